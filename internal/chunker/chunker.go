@@ -3,6 +3,7 @@ package chunker
 
 import (
 	"strings"
+	"unicode/utf8"
 )
 
 const (
@@ -11,11 +12,13 @@ const (
 	DefaultMaxSize    = 600
 )
 
-// Options configures chunking behavior.
+// Options configures chunking behavior. Sizes are measured in characters
+// (runes), not bytes.
 type Options struct {
 	TargetSize int
 	MinSize    int
 	MaxSize    int
+	Overlap    int // chars of the previous chunk repeated at the start of the next
 }
 
 // DefaultOptions returns default chunking options.
@@ -34,28 +37,59 @@ type ChunkResult struct {
 	EndLine   int
 }
 
-// Chunk splits text into chunks. Short text (<= maxSize) returns a single chunk.
+// Chunk splits markdown text into chunks. Short text (<= maxSize) returns a single chunk.
 func Chunk(text string, opts Options) []ChunkResult {
-	if opts.TargetSize == 0 {
-		opts = DefaultOptions()
-	}
-
-	text = strings.TrimSpace(text)
-	if len(text) == 0 {
-		return nil
-	}
-
 	// Short content — no chunking needed
-	if len(text) <= opts.MaxSize {
-		lines := strings.Count(text, "\n")
-		return []ChunkResult{{Text: text, StartLine: 1, EndLine: lines + 1}}
+	text, opts, short := prepare(text, opts)
+	if short != nil || text == "" {
+		return short
 	}
 
 	// Split into blocks on markdown boundaries
 	blocks := splitBlocks(text)
 
 	// Merge small blocks, split large ones, targeting opts.TargetSize
-	return mergeBlocks(blocks, opts)
+	results := mergeBlocks(blocks, opts)
+	if opts.Overlap > 0 {
+		results = addOverlap(results, opts.Overlap)
+	}
+	return results
+}
+
+// addOverlap prefixes each chunk with the tail of the chunk before it, so
+// text straddling a boundary is searchable from either side. The tail is
+// trimmed forward to a word boundary.
+func addOverlap(chunks []ChunkResult, overlap int) []ChunkResult {
+	out := make([]ChunkResult, len(chunks))
+	copy(out, chunks)
+	for i := 1; i < len(chunks); i++ {
+		tail := overlapTail(chunks[i-1].Text, overlap)
+		if tail == "" {
+			continue
+		}
+		out[i].Text = tail + "\n" + chunks[i].Text
+		out[i].StartLine = chunks[i-1].EndLine - strings.Count(tail, "\n")
+	}
+	return out
+}
+
+// overlapTail returns roughly the last n chars of text, starting on a word
+// boundary.
+func overlapTail(text string, n int) string {
+	runes := []rune(text)
+	if n >= len(runes) {
+		return text
+	}
+	tail := string(runes[len(runes)-n:])
+	if i := strings.IndexAny(tail, " \t\n"); i >= 0 {
+		tail = tail[i:]
+	}
+	return strings.TrimSpace(tail)
+}
+
+// size returns the length of s in characters.
+func size(s string) int {
+	return utf8.RuneCountInString(s)
 }
 
 // block is an intermediate representation of a text section.
@@ -65,7 +99,9 @@ type block struct {
 	endLine   int
 }
 
-// splitBlocks splits text on heading lines and double newlines.
+// splitBlocks splits text on heading lines and double newlines. Fenced code
+// blocks (``` or ~~~) are never split, so blank lines and # comments inside
+// code stay with the code.
 func splitBlocks(text string) []block {
 	lines := strings.Split(text, "\n")
 	var blocks []block
@@ -85,10 +121,25 @@ func splitBlocks(text string) []block {
 	}
 
 	prevEmpty := false
+	fence := "" // opening fence marker while inside a code block
 	for i, line := range lines {
 		lineNum := i + 1
 		trimmed := strings.TrimSpace(line)
 
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			current = append(current, line)
+			continue
+		}
+		if f := fenceMarker(trimmed); f != "" {
+			fence = f
+			prevEmpty = false
+			current = append(current, line)
+			continue
+		}
+
 		// Split on headings
 		if strings.HasPrefix(trimmed, "#") && len(current) > 0 {
 			flush(lineNum - 1)
@@ -111,6 +162,16 @@ func splitBlocks(text string) []block {
 	return blocks
 }
 
+// fenceMarker returns the fence that opens a code block on this line, or "".
+func fenceMarker(trimmed string) string {
+	for _, f := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, f) {
+			return f
+		}
+	}
+	return ""
+}
+
 // mergeBlocks combines small blocks and splits oversized ones.
 func mergeBlocks(blocks []block, opts Options) []ChunkResult {
 	var results []ChunkResult
@@ -122,7 +183,7 @@ func mergeBlocks(blocks []block, opts Options) []ChunkResult {
 			return
 		}
 		// If accumulated block is too large, hard-split it
-		if len(t) > opts.MaxSize {
+		if size(t) > opts.MaxSize {
 			results = append(results, hardSplit(t, accum.startLine, opts)...)
 		} else {
 			lines := strings.Count(t, "\n")
@@ -138,7 +199,7 @@ func mergeBlocks(blocks []block, opts Options) []ChunkResult {
 		}
 
 		combined := accum.text + "\n\n" + b.text
-		if len(combined) <= opts.TargetSize {
+		if size(combined) <= opts.TargetSize {
 			accum.text = combined
 			accum.endLine = b.endLine
 		} else {
@@ -151,31 +212,37 @@ func mergeBlocks(blocks []block, opts Options) []ChunkResult {
 	return results
 }
 
-// hardSplit breaks text that exceeds maxSize on line boundaries.
+// hardSplit breaks text that exceeds maxSize on line boundaries. A single
+// line longer than maxSize is cut into target-sized pieces on rune boundaries.
 func hardSplit(text string, startLine int, opts Options) []ChunkResult {
 	lines := strings.Split(text, "\n")
 	var results []ChunkResult
 	var current []string
 	curStart := startLine
+	curEnd := startLine
 	curLen := 0
 
 	for i, line := range lines {
-		lineLen := len(line)
-		if curLen+lineLen > opts.TargetSize && len(current) > 0 {
-			t := strings.TrimSpace(strings.Join(current, "\n"))
-			if t != "" {
-				results = append(results, ChunkResult{
-					Text:      t,
-					StartLine: curStart,
-					EndLine:   startLine + i - 1,
-				})
+		lineNum := startLine + i
+		for _, piece := range splitLongLine(line, opts) {
+			pieceLen := size(piece)
+			if curLen+pieceLen > opts.TargetSize && len(current) > 0 {
+				t := strings.TrimSpace(strings.Join(current, "\n"))
+				if t != "" {
+					results = append(results, ChunkResult{
+						Text:      t,
+						StartLine: curStart,
+						EndLine:   curEnd,
+					})
+				}
+				current = nil
+				curStart = lineNum
+				curLen = 0
 			}
-			current = nil
-			curStart = startLine + i
-			curLen = 0
+			current = append(current, piece)
+			curEnd = lineNum
+			curLen += pieceLen + 1 // +1 for newline
 		}
-		current = append(current, line)
-		curLen += lineLen + 1 // +1 for newline
 	}
 
 	if len(current) > 0 {
@@ -191,3 +258,18 @@ func hardSplit(text string, startLine int, opts Options) []ChunkResult {
 
 	return results
 }
+
+// splitLongLine cuts a line longer than opts.MaxSize into pieces of at most
+// opts.TargetSize chars. Shorter lines are returned as is.
+func splitLongLine(line string, opts Options) []string {
+	if size(line) <= opts.MaxSize {
+		return []string{line}
+	}
+	runes := []rune(line)
+	var pieces []string
+	for len(runes) > opts.TargetSize {
+		pieces = append(pieces, string(runes[:opts.TargetSize]))
+		runes = runes[opts.TargetSize:]
+	}
+	return append(pieces, string(runes))
+}
@@ -3,6 +3,7 @@ package chunker
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestChunk_EmptyInput(t *testing.T) {
@@ -84,3 +85,111 @@ func TestChunk_DoubleNewlineSplit(t *testing.T) {
 		t.Fatalf("expected at least 2 chunks from paragraph splits, got %d", len(result))
 	}
 }
+
+func TestChunk_KeepsFencedCodeTogether(t *testing.T) {
+	code := "```go\nfunc main() {\n\tx := 1\n\n\n\t# not a heading\n\n\n\tfmt.Println(x)\n}\n```"
+	text := "# Setup\n" + strings.Repeat("Intro paragraph about the program. ", 10) +
+		"\n\n\n" + code + "\n\n\n# Notes\n" + strings.Repeat("Trailing notes about usage. ", 15)
+
+	result := Chunk(text, Options{TargetSize: 150, MinSize: 50, MaxSize: 300})
+	found := false
+	for _, r := range result {
+		if strings.Contains(r.Text, "```go") {
+			found = true
+			if !strings.Contains(r.Text, code) {
+				t.Errorf("code block split across chunks: %q", r.Text)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("code block missing from chunks")
+	}
+}
+
+func TestChunk_HardSplitsOversizedCode(t *testing.T) {
+	var lines []string
+	for i := 0; i < 60; i++ {
+		lines = append(lines, "\tstatement := compute(value, other)")
+		if i%10 == 0 {
+			lines = append(lines, "", "")
+		}
+	}
+	text := "```\n" + strings.Join(lines, "\n") + "\n```"
+
+	opts := Options{TargetSize: 400, MinSize: 100, MaxSize: 600}
+	result := Chunk(text, opts)
+	if len(result) < 2 {
+		t.Fatalf("expected oversized code to be split, got %d chunks", len(result))
+	}
+	for _, r := range result {
+		if len(r.Text) > opts.MaxSize {
+			t.Errorf("chunk exceeds max size: %d", len(r.Text))
+		}
+	}
+}
+
+func TestChunk_Overlap(t *testing.T) {
+	var sections []string
+	for i := 0; i < 4; i++ {
+		sections = append(sections, "## Section\n"+strings.Repeat("filler words for this section ", 8)+"boundary marker "+string(rune('A'+i)))
+	}
+	text := strings.Join(sections, "\n\n\n")
+	opts := Options{TargetSize: 200, MinSize: 50, MaxSize: 300}
+
+	plain := Chunk(text, opts)
+	if len(plain) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(plain))
+	}
+
+	opts.Overlap = 40
+	overlapped := Chunk(text, opts)
+	if len(overlapped) != len(plain) {
+		t.Fatalf("overlap changed chunk count: %d vs %d", len(overlapped), len(plain))
+	}
+	if overlapped[0].Text != plain[0].Text {
+		t.Error("first chunk should be unchanged")
+	}
+	for i := 1; i < len(plain); i++ {
+		// The tail of the previous chunk appears at the head of the next
+		prevTail := plain[i-1].Text[len(plain[i-1].Text)-16:]
+		if !strings.Contains(overlapped[i].Text, prevTail) {
+			t.Errorf("chunk %d missing overlap %q: %q", i, prevTail, overlapped[i].Text)
+		}
+		if !strings.HasSuffix(overlapped[i].Text, plain[i].Text) {
+			t.Errorf("chunk %d lost its own text", i)
+		}
+		if overlapped[i].StartLine > plain[i].StartLine {
+			t.Errorf("chunk %d StartLine %d should not move forward", i, overlapped[i].StartLine)
+		}
+	}
+}
+
+func TestChunk_MultibyteSizes(t *testing.T) {
+	// 300 CJK chars per paragraph is 900 bytes, but fits within MaxSize chars
+	para := strings.Repeat("日本語の文章", 50)
+	result := Chunk(para, DefaultOptions())
+	if len(result) != 1 {
+		t.Errorf("expected 300-char CJK text to be one chunk, got %d", len(result))
+	}
+
+	// A single emoji line far past MaxSize must be cut on rune boundaries
+	line := strings.Repeat("🚀✨", 700)
+	opts := DefaultOptions()
+	result = Chunk(line, opts)
+	if len(result) < 3 {
+		t.Fatalf("expected long line to be split, got %d chunks", len(result))
+	}
+	var rebuilt strings.Builder
+	for _, r := range result {
+		if !utf8.ValidString(r.Text) {
+			t.Fatalf("chunk contains broken runes: %q", r.Text)
+		}
+		if n := utf8.RuneCountInString(r.Text); n > opts.MaxSize {
+			t.Errorf("chunk has %d chars, max %d", n, opts.MaxSize)
+		}
+		rebuilt.WriteString(r.Text)
+	}
+	if rebuilt.String() != line {
+		t.Error("split pieces do not reassemble the original line")
+	}
+}
@@ -0,0 +1,121 @@
+package chunker
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Section is a heading and the text under it, up to the next heading of the
+// same or a shallower level.
+type Section struct {
+	Heading   string // heading text without the leading #s; "" for a preamble
+	Level     int    // 1-6; 0 for a preamble
+	Text      string // including the heading line
+	StartLine int
+	EndLine   int
+}
+
+// Sections splits markdown at headings of the given level (1-6) and
+// shallower. Level 0 picks the shallowest level that occurs more than once,
+// so a document with a single title splits at its subsections. Text before
+// the first heading becomes a preamble section. Headings inside fenced code
+// blocks are ignored.
+func Sections(text string, level int) []Section {
+	lines := strings.Split(text, "\n")
+	levels := make([]int, len(lines))
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if f := fenceMarker(trimmed); f != "" {
+			fence = f
+			continue
+		}
+		levels[i] = headingLevel(line)
+	}
+	if level <= 0 {
+		level = splitLevel(levels)
+	}
+
+	var sections []Section
+	start := 0
+	flush := func(end int) {
+		body := strings.TrimSpace(strings.Join(lines[start:end], "\n"))
+		if body == "" {
+			return
+		}
+		sec := Section{Text: body, StartLine: start + 1, EndLine: end}
+		if lv := levels[start]; lv > 0 && lv <= level {
+			sec.Level = lv
+			sec.Heading = strings.TrimSpace(strings.Trim(strings.TrimSpace(lines[start]), "#"))
+		}
+		sections = append(sections, sec)
+	}
+	for i, lv := range levels {
+		if lv > 0 && lv <= level && i > start {
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(lines))
+	return sections
+}
+
+// headingLevel returns the level of an ATX heading line, or 0.
+func headingLevel(line string) int {
+	if strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+		return 0 // indented code
+	}
+	t := strings.TrimLeft(line, " ")
+	n := 0
+	for n < len(t) && t[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || (n < len(t) && t[n] != ' ' && t[n] != '\t') {
+		return 0
+	}
+	return n
+}
+
+// splitLevel returns the shallowest heading level used more than once, or
+// the shallowest used at all.
+func splitLevel(levels []int) int {
+	var counts [7]int
+	for _, lv := range levels {
+		counts[lv]++
+	}
+	for lv := 1; lv <= 6; lv++ {
+		if counts[lv] > 1 {
+			return lv
+		}
+	}
+	for lv := 1; lv <= 6; lv++ {
+		if counts[lv] > 0 {
+			return lv
+		}
+	}
+	return 0
+}
+
+// Slug turns a heading into a lowercase, hyphen-separated key fragment.
+func Slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package chunker
+
+import "testing"
+
+func TestSections(t *testing.T) {
+	text := "# Title\n\nIntro.\n\n## Setup\n\nInstall it.\n\n```sh\n## not a heading\n```\n\n### Details\n\nMore.\n\n## Usage\n\nRun it."
+
+	got := Sections(text, 0)
+	want := []struct {
+		heading string
+		start   int
+	}{{"Title", 1}, {"Setup", 5}, {"Usage", 17}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d sections, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Heading != w.heading || got[i].StartLine != w.start {
+			t.Errorf("section %d = %q at line %d, want %q at %d", i, got[i].Heading, got[i].StartLine, w.heading, w.start)
+		}
+	}
+	if got[1].Level != 2 || got[1].EndLine != 16 {
+		t.Errorf("expected Setup to be level 2 ending at line 16, got %+v", got[1])
+	}
+
+	if got := Sections(text, 3); len(got) != 4 || got[2].Heading != "Details" {
+		t.Errorf("expected level 3 to split out Details, got %+v", got)
+	}
+	if got := Sections("Preamble.\n\n# One\n\nx", 0); len(got) != 2 || got[0].Level != 0 || got[1].Heading != "One" {
+		t.Errorf("expected a preamble and one section, got %+v", got)
+	}
+	if got := Sections("no headings", 0); len(got) != 1 || got[0].Level != 0 {
+		t.Errorf("expected one preamble section, got %+v", got)
+	}
+	if got := Sections("#hashtag\n\n# Real", 0); len(got) != 2 || got[1].Heading != "Real" {
+		t.Errorf("expected #hashtag not to be a heading, got %+v", got)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Getting Started":       "getting-started",
+		"  API: v2 (beta)!  ":   "api-v2-beta",
+		"Déjà vu":               "déjà-vu",
+		"---":                   "",
+		"Step 1 -- Install Go.": "step-1-install-go",
+	}
+	for in, want := range tests {
+		if got := Slug(in); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
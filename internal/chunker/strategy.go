@@ -0,0 +1,134 @@
+package chunker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Chunker is a chunking strategy.
+type Chunker interface {
+	Chunk(text string, opts Options) []ChunkResult
+}
+
+// MarkdownChunker splits on headings and blank lines, keeping fenced code
+// blocks intact. It is the default strategy.
+type MarkdownChunker struct{}
+
+// Chunk implements Chunker.
+func (MarkdownChunker) Chunk(text string, opts Options) []ChunkResult {
+	return Chunk(text, opts)
+}
+
+// PlainChunker cuts text into fixed windows of opts.TargetSize chars,
+// ignoring structure. Consecutive windows share opts.Overlap chars.
+type PlainChunker struct{}
+
+// Chunk implements Chunker.
+func (PlainChunker) Chunk(text string, opts Options) []ChunkResult {
+	text, opts, short := prepare(text, opts)
+	if short != nil || text == "" {
+		return short
+	}
+
+	runes := []rune(text)
+	step := opts.TargetSize - opts.Overlap
+	if step <= 0 {
+		step = opts.TargetSize
+	}
+
+	var results []ChunkResult
+	for start := 0; start < len(runes); start += step {
+		end := start + opts.TargetSize
+		if end > len(runes) {
+			end = len(runes)
+		}
+		before := string(runes[:start])
+		window := string(runes[start:end])
+		startLine := strings.Count(before, "\n") + 1
+		if t := strings.TrimSpace(window); t != "" {
+			results = append(results, ChunkResult{
+				Text:      t,
+				StartLine: startLine,
+				EndLine:   startLine + strings.Count(window, "\n"),
+			})
+		}
+		if end == len(runes) {
+			break
+		}
+	}
+	return results
+}
+
+// LineChunker packs whole lines into chunks of about opts.TargetSize chars.
+// It suits logs, JSON lines and source files where headings and blank lines
+// carry no meaning.
+type LineChunker struct{}
+
+// Chunk implements Chunker.
+func (LineChunker) Chunk(text string, opts Options) []ChunkResult {
+	text, opts, short := prepare(text, opts)
+	if short != nil || text == "" {
+		return short
+	}
+
+	results := hardSplit(text, 1, opts)
+	if opts.Overlap > 0 {
+		results = addOverlap(results, opts.Overlap)
+	}
+	return results
+}
+
+// prepare applies defaults and trims text. Text that fits in opts.MaxSize is
+// returned as a single chunk in short.
+func prepare(text string, opts Options) (string, Options, []ChunkResult) {
+	if opts.TargetSize == 0 {
+		opts = DefaultOptions()
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", opts, nil
+	}
+	if size(text) <= opts.MaxSize {
+		lines := strings.Count(text, "\n")
+		return text, opts, []ChunkResult{{Text: text, StartLine: 1, EndLine: lines + 1}}
+	}
+	return text, opts, nil
+}
+
+// Formats accepted by ForFormat.
+const (
+	FormatMarkdown = "markdown"
+	FormatPlain    = "plain"
+	FormatLines    = "lines"
+)
+
+// ForFormat returns the chunker for a content format. An empty format
+// selects markdown.
+func ForFormat(format string) (Chunker, error) {
+	switch strings.ToLower(format) {
+	case "", FormatMarkdown, "md":
+		return MarkdownChunker{}, nil
+	case FormatPlain, "text":
+		return PlainChunker{}, nil
+	case FormatLines, "code", "log", "json":
+		return LineChunker{}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want markdown, plain, or lines)", format)
+	}
+}
+
+// Detect guesses the format of text. JSON and JSON lines are chunked by
+// line; everything else, including markdown that opens with a [link], is
+// treated as markdown.
+func Detect(text string) string {
+	t := strings.TrimSpace(text)
+	if !strings.HasPrefix(t, "{") && !strings.HasPrefix(t, "[") {
+		return FormatMarkdown
+	}
+	first, _, _ := strings.Cut(t, "\n")
+	if json.Valid([]byte(t)) || json.Valid([]byte(first)) {
+		return FormatLines
+	}
+	return FormatMarkdown
+}
@@ -0,0 +1,95 @@
+package chunker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlainChunker_FixedWindows(t *testing.T) {
+	text := strings.Repeat("abcdefghij", 100) // 1000 chars, no structure
+	opts := Options{TargetSize: 300, MinSize: 50, MaxSize: 400}
+
+	result := PlainChunker{}.Chunk(text, opts)
+	if len(result) != 4 {
+		t.Fatalf("expected 4 windows, got %d", len(result))
+	}
+	for i, r := range result[:3] {
+		if len(r.Text) != 300 {
+			t.Errorf("window %d: expected 300 chars, got %d", i, len(r.Text))
+		}
+	}
+
+	opts.Overlap = 50
+	overlapped := PlainChunker{}.Chunk(text, opts)
+	if len(overlapped) < len(result) {
+		t.Errorf("overlap should not reduce windows, got %d", len(overlapped))
+	}
+	prev := overlapped[0].Text
+	if !strings.HasPrefix(overlapped[1].Text, prev[len(prev)-50:]) {
+		t.Error("expected consecutive windows to share 50 chars")
+	}
+}
+
+func TestLineChunker_IgnoresMarkdown(t *testing.T) {
+	var lines []string
+	for i := 0; i < 40; i++ {
+		lines = append(lines, `{"level":"info","msg":"request handled","status":200}`)
+		if i%5 == 0 {
+			lines = append(lines, "# not a heading", "", "")
+		}
+	}
+	text := strings.Join(lines, "\n")
+
+	result := LineChunker{}.Chunk(text, DefaultOptions())
+	if len(result) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(result))
+	}
+	for _, r := range result {
+		if size(r.Text) > DefaultMaxSize {
+			t.Errorf("chunk exceeds max size: %d", size(r.Text))
+		}
+		for _, l := range strings.Split(r.Text, "\n") {
+			if l != "" && l != "# not a heading" && l != lines[0] {
+				t.Errorf("line was split: %q", l)
+			}
+		}
+	}
+}
+
+func TestForFormat(t *testing.T) {
+	tests := map[string]Chunker{
+		"":         MarkdownChunker{},
+		"markdown": MarkdownChunker{},
+		"plain":    PlainChunker{},
+		"lines":    LineChunker{},
+		"JSON":     LineChunker{},
+	}
+	for format, want := range tests {
+		got, err := ForFormat(format)
+		if err != nil {
+			t.Errorf("%q: %v", format, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%q: got %T, want %T", format, got, want)
+		}
+	}
+	if _, err := ForFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	if got := Detect(`{"a":1}` + "\n" + `{"a":2}`); got != FormatLines {
+		t.Errorf("json lines: got %q", got)
+	}
+	if got := Detect("# Notes\n\nSome prose."); got != FormatMarkdown {
+		t.Errorf("markdown: got %q", got)
+	}
+	if got := Detect("[\n  1,\n  2\n]"); got != FormatLines {
+		t.Errorf("pretty-printed json: got %q", got)
+	}
+	if got := Detect("[Design doc](https://example.com/design)\n\n## Goals\n\nShip it."); got != FormatMarkdown {
+		t.Errorf("markdown opening with a link: got %q", got)
+	}
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Write a consistent single-file snapshot of the database",
+		Long: `Copy the database to a single file with VACUUM INTO. Unlike copying the
+.db file, the snapshot is consistent even while other processes are writing
+and includes everything still in the write-ahead log.`,
+		Run: runBackup,
+	}
+
+	cmd.Flags().StringP("out", "o", "", "Snapshot file to write (required)")
+	cmd.Flags().Bool("force", false, "Replace the snapshot file if it exists")
+	cmd.MarkFlagRequired("out")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runBackup(cmd *cobra.Command, args []string) {
+	out, _ := cmd.Flags().GetString("out")
+	force, _ := cmd.Flags().GetBool("force")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.Backup(cmd.Context(), out, force)
+	if err != nil {
+		exitErr("backup", err)
+	}
+
+	printJSON(result)
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rcliao/agent-memory/internal/model"
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "chunks",
+		Short: "Show how a stored memory was chunked for search",
+		Long: `Print the search chunks of a memory version: sequence, line range, text,
+and whether the chunk has an embedding. Defaults to the latest version; use
+-v for an older one or --id for an exact row. Does not count as an access.`,
+		Run: runChunks,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Namespace")
+	cmd.Flags().StringP("key", "k", "", "Key")
+	cmd.Flags().IntP("version", "v", 0, "Specific version (default latest)")
+	cmd.Flags().String("id", "", "Memory row ID (instead of --ns/--key)")
+
+	cmd.MarkFlagsOneRequired("key", "id")
+	cmd.MarkFlagsMutuallyExclusive("id", "key")
+	cmd.MarkFlagsMutuallyExclusive("id", "ns")
+	cmd.MarkFlagsMutuallyExclusive("id", "version")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runChunks(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	key, _ := cmd.Flags().GetString("key")
+	version, _ := cmd.Flags().GetInt("version")
+	id, _ := cmd.Flags().GetString("id")
+
+	if key != "" && ns == "" {
+		exitErr("chunks", fmt.Errorf("--ns is required with --key"))
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	if id == "" {
+		// History reads skip access tracking; pick the wanted version from it.
+		versions, err := s.Get(cmd.Context(), store.GetParams{NS: ns, Key: key, History: true})
+		if err != nil {
+			exitErr("chunks", err)
+		}
+		id = versions[0].ID
+		if version > 0 {
+			id = ""
+			for _, m := range versions {
+				if m.Version == version {
+					id = m.ID
+				}
+			}
+			if id == "" {
+				exitErr("chunks", fmt.Errorf("memory not found: %s/%s v%d", ns, key, version))
+			}
+		}
+	} else if _, err := s.GetByID(cmd.Context(), id); err != nil {
+		exitErr("chunks", err)
+	}
+
+	chunks, err := s.GetChunks(cmd.Context(), id)
+	if err != nil {
+		exitErr("chunks", err)
+	}
+
+	if formatFlag == "text" {
+		for _, c := range chunks {
+			printChunkText(c)
+		}
+		return
+	}
+	printJSON(chunks)
+}
+
+func printChunkText(c model.Chunk) {
+	embedded := ""
+	if c.HasEmbedding {
+		embedded = " (embedded)"
+	}
+	fmt.Fprintf(os.Stdout, "--- chunk %d, lines %d-%d, %d chars%s\n%s\n",
+		c.Seq, c.StartLine, c.EndLine, len([]rune(c.Text)), embedded, c.Text)
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:     "compact",
+		Aliases: []string{"vacuum"},
+		Short:   "Reclaim unused space in the database",
+		Long:    "Run VACUUM and truncate the write-ahead log, reporting file sizes before and after.",
+		Run:     runCompact,
+	}
+
+	cmd.Flags().Bool("wal-checkpoint", true, "Also checkpoint and truncate the WAL file")
+	addYesFlag(cmd)
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runCompact(cmd *cobra.Command, args []string) {
+	checkpoint, _ := cmd.Flags().GetBool("wal-checkpoint")
+
+	confirm(cmd, "rewrite the database with VACUUM (it is locked while this runs)", false)
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.Compact(cmd.Context(), checkpoint)
+	if err != nil {
+		exitErr("compact", err)
+	}
+
+	printJSON(result)
+}
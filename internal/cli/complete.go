@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cobra.OnInitialize(registerCompletions)
+}
+
+// completionTimeout bounds how long a completion may spend in the database.
+const completionTimeout = 500 * time.Millisecond
+
+// registerCompletions attaches namespace and key completion to every
+// command with ns/key flags. It runs once all commands are registered.
+func registerCompletions() {
+	pairs := [][2]string{{"ns", "key"}, {"from-ns", "from-key"}, {"to-ns", "to-key"}}
+	var walk func(*cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, p := range pairs {
+			if cmd.Flags().Lookup(p[0]) != nil {
+				cmd.RegisterFlagCompletionFunc(p[0], completeNamespaces)
+			}
+			if cmd.Flags().Lookup(p[1]) != nil {
+				cmd.RegisterFlagCompletionFunc(p[1], completeKeys(p[0]))
+			}
+		}
+		for _, c := range cmd.Commands() {
+			walk(c)
+		}
+	}
+	walk(RootCmd)
+}
+
+// completionStore opens the database read-only, or returns nil if it does
+// not exist yet.
+func completionStore() *store.SQLiteStore {
+	path := getDBPath()
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	s, err := store.NewReadOnlyStore(path)
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+func completeNamespaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	s := completionStore()
+	if s == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	rows, err := s.ListNamespaces(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var out []string
+	for _, r := range rows {
+		if strings.HasPrefix(r.NS, toComplete) {
+			out = append(out, r.NS)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeKeys completes keys within the namespace given by nsFlag, falling
+// back to the configured default namespace.
+func completeKeys(nsFlag string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		ns, _ := cmd.Flags().GetString(nsFlag)
+		if ns == "" {
+			if c, err := loadConfig(); err == nil {
+				ns = c.NS
+			}
+		}
+		if ns == "" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		s := completionStore()
+		if s == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		defer s.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+		keys, err := s.ListKeys(ctx, ns)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var out []string
+		for _, k := range keys {
+			if strings.HasPrefix(k, toComplete) {
+				out = append(out, k)
+			}
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// config holds per-user defaults for common flags. Values come from
+// ~/.agent-memory/config.json (or $AGENT_MEMORY_CONFIG), overridden by
+// $AGENT_MEMORY_DEFAULT_* variables, overridden by explicit flags.
+type config struct {
+	NS       string `json:"ns"`
+	Kind     string `json:"kind"`
+	Priority string `json:"priority"`
+	Budget   int    `json:"budget"`
+
+	// AllowedNS limits writes to these namespaces (exact names or globs
+	// like "project:*"); NSRequired makes put ignore the default ns.
+	AllowedNS  []string `json:"allowed_ns"`
+	NSRequired bool     `json:"ns_required"`
+}
+
+// loadedConfig is the config applied to the running command.
+var loadedConfig config
+
+func configPath() string {
+	if env := os.Getenv("AGENT_MEMORY_CONFIG"); env != "" {
+		return env
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".agent-memory", "config.json")
+}
+
+func loadConfig() (config, error) {
+	var c config
+	path := configPath()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return c, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &c); err != nil {
+			return c, fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	if v := os.Getenv("AGENT_MEMORY_DEFAULT_NS"); v != "" {
+		c.NS = v
+	}
+	if v := os.Getenv("AGENT_MEMORY_DEFAULT_KIND"); v != "" {
+		c.Kind = v
+	}
+	if v := os.Getenv("AGENT_MEMORY_DEFAULT_PRIORITY"); v != "" {
+		c.Priority = v
+	}
+	if v := os.Getenv("AGENT_MEMORY_DEFAULT_BUDGET"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return c, fmt.Errorf("invalid AGENT_MEMORY_DEFAULT_BUDGET %q", v)
+		}
+		c.Budget = n
+	}
+	if v := os.Getenv("AGENT_MEMORY_ALLOWED_NS"); v != "" {
+		c.AllowedNS = nil
+		for _, ns := range strings.Split(v, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				c.AllowedNS = append(c.AllowedNS, ns)
+			}
+		}
+	}
+	if v := os.Getenv("AGENT_MEMORY_NS_REQUIRED"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return c, fmt.Errorf("invalid AGENT_MEMORY_NS_REQUIRED %q", v)
+		}
+		c.NSRequired = b
+	}
+	return c, nil
+}
+
+// applyConfig fills flags the user did not set from the loaded defaults.
+// Kind and priority only default on put, where they describe what is
+// written; elsewhere they are filters and stay empty.
+func applyConfig(cmd *cobra.Command, args []string) {
+	c, err := loadConfig()
+	if err != nil {
+		exitErr("config", err)
+	}
+	loadedConfig = c
+	if c.NSRequired {
+		nsRequired = true
+	}
+
+	defaults := map[string]string{"ns": c.NS}
+	if nsRequired && cmd.Name() == "put" {
+		// Writes must name their namespace explicitly
+		delete(defaults, "ns")
+	}
+	if cmd.Name() == "rm" && cmd.HasParent() && cmd.Parent().Name() == "ns" {
+		// Deleting a whole namespace must never fall back to the default
+		delete(defaults, "ns")
+	}
+	if c.Budget > 0 {
+		defaults["budget"] = strconv.Itoa(c.Budget)
+	}
+	if cmd.Name() == "put" {
+		defaults["kind"] = c.Kind
+		defaults["priority"] = c.Priority
+	}
+
+	for name, value := range defaults {
+		f := cmd.Flags().Lookup(name)
+		if value == "" || f == nil || f.Changed {
+			continue
+		}
+		if err := f.Value.Set(value); err != nil {
+			exitErr("config", fmt.Errorf("default %s: %w", name, err))
+		}
+		// Satisfy required-flag checks without counting as explicitly set
+		if _, required := f.Annotations[cobra.BashCompOneRequiredFlag]; required {
+			f.Changed = true
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// addYesFlag registers --yes/-y on a destructive command.
+func addYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
+
+// confirm asks the user to approve action when stdin is a terminal, exiting
+// if they decline. --yes skips the prompt. Without a terminal, the command
+// proceeds unless requireYes is set, in which case --yes is mandatory so a
+// script cannot destroy data by accident.
+func confirm(cmd *cobra.Command, action string, requireYes bool) {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return
+	}
+
+	fd := os.Stdin.Fd()
+	if !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd) {
+		if requireYes {
+			exitErr(strings.TrimPrefix(cmd.CommandPath(), RootCmd.Name()+" "), fmt.Errorf("refusing to %s without a terminal; pass --yes to confirm", action))
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "%s? [y/N] ", capitalize(action))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return
+	}
+	fmt.Fprintln(os.Stderr, "aborted")
+	os.Exit(1)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
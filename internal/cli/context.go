@@ -1,11 +1,12 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/rcliao/agent-memory/internal/summarize"
 	"github.com/spf13/cobra"
 )
 
@@ -22,6 +23,9 @@ func init() {
 	cmd.Flags().String("kind", "", "Filter by kind")
 	cmd.Flags().StringSliceP("tags", "t", nil, "Filter by tags")
 	cmd.Flags().IntP("budget", "b", 4000, "Max tokens in output")
+	cmd.Flags().Float64("min-score", 0, "Skip memories scoring below this (0-1)")
+	cmd.Flags().Bool("summarize", false, "Compress memories that don't fit into briefs ($AGENT_MEMORY_SUMMARIZE_PROVIDER)")
+	cmd.Flags().String("tokenizer", "chars", "Token estimator: chars (4 chars/token) or words (1.3 tokens/word, CJK per char)")
 
 	RootCmd.AddCommand(cmd)
 }
@@ -31,8 +35,24 @@ func runContext(cmd *cobra.Command, args []string) {
 	kind, _ := cmd.Flags().GetString("kind")
 	tags, _ := cmd.Flags().GetStringSlice("tags")
 	budget, _ := cmd.Flags().GetInt("budget")
+	tokenizer, _ := cmd.Flags().GetString("tokenizer")
+	minScore, _ := cmd.Flags().GetFloat64("min-score")
+	summarizeOn, _ := cmd.Flags().GetBool("summarize")
 	query := strings.Join(args, " ")
 
+	counter, err := store.TokenCounterByName(tokenizer)
+	if err != nil {
+		exitErr("context", err)
+	}
+
+	var summarizer summarize.Summarizer
+	if summarizeOn {
+		summarizer = summarize.NewFromEnv()
+		if _, ok := summarizer.(summarize.Noop); ok {
+			fmt.Fprintln(os.Stderr, "note: no summarizer configured (set AGENT_MEMORY_SUMMARIZE_PROVIDER); memories that don't fit are excerpted")
+		}
+	}
+
 	s, err := openStore()
 	if err != nil {
 		exitErr("open store", err)
@@ -40,16 +60,42 @@ func runContext(cmd *cobra.Command, args []string) {
 	defer s.Close()
 
 	result, err := s.Context(cmd.Context(), store.ContextParams{
-		NS:     ns,
-		Query:  query,
-		Kind:   kind,
-		Tags:   tags,
-		Budget: budget,
+		NS:       ns,
+		Query:    query,
+		Kind:     kind,
+		Tags:     tags,
+		Budget:   budget,
+		Counter:  counter,
+		MinScore: minScore,
+
+		Summarizer: summarizer,
 	})
 	if err != nil {
 		exitErr("context", err)
 	}
 
-	b, _ := json.MarshalIndent(result, "", "  ")
-	fmt.Println(string(b))
+	if formatFlag == "md" || formatFlag == "markdown" {
+		fmt.Print(renderContextMarkdown(result))
+		return
+	}
+
+	printJSON(result)
+}
+
+// renderContextMarkdown renders assembled context as markdown sections, one
+// per memory, ready to paste into a prompt.
+func renderContextMarkdown(r *store.ContextResult) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<!-- context: %d memories, %d/%d tokens -->\n", len(r.Memories), r.Used, r.Budget)
+	for _, m := range r.Memories {
+		fmt.Fprintf(&sb, "\n## %s/%s", m.NS, m.Key)
+		switch {
+		case m.Summary:
+			sb.WriteString(" (summary)")
+		case m.Excerpt:
+			sb.WriteString(" (excerpt)")
+		}
+		fmt.Fprintf(&sb, "\n\n%s\n", m.Content)
+	}
+	return sb.String()
 }
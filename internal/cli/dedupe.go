@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Find near-identical memories stored under different keys",
+		Long: `Cluster the latest versions of memories whose embeddings are at least
+--threshold similar and report each cluster with the memory that would be kept
+(highest priority, then newest). With --link, each duplicate is linked to the
+kept memory with relates_to; with --rm, every version of each duplicate is
+soft-deleted (restorable with undelete --all-versions). --dry-run shows the
+clusters without changing anything.
+
+Without an embedding provider only memories with identical content are
+clustered.`,
+		Run: runDedupe,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Only consider this namespace")
+	cmd.Flags().Float64("threshold", store.DefaultDedupeThreshold, "Similarity at or above which memories are duplicates")
+	cmd.Flags().Bool("link", false, "Link each duplicate to the kept memory with relates_to")
+	cmd.Flags().Bool("rm", false, "Soft-delete every duplicate, keeping one memory per cluster")
+	cmd.Flags().Bool("dry-run", false, "Report clusters without linking or deleting")
+	addYesFlag(cmd)
+
+	cmd.MarkFlagsMutuallyExclusive("link", "rm")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runDedupe(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	link, _ := cmd.Flags().GetBool("link")
+	rm, _ := cmd.Flags().GetBool("rm")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if threshold <= 0 || threshold > 1 {
+		exitErr("dedupe", fmt.Errorf("--threshold must be in (0, 1]"))
+	}
+
+	action := store.DedupeReport
+	switch {
+	case link:
+		action = store.DedupeLink
+	case rm:
+		action = store.DedupeRm
+		if !dryRun {
+			scope := "all namespaces"
+			if ns != "" {
+				scope = "namespace " + ns
+			}
+			confirm(cmd, "soft-delete duplicate memories in "+scope, false)
+		}
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.Dedupe(cmd.Context(), store.DedupeParams{
+		NS:        ns,
+		Threshold: threshold,
+		Action:    action,
+		DryRun:    dryRun,
+	})
+	if err != nil {
+		exitErr("dedupe", err)
+	}
+
+	if result.Method == store.DedupeByContent {
+		fmt.Fprintln(os.Stderr, "note: no embedding provider configured; only identical content was compared")
+	}
+	if quiet && action != store.DedupeReport {
+		return
+	}
+	printJSON(result)
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/rcliao/agent-memory/internal/diff"
+	"github.com/rcliao/agent-memory/internal/model"
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show changes between two versions of a memory",
+		Long:  "Print a unified diff of two versions' content. Defaults to the latest two versions.",
+		Run:   runDiff,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	cmd.Flags().StringP("key", "k", "", "Key (required)")
+	cmd.Flags().Int("from", 0, "Older version (default: version before --to)")
+	cmd.Flags().Int("to", 0, "Newer version (default: latest)")
+
+	cmd.MarkFlagRequired("ns")
+	cmd.MarkFlagRequired("key")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	key, _ := cmd.Flags().GetString("key")
+	from, _ := cmd.Flags().GetInt("from")
+	to, _ := cmd.Flags().GetInt("to")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	// History is newest first
+	history, err := s.Get(cmd.Context(), store.GetParams{NS: ns, Key: key, History: true})
+	if err != nil {
+		exitErr("diff", err)
+	}
+
+	byVersion := map[int]model.Memory{}
+	for _, m := range history {
+		byVersion[m.Version] = m
+	}
+	if to == 0 {
+		to = history[0].Version
+	}
+	if from == 0 {
+		for _, m := range history {
+			if m.Version < to {
+				from = m.Version
+				break
+			}
+		}
+		if from == 0 {
+			exitErr("diff", fmt.Errorf("no version before v%d of %s/%s", to, ns, key))
+		}
+	}
+
+	older, ok := byVersion[from]
+	if !ok {
+		exitErr("diff", fmt.Errorf("version %d not found: %s/%s", from, ns, key))
+	}
+	newer, ok := byVersion[to]
+	if !ok {
+		exitErr("diff", fmt.Errorf("version %d not found: %s/%s", to, ns, key))
+	}
+
+	fmt.Print(diff.Unified(older.Content, newer.Content,
+		fmt.Sprintf("%s/%s v%d", ns, key, from),
+		fmt.Sprintf("%s/%s v%d", ns, key, to),
+		diff.DefaultContext))
+}
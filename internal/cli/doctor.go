@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/embedding"
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Show resolved configuration and check the database and embedder",
+		Long:  "Print the database path and file status, the configured embedding provider, and whether each is reachable. Exits 1 if any check fails.",
+		Run:   runDoctor,
+	}
+
+	RootCmd.AddCommand(cmd)
+}
+
+type doctorCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type doctorEmbedder struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Dims     int    `json:"dims"`
+}
+
+type doctorReport struct {
+	OK       bool            `json:"ok"`
+	DBPath   string          `json:"db_path"`
+	DBExists bool            `json:"db_exists"`
+	DBSize   int64           `json:"db_size,omitempty"`
+	Embedder *doctorEmbedder `json:"embedder,omitempty"`
+	Checks   []doctorCheck   `json:"checks"`
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	report := doctorReport{DBPath: getDBPath(), OK: true}
+	check := func(name string, err error, detail string) {
+		c := doctorCheck{Name: name, OK: err == nil, Detail: detail}
+		if err != nil {
+			c.Detail = err.Error()
+			report.OK = false
+		}
+		report.Checks = append(report.Checks, c)
+	}
+
+	// Database file
+	if info, err := os.Stat(report.DBPath); err == nil {
+		report.DBExists = true
+		report.DBSize = info.Size()
+		check("db_file", nil, fmt.Sprintf("%d bytes", info.Size()))
+
+		s, err := store.NewReadOnlyStore(report.DBPath)
+		if err == nil {
+			_, err = s.Stats(cmd.Context(), store.StatsParams{})
+			s.Close()
+		}
+		check("db_open", err, "")
+	} else if os.IsNotExist(err) {
+		check("db_file", nil, "not created yet; the first write creates it")
+	} else {
+		check("db_file", err, "")
+	}
+
+	// Embedder
+	e := embedding.NewFromEnv()
+	if e == nil {
+		check("embedder", nil, "disabled (set AGENT_MEMORY_EMBED_PROVIDER to enable vector search)")
+	} else {
+		report.Embedder = &doctorEmbedder{
+			Provider: os.Getenv("AGENT_MEMORY_EMBED_PROVIDER"),
+			Model:    e.Model(),
+			Dims:     e.Dims(),
+		}
+		check("embedder", nil, report.Embedder.Provider+"/"+e.Model())
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+		vec, err := e.Embed(ctx, "ping")
+		cancel()
+		detail := ""
+		if err == nil {
+			detail = fmt.Sprintf("returned %d dims", len(vec))
+			if len(vec) == 0 {
+				err = fmt.Errorf("embedder returned an empty vector")
+			}
+		}
+		check("embedder_connectivity", err, detail)
+	}
+
+	printJSON(report)
+	if !report.OK {
+		os.Exit(1)
+	}
+}
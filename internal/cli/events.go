@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Show the audit log of mutations",
+		Long: `Show the append-only log of mutations (put, rm, undelete, update, link,
+unlink, schema), oldest first. Each event records when it happened, the
+ns/key and version it touched, and the --actor of the command that made it.`,
+		Run: runEvents,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Filter by namespace")
+	cmd.Flags().StringP("key", "k", "", "Filter by key")
+	cmd.Flags().String("op", "", "Filter by operation: put, rm, undelete, update, link, unlink, schema")
+	cmd.Flags().String("since", "", "Only events at or after this time (RFC3339, or a duration like 24h or 7d)")
+	cmd.Flags().IntP("limit", "l", 0, "Show only the most recent N events (0 = all)")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runEvents(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	key, _ := cmd.Flags().GetString("key")
+	op, _ := cmd.Flags().GetString("op")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	p := store.EventsParams{NS: ns, Key: key, Op: op, Limit: limit}
+	if sinceStr != "" {
+		since, err := store.ParseSince(sinceStr)
+		if err != nil {
+			exitErr("events", err)
+		}
+		p.Since = since
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	events, err := s.Events(cmd.Context(), p)
+	if err != nil {
+		exitErr("events", err)
+	}
+	if events == nil {
+		events = []store.Event{}
+	}
+
+	printJSON(events)
+}
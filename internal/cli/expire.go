@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "expire",
+		Short: "Set, extend, or clear the TTL of a memory",
+		Long:  "Update expires_at on the latest version of a memory, counting the TTL from now.",
+		Run:   runExpire,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	cmd.Flags().StringP("key", "k", "", "Key (required)")
+	cmd.Flags().String("ttl", "", "Time-to-live from now (e.g. 7d, 24h, 30m)")
+	cmd.Flags().Bool("clear", false, "Remove the expiry")
+
+	cmd.MarkFlagRequired("ns")
+	cmd.MarkFlagRequired("key")
+	cmd.MarkFlagsMutuallyExclusive("ttl", "clear")
+	cmd.MarkFlagsOneRequired("ttl", "clear")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runExpire(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	key, _ := cmd.Flags().GetString("key")
+	ttl, _ := cmd.Flags().GetString("ttl")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	mem, err := s.SetTTL(cmd.Context(), ns, key, ttl)
+	if err != nil {
+		exitErr("expire", err)
+	}
+
+	if quiet {
+		return
+	}
+
+	b, _ := json.Marshal(mem)
+	fmt.Println(string(b))
+}
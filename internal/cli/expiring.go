@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "expiring",
+		Short: "List memories that will expire soon",
+		Long: `List the latest version of each memory whose TTL runs out within --within,
+soonest first, with the time remaining. Use -f text for one line per memory,
+e.g. from a cron job that warns before important state disappears.`,
+		Run: runExpiring,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Filter by namespace")
+	cmd.Flags().String("within", "24h", "Window from now (e.g. 30m, 24h, 7d)")
+	addProjectionFlags(cmd)
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runExpiring(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	within, _ := cmd.Flags().GetString("within")
+
+	window, err := store.ParseTTL(within)
+	if err != nil {
+		exitErr("expiring", fmt.Errorf("invalid --within: %w", err))
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	memories, err := s.Expiring(cmd.Context(), window, ns)
+	if err != nil {
+		exitErr("expiring", err)
+	}
+
+	if formatFlag == "text" {
+		for _, m := range memories {
+			fmt.Fprintf(os.Stdout, "%s/%s\t%s\t%s\n", m.NS, m.Key, m.ExpiresIn, m.ExpiresAt.Format(time.RFC3339))
+		}
+		return
+	}
+	if memories == nil {
+		memories = []store.ExpiringMemory{}
+	}
+	printProjected(cmd, memories)
+}
@@ -1,9 +1,18 @@
 package cli
 
 import (
+	"bufio"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/mattn/go-isatty"
+	"github.com/rcliao/agent-memory/internal/model"
 	"github.com/rcliao/agent-memory/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -11,18 +20,55 @@ import (
 func init() {
 	cmd := &cobra.Command{
 		Use:   "export",
-		Short: "Export memories as JSON",
-		Long:  "Export memories as newline-delimited JSON. Filter by namespace with -n.",
-		Run:   runExport,
+		Short: "Export memories as JSON Lines, CSV, or Markdown",
+		Long: `Export memories as newline-delimited JSON, one memory version per line.
+
+Every non-deleted version is included, not just the latest, and expired
+memories are exported too. Filter by namespace with -n; add --include-deleted
+to also export soft-deleted versions (they are imported soft-deleted).
+
+Use -f csv for a spreadsheet-friendly table, or -f md for readable Markdown
+grouped by namespace. Neither can be re-imported.
+
+For periodic syncs, --since-id exports only versions stored after a previous
+export. Each export prints "watermark: <id>" to stderr; pass that id as
+--since-id next time. Soft-deleting an already exported version does not
+make it export again.`,
+		Run: runExport,
 	}
 
 	cmd.Flags().StringP("ns", "n", "", "Filter by namespace")
+	cmd.Flags().StringP("out", "o", "", "Write to this file instead of stdout")
+	cmd.Flags().Bool("include-deleted", false, "Also export soft-deleted versions")
+	cmd.Flags().String("since", "", "Only versions created at or after this time (RFC3339, or a duration like 24h or 7d)")
+	cmd.Flags().String("since-id", "", "Only versions stored after this memory id (the watermark of a previous export)")
 
 	RootCmd.AddCommand(cmd)
 }
 
 func runExport(cmd *cobra.Command, args []string) {
 	ns, _ := cmd.Flags().GetString("ns")
+	out, _ := cmd.Flags().GetString("out")
+	includeDeleted, _ := cmd.Flags().GetBool("include-deleted")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	sinceID, _ := cmd.Flags().GetString("since-id")
+
+	newEncoder, ok := exportFormats[formatFlag]
+	if !ok {
+		exitErr("export", fmt.Errorf("unsupported format %q (use json, csv, or md)", formatFlag))
+	}
+	p := store.ExportParams{
+		NS:             ns,
+		IncludeDeleted: includeDeleted,
+		SinceID:        sinceID,
+	}
+	if sinceStr != "" {
+		since, err := store.ParseSince(sinceStr)
+		if err != nil {
+			exitErr("export", err)
+		}
+		p.Since = since
+	}
 
 	s, err := openStore()
 	if err != nil {
@@ -30,21 +76,168 @@ func runExport(cmd *cobra.Command, args []string) {
 	}
 	defer s.Close()
 
-	memories, err := s.List(cmd.Context(), store.ListParams{
-		NS:    ns,
-		Limit: 100000, // effectively unlimited
+	var dst io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			exitErr("export", err)
+		}
+		defer f.Close()
+		dst = f
+	}
+	buf := bufio.NewWriter(dst)
+	enc := newEncoder(buf)
+
+	progress := isatty.IsTerminal(os.Stderr.Fd())
+	n := 0
+	watermark := strings.ToUpper(sinceID)
+	err = s.ExportEach(cmd.Context(), p, func(m model.Memory) error {
+		if err := enc.Encode(m); err != nil {
+			return fmt.Errorf("write memory %s/%s v%d: %w", m.NS, m.Key, m.Version, err)
+		}
+		n++
+		if m.ID > watermark {
+			watermark = m.ID
+		}
+		if progress && n%progressInterval == 0 {
+			fmt.Fprintf(os.Stderr, "\rexporting... %d", n)
+		}
+		return nil
 	})
+	if err == nil {
+		err = enc.Flush()
+	}
+	if err == nil {
+		err = buf.Flush()
+	}
+	if progress && n >= progressInterval {
+		fmt.Fprint(os.Stderr, "\r\x1b[K")
+	}
 	if err != nil {
 		exitErr("export", err)
 	}
 
-	// Also include historical versions via ExportAll
-	allMemories, err := s.ExportAll(cmd.Context(), ns)
-	if err != nil {
-		exitErr("export", err)
+	if !quiet {
+		target := "stdout"
+		if out != "" {
+			target = out
+		}
+		fmt.Fprintf(os.Stderr, "exported %d memories to %s\n", n, target)
+	}
+	// The watermark is what the next incremental run needs, so it is
+	// printed even with --quiet.
+	if watermark != "" {
+		fmt.Fprintf(os.Stderr, "watermark: %s\n", watermark)
 	}
-	_ = memories // ExportAll is more complete
+}
+
+// progressInterval is how many memories pass between progress updates.
+const progressInterval = 500
+
+// exportEncoder writes memories one at a time in some output format.
+type exportEncoder interface {
+	Encode(m model.Memory) error
+	Flush() error
+}
+
+// exportFormats maps --format values to encoder constructors.
+var exportFormats = map[string]func(io.Writer) exportEncoder{
+	"json":     newJSONLEncoder,
+	"csv":      newCSVEncoder,
+	"md":       newMarkdownEncoder,
+	"markdown": newMarkdownEncoder,
+}
 
-	b, _ := json.MarshalIndent(allMemories, "", "  ")
-	fmt.Println(string(b))
+type jsonlEncoder struct{ enc *json.Encoder }
+
+func newJSONLEncoder(w io.Writer) exportEncoder {
+	return jsonlEncoder{enc: json.NewEncoder(w)}
 }
+
+func (e jsonlEncoder) Encode(m model.Memory) error { return e.enc.Encode(m) }
+func (e jsonlEncoder) Flush() error                { return nil }
+
+// csvHeader lists the columns written by the CSV export.
+var csvHeader = []string{"ns", "key", "kind", "priority", "version", "created_at", "tags", "content"}
+
+type csvEncoder struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVEncoder(w io.Writer) exportEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) Encode(m model.Memory) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	return e.w.Write([]string{
+		m.NS,
+		m.Key,
+		m.Kind,
+		m.Priority,
+		strconv.Itoa(m.Version),
+		m.CreatedAt.UTC().Format(time.RFC3339),
+		strings.Join(m.Tags, ";"),
+		m.Content,
+	})
+}
+
+// Flush writes any buffered rows, and the header if no memories were exported.
+func (e *csvEncoder) Flush() error {
+	if !e.wroteHeader {
+		if err := e.w.Write(csvHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// markdownEncoder writes one "## ns/key" section per memory under a
+// "# namespace" header, relying on the export's ns/key/version ordering.
+type markdownEncoder struct {
+	w       io.Writer
+	ns      string
+	started bool
+}
+
+func newMarkdownEncoder(w io.Writer) exportEncoder {
+	return &markdownEncoder{w: w}
+}
+
+func (e *markdownEncoder) Encode(m model.Memory) error {
+	var sb strings.Builder
+	if !e.started || m.NS != e.ns {
+		if e.started {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "# %s\n\n", m.NS)
+		e.ns = m.NS
+	} else {
+		sb.WriteString("\n---\n\n")
+	}
+	e.started = true
+
+	fmt.Fprintf(&sb, "## %s/%s\n\n", m.NS, m.Key)
+	meta := []string{m.Kind, m.Priority, fmt.Sprintf("v%d", m.Version)}
+	if len(m.Tags) > 0 {
+		meta = append(meta, "tags: "+strings.Join(m.Tags, ", "))
+	}
+	meta = append(meta, "created "+m.CreatedAt.UTC().Format(time.RFC3339))
+	if m.DeletedAt != nil {
+		meta = append(meta, "deleted "+m.DeletedAt.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&sb, "_%s_\n\n%s\n", strings.Join(meta, " · "), strings.TrimRight(m.Content, "\n"))
+
+	_, err := io.WriteString(e.w, sb.String())
+	return err
+}
+
+func (e *markdownEncoder) Flush() error { return nil }
@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/rcliao/agent-memory/internal/store"
 	"github.com/spf13/cobra"
@@ -15,13 +15,20 @@ func init() {
 		Run:   runGet,
 	}
 
-	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
-	cmd.Flags().StringP("key", "k", "", "Key (required)")
+	cmd.Flags().StringP("ns", "n", "", "Namespace (required with --key)")
+	cmd.Flags().StringP("key", "k", "", "Key")
+	cmd.Flags().String("id", "", "Fetch exactly this memory row by ID, even if superseded or deleted")
 	cmd.Flags().Bool("history", false, "Return all versions (newest first)")
 	cmd.Flags().IntP("version", "v", 0, "Specific version number")
+	cmd.Flags().Bool("include-expired", false, "Return the version even if it has expired (no access tracking)")
+	cmd.Flags().Bool("fuzzy", false, "If the key does not exist, return the closest matching key instead")
+	addProjectionFlags(cmd)
 
-	cmd.MarkFlagRequired("ns")
-	cmd.MarkFlagRequired("key")
+	cmd.MarkFlagsOneRequired("key", "id")
+	cmd.MarkFlagsMutuallyExclusive("id", "ns")
+	cmd.MarkFlagsMutuallyExclusive("id", "key")
+	cmd.MarkFlagsMutuallyExclusive("id", "history")
+	cmd.MarkFlagsMutuallyExclusive("id", "version")
 
 	RootCmd.AddCommand(cmd)
 }
@@ -31,6 +38,13 @@ func runGet(cmd *cobra.Command, args []string) {
 	key, _ := cmd.Flags().GetString("key")
 	history, _ := cmd.Flags().GetBool("history")
 	version, _ := cmd.Flags().GetInt("version")
+	id, _ := cmd.Flags().GetString("id")
+	includeExpired, _ := cmd.Flags().GetBool("include-expired")
+	fuzzy, _ := cmd.Flags().GetBool("fuzzy")
+
+	if id == "" && ns == "" {
+		exitErr("get", fmt.Errorf("--ns is required with --key"))
+	}
 
 	s, err := openStore()
 	if err != nil {
@@ -38,21 +52,33 @@ func runGet(cmd *cobra.Command, args []string) {
 	}
 	defer s.Close()
 
+	if id != "" {
+		m, err := s.GetByID(cmd.Context(), id)
+		if err != nil {
+			exitErr("get", err)
+		}
+		printProjected(cmd, m)
+		return
+	}
+
 	memories, err := s.Get(cmd.Context(), store.GetParams{
-		NS:      ns,
-		Key:     key,
-		History: history,
-		Version: version,
+		NS:             ns,
+		Key:            key,
+		History:        history,
+		Version:        version,
+		IncludeExpired: includeExpired,
+		Fuzzy:          fuzzy,
 	})
 	if err != nil {
 		exitErr("get", err)
 	}
+	if memories[0].Key != key {
+		fmt.Fprintf(os.Stderr, "note: no key %q; showing closest match %q\n", key, memories[0].Key)
+	}
 
 	if history || len(memories) > 1 {
-		b, _ := json.MarshalIndent(memories, "", "  ")
-		fmt.Println(string(b))
+		printProjected(cmd, memories)
 	} else {
-		b, _ := json.MarshalIndent(memories[0], "", "  ")
-		fmt.Println(string(b))
+		printProjected(cmd, memories[0])
 	}
 }
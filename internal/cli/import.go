@@ -1,12 +1,14 @@
 package cli
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 
 	"github.com/rcliao/agent-memory/internal/model"
+	"github.com/rcliao/agent-memory/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -14,21 +16,27 @@ func init() {
 	cmd := &cobra.Command{
 		Use:   "import",
 		Short: "Import memories from JSON",
-		Long:  "Import memories from JSON (stdin or file). Expects the format produced by export.",
+		Long:  "Import memories from stdin. Accepts the JSON Lines produced by export, or a JSON array of memories.",
 		Run:   runImport,
 	}
 
+	cmd.Flags().Bool("force", false, "Import every memory, even if an identical version already exists")
+	cmd.Flags().String("on-conflict", string(store.MergeVersion), "When ns+key already exists: version (add new version), skip, or newest (by created_at)")
+
 	RootCmd.AddCommand(cmd)
 }
 
 func runImport(cmd *cobra.Command, args []string) {
+	force, _ := cmd.Flags().GetBool("force")
+	onConflict, _ := cmd.Flags().GetString("on-conflict")
+
 	data, err := io.ReadAll(os.Stdin)
 	if err != nil {
 		exitErr("read stdin", err)
 	}
 
-	var memories []model.Memory
-	if err := json.Unmarshal(data, &memories); err != nil {
+	memories, err := parseImport(data)
+	if err != nil {
 		exitErr("parse json", err)
 	}
 
@@ -38,10 +46,40 @@ func runImport(cmd *cobra.Command, args []string) {
 	}
 	defer s.Close()
 
-	imported, err := s.Import(cmd.Context(), memories)
+	result, err := s.Import(cmd.Context(), store.ImportParams{
+		Memories:   memories,
+		Force:      force,
+		OnConflict: store.MergeStrategy(onConflict),
+	})
 	if err != nil {
 		exitErr("import", err)
 	}
 
-	fmt.Printf(`{"ok":true,"imported":%d}`+"\n", imported)
+	if quiet {
+		return
+	}
+	fmt.Printf(`{"ok":true,"imported":%d,"skipped":%d}`+"\n", result.Imported, result.Skipped)
+}
+
+// parseImport decodes either a JSON array of memories or JSON Lines.
+func parseImport(data []byte) ([]model.Memory, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var memories []model.Memory
+		err := json.Unmarshal(trimmed, &memories)
+		return memories, err
+	}
+
+	var memories []model.Memory
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		var m model.Memory
+		if err := dec.Decode(&m); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(memories)+1, err)
+		}
+		memories = append(memories, m)
+	}
+	return memories, nil
 }
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "List the keys in a namespace",
+		Run:   runKeys,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	cmd.Flags().String("prefix", "", "Only list keys starting with this prefix")
+	cmd.MarkFlagRequired("ns")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runKeys(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	prefix, _ := cmd.Flags().GetString("prefix")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	keys, err := s.ListKeys(cmd.Context(), ns)
+	if err != nil {
+		exitErr("list keys", err)
+	}
+
+	if prefix != "" {
+		filtered := []string{}
+		for _, k := range keys {
+			if strings.HasPrefix(k, prefix) {
+				filtered = append(filtered, k)
+			}
+		}
+		keys = filtered
+	}
+
+	if formatFlag == "text" {
+		for _, k := range keys {
+			fmt.Println(k)
+		}
+		return
+	}
+
+	printJSON(keys)
+}
@@ -3,6 +3,8 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/rcliao/agent-memory/internal/store"
 	"github.com/spf13/cobra"
@@ -21,16 +23,22 @@ func init() {
 	cmd.Flags().String("to-key", "", "Target key")
 	cmd.Flags().StringP("rel", "r", "", "Relation: relates_to, contradicts, depends_on, refines")
 	cmd.Flags().Bool("rm", false, "Remove the link")
+	cmd.Flags().Bool("both", false, "Also create/remove the reverse link (relates_to and contradicts only)")
+	cmd.Flags().String("file", "", "Create links from a JSON array of {from_ns, from_key, to_ns, to_key, rel} (- for stdin)")
 
-	cmd.MarkFlagRequired("from-ns")
-	cmd.MarkFlagRequired("from-key")
-	cmd.MarkFlagRequired("to-ns")
-	cmd.MarkFlagRequired("to-key")
-	cmd.MarkFlagRequired("rel")
-
+	cmd.AddCommand(newLinkListCmd())
 	RootCmd.AddCommand(cmd)
 }
 
+// linkSpec is one entry of a --file batch.
+type linkSpec struct {
+	FromNS  string `json:"from_ns"`
+	FromKey string `json:"from_key"`
+	ToNS    string `json:"to_ns"`
+	ToKey   string `json:"to_key"`
+	Rel     string `json:"rel"`
+}
+
 func runLink(cmd *cobra.Command, args []string) {
 	fromNS, _ := cmd.Flags().GetString("from-ns")
 	fromKey, _ := cmd.Flags().GetString("from-key")
@@ -38,6 +46,18 @@ func runLink(cmd *cobra.Command, args []string) {
 	toKey, _ := cmd.Flags().GetString("to-key")
 	rel, _ := cmd.Flags().GetString("rel")
 	rm, _ := cmd.Flags().GetBool("rm")
+	both, _ := cmd.Flags().GetBool("both")
+	file, _ := cmd.Flags().GetString("file")
+
+	if file != "" {
+		runLinkFile(cmd, file, rm, both)
+		return
+	}
+	for _, name := range []string{"from-ns", "from-key", "to-ns", "to-key", "rel"} {
+		if !cmd.Flags().Changed(name) {
+			exitErr("link", fmt.Errorf("--%s is required (or use --file)", name))
+		}
+	}
 
 	s, err := openStore()
 	if err != nil {
@@ -46,17 +66,119 @@ func runLink(cmd *cobra.Command, args []string) {
 	defer s.Close()
 
 	link, err := s.Link(cmd.Context(), store.LinkParams{
-		FromNS:  fromNS,
-		FromKey: fromKey,
-		ToNS:    toNS,
-		ToKey:   toKey,
-		Rel:     rel,
-		Remove:  rm,
+		FromNS:        fromNS,
+		FromKey:       fromKey,
+		ToNS:          toNS,
+		ToKey:         toKey,
+		Rel:           rel,
+		Remove:        rm,
+		Bidirectional: both,
 	})
 	if err != nil {
 		exitErr("link", err)
 	}
 
-	b, _ := json.MarshalIndent(link, "", "  ")
-	fmt.Println(string(b))
+	if quiet {
+		return
+	}
+	printJSON(link)
+}
+
+func runLinkFile(cmd *cobra.Command, file string, rm, both bool) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		exitErr("read links", err)
+	}
+
+	var specs []linkSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		exitErr("parse links", err)
+	}
+
+	links := make([]store.LinkParams, len(specs))
+	for i, l := range specs {
+		links[i] = store.LinkParams{
+			FromNS:        l.FromNS,
+			FromKey:       l.FromKey,
+			ToNS:          l.ToNS,
+			ToKey:         l.ToKey,
+			Rel:           l.Rel,
+			Remove:        rm,
+			Bidirectional: both,
+		}
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.LinkBatch(cmd.Context(), links)
+	if err != nil {
+		exitErr("link", err)
+	}
+
+	if quiet {
+		for _, e := range result.Errors {
+			fmt.Fprintf(os.Stderr, "error: link %d: %s\n", e.Index, e.Error)
+		}
+	} else {
+		printJSON(result)
+	}
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// newLinkListCmd builds the "link list" subcommand.
+func newLinkListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List links with ns/key endpoints",
+		Long:  "List links between memories. Use -f text for one `from --rel--> to` line per link.",
+		Run:   runLinkList,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Only links with an endpoint in this namespace")
+	cmd.Flags().StringP("rel", "r", "", "Filter by relation")
+	cmd.Flags().Bool("include-deleted", false, "Include links whose endpoints are soft-deleted")
+
+	return cmd
+}
+
+func runLinkList(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	rel, _ := cmd.Flags().GetString("rel")
+	includeDeleted, _ := cmd.Flags().GetBool("include-deleted")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	links, err := s.ListLinks(cmd.Context(), store.ListLinksParams{
+		NS:             ns,
+		Rel:            rel,
+		IncludeDeleted: includeDeleted,
+	})
+	if err != nil {
+		exitErr("link list", err)
+	}
+
+	if formatFlag == "text" {
+		for _, l := range links {
+			fmt.Printf("%s/%s --%s--> %s/%s\n", l.FromNS, l.FromKey, l.Rel, l.ToNS, l.ToKey)
+		}
+		return
+	}
+
+	printJSON(links)
 }
@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/rcliao/agent-memory/internal/store"
@@ -19,8 +19,13 @@ func init() {
 	cmd.Flags().StringP("ns", "n", "", "Filter by namespace")
 	cmd.Flags().String("kind", "", "Filter by kind")
 	cmd.Flags().StringP("tags", "t", "", "Filter by tags (comma-separated)")
+	cmd.Flags().String("actor", "", "Filter by the actor that wrote the memory")
+	cmd.Flags().StringArray("meta-filter", nil, "Filter by a meta JSON field, key=value (repeatable; dotted keys for nested fields)")
 	cmd.Flags().IntP("limit", "l", 20, "Max results")
 	cmd.Flags().Bool("keys-only", false, "Only output ns/key pairs")
+	cmd.Flags().Bool("deleted", false, "List soft-deleted memories instead")
+	cmd.Flags().Bool("include-deleted", false, "Include soft-deleted memories")
+	addProjectionFlags(cmd)
 
 	RootCmd.AddCommand(cmd)
 }
@@ -29,8 +34,12 @@ func runList(cmd *cobra.Command, args []string) {
 	ns, _ := cmd.Flags().GetString("ns")
 	kind, _ := cmd.Flags().GetString("kind")
 	tagsStr, _ := cmd.Flags().GetString("tags")
+	actorFilter, _ := cmd.Flags().GetString("actor")
 	limit, _ := cmd.Flags().GetInt("limit")
 	keysOnly, _ := cmd.Flags().GetBool("keys-only")
+	deleted, _ := cmd.Flags().GetBool("deleted")
+	includeDeleted, _ := cmd.Flags().GetBool("include-deleted")
+	metaFilters, _ := cmd.Flags().GetStringArray("meta-filter")
 
 	var tags []string
 	if tagsStr != "" {
@@ -42,6 +51,18 @@ func runList(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	var metaFilter map[string]string
+	for _, f := range metaFilters {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok || k == "" {
+			exitErr("list", fmt.Errorf("invalid --meta-filter %q (want key=value)", f))
+		}
+		if metaFilter == nil {
+			metaFilter = map[string]string{}
+		}
+		metaFilter[k] = v
+	}
+
 	s, err := openStore()
 	if err != nil {
 		exitErr("open store", err)
@@ -49,10 +70,14 @@ func runList(cmd *cobra.Command, args []string) {
 	defer s.Close()
 
 	memories, err := s.List(cmd.Context(), store.ListParams{
-		NS:    ns,
-		Kind:  kind,
-		Tags:  tags,
-		Limit: limit,
+		NS:             ns,
+		Kind:           kind,
+		Tags:           tags,
+		Actor:          actorFilter,
+		Limit:          limit,
+		MetaFilter:     metaFilter,
+		IncludeDeleted: includeDeleted,
+		OnlyDeleted:    deleted,
 	})
 	if err != nil {
 		exitErr("list", err)
@@ -65,6 +90,14 @@ func runList(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	b, _ := json.MarshalIndent(memories, "", "  ")
-	fmt.Println(string(b))
+	if formatFlag == "table" {
+		rows := make([][]string, len(memories))
+		for i, m := range memories {
+			rows[i] = memoryTableRow(m)
+		}
+		renderTable(os.Stdout, memoryTableHeaders, rows, terminalWidth(), useColor())
+		return
+	}
+
+	printProjected(cmd, memories)
 }
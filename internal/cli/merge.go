@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "merge",
+		Short: "Combine several memories into one",
+		Long: `Store a new version of the --into memory containing its current content (if
+any) followed by each --from memory's, separated by --separator. Tags are
+merged; the highest priority wins.
+
+Memories are written ns/key, split at the first slash, so keys may contain
+slashes but namespaces may not.`,
+		Run: runMerge,
+	}
+
+	cmd.Flags().String("into", "", "Target memory as ns/key (required)")
+	cmd.Flags().StringArray("from", nil, "Source memory as ns/key (repeatable, required)")
+	cmd.Flags().String("separator", store.DefaultMergeSeparator, "Text placed between merged contents")
+	cmd.Flags().Bool("rm-sources", false, "Soft-delete every version of the sources after merging")
+	cmd.Flags().Bool("link", false, "Link the merged memory to each source with refines")
+
+	cmd.MarkFlagRequired("into")
+	cmd.MarkFlagRequired("from")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runMerge(cmd *cobra.Command, args []string) {
+	into, _ := cmd.Flags().GetString("into")
+	from, _ := cmd.Flags().GetStringArray("from")
+	separator, _ := cmd.Flags().GetString("separator")
+	rmSources, _ := cmd.Flags().GetBool("rm-sources")
+	link, _ := cmd.Flags().GetBool("link")
+
+	target, err := parseMemoryRef(into)
+	if err != nil {
+		exitErr("merge", fmt.Errorf("--into: %w", err))
+	}
+	var sources []store.MemoryRef
+	for _, f := range from {
+		r, err := parseMemoryRef(f)
+		if err != nil {
+			exitErr("merge", fmt.Errorf("--from: %w", err))
+		}
+		sources = append(sources, r)
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.Merge(cmd.Context(), store.MergeParams{
+		IntoNS:    target.NS,
+		IntoKey:   target.Key,
+		From:      sources,
+		Separator: separator,
+		RmSources: rmSources,
+		Link:      link,
+	})
+	if err != nil {
+		exitErr("merge", err)
+	}
+
+	if quiet {
+		return
+	}
+	printJSON(result)
+}
+
+// parseMemoryRef parses "ns/key", splitting at the first slash.
+func parseMemoryRef(s string) (store.MemoryRef, error) {
+	ns, key, ok := strings.Cut(s, "/")
+	if !ok || ns == "" || key == "" {
+		return store.MemoryRef{}, fmt.Errorf("invalid memory %q (want ns/key)", s)
+	}
+	return store.MemoryRef{NS: ns, Key: key}, nil
+}
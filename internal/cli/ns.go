@@ -1,9 +1,6 @@
 package cli
 
 import (
-	"encoding/json"
-	"fmt"
-
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +16,46 @@ func init() {
 		Run:   runNSList,
 	}
 
-	nsCmd.AddCommand(listCmd)
+	rmCmd := &cobra.Command{
+		Use:   "rm",
+		Short: "Delete every memory in a namespace",
+		Long: `Soft-delete every memory in a namespace (restorable per key with undelete),
+or with --hard permanently delete every version with its chunks, links, and
+meta schema. Prompts on a terminal; a hard delete from a script needs --yes.`,
+		Run: runNSRm,
+	}
+	rmCmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	rmCmd.Flags().Bool("hard", false, "Permanently delete instead of soft-deleting")
+	addYesFlag(rmCmd)
+	rmCmd.MarkFlagRequired("ns")
+
+	mvCmd := &cobra.Command{
+		Use:   "mv",
+		Short: "Rename a namespace, moving every version of its memories",
+		Long: `Move every memory in a namespace, with all its versions, to another
+namespace. Links and the meta schema move with them. If the target already has
+some of the same keys the move is refused; with --force the moved versions are
+appended after the existing ones and become the latest.`,
+		Run: runNSMv,
+	}
+	mvCmd.Flags().String("from", "", "Namespace to rename (required)")
+	mvCmd.Flags().String("to", "", "New namespace (required)")
+	mvCmd.Flags().Bool("force", false, "Merge into keys that already exist in the target")
+	mvCmd.MarkFlagRequired("from")
+	mvCmd.MarkFlagRequired("to")
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show statistics for one namespace",
+		Long: `Show counts of keys, live and deleted versions, and chunks for a namespace,
+the stored size of its live content, its oldest and newest memories, and its
+most used tags.`,
+		Run: runNSStats,
+	}
+	statsCmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	statsCmd.MarkFlagRequired("ns")
+
+	nsCmd.AddCommand(listCmd, statsCmd, rmCmd, mvCmd, newSchemaCmd())
 	RootCmd.AddCommand(nsCmd)
 }
 
@@ -35,6 +71,71 @@ func runNSList(cmd *cobra.Command, args []string) {
 		exitErr("list namespaces", err)
 	}
 
-	b, _ := json.MarshalIndent(rows, "", "  ")
-	fmt.Println(string(b))
+	printJSON(rows)
+}
+
+func runNSStats(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	detail, err := s.NamespaceDetail(cmd.Context(), ns)
+	if err != nil {
+		exitErr("ns stats", err)
+	}
+
+	printJSON(detail)
+}
+
+func runNSRm(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	hard, _ := cmd.Flags().GetBool("hard")
+
+	if hard {
+		confirm(cmd, "permanently delete every memory in namespace "+ns, true)
+	} else {
+		confirm(cmd, "soft-delete every memory in namespace "+ns, false)
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.DeleteNamespace(cmd.Context(), ns, hard)
+	if err != nil {
+		exitErr("ns rm", err)
+	}
+
+	if quiet {
+		return
+	}
+	printJSON(result)
+}
+
+func runNSMv(cmd *cobra.Command, args []string) {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	force, _ := cmd.Flags().GetBool("force")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.RenameNamespace(cmd.Context(), from, to, force)
+	if err != nil {
+		exitErr("ns mv", err)
+	}
+
+	if quiet {
+		return
+	}
+	printJSON(result)
 }
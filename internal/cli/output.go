@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// printJSON prints v as indented JSON, or on a single line with --compact.
+func printJSON(v interface{}) {
+	var b []byte
+	if compactOutput {
+		b, _ = json.Marshal(v)
+	} else {
+		b, _ = json.MarshalIndent(v, "", "  ")
+	}
+	fmt.Println(string(b))
+}
+
+// addProjectionFlags registers the --fields and --no-content output flags.
+func addProjectionFlags(cmd *cobra.Command) {
+	cmd.Flags().String("fields", "", "Comma-separated fields to output (e.g. ns,key,kind,tags)")
+	cmd.Flags().Bool("no-content", false, "Omit content from output")
+}
+
+// printProjected prints v as indented JSON, keeping only the fields selected
+// by --fields, in the order given, and dropping content when --no-content is
+// set.
+func printProjected(cmd *cobra.Command, v interface{}) {
+	fieldsStr, _ := cmd.Flags().GetString("fields")
+	noContent, _ := cmd.Flags().GetBool("no-content")
+
+	if fieldsStr == "" && !noContent {
+		printJSON(v)
+		return
+	}
+
+	var fields []string
+	for _, f := range strings.Split(fieldsStr, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+
+	raw, err := json.Marshal(v)
+	if err == nil {
+		raw, err = project(raw, fields, noContent)
+	}
+	if err != nil {
+		exitErr("output", err)
+	}
+	printJSON(json.RawMessage(raw))
+}
+
+// project applies a field projection to a JSON object, or to each object
+// of a JSON array. With fields, only those keys are kept, in that order;
+// otherwise keys keep their original order. noContent drops "content".
+func project(raw []byte, fields []string, noContent bool) ([]byte, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+		for i, item := range items {
+			p, err := project(item, fields, noContent)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = p
+		}
+		return json.Marshal(items)
+	}
+	if len(raw) == 0 || raw[0] != '{' {
+		return raw, nil
+	}
+
+	// Decode key by key to keep the original order
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	values := map[string]json.RawMessage{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		values[key] = value
+	}
+	if len(fields) > 0 {
+		keys = fields
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	written := map[string]bool{}
+	for _, k := range keys {
+		value, ok := values[k]
+		if !ok || written[k] || (noContent && k == "content") {
+			continue
+		}
+		if len(written) > 0 {
+			buf.WriteByte(',')
+		}
+		written[k] = true
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
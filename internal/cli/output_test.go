@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+func TestProjectKeepsFieldOrder(t *testing.T) {
+	m := model.Memory{NS: "ns", Key: "k", Content: "long content", Kind: "semantic", Tags: []string{"a"}}
+	raw, _ := json.Marshal([]model.Memory{m})
+
+	got, err := project(raw, []string{"tags", "ns", "missing", "key", "ns", "kind"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"tags":["a"],"ns":"ns","key":"k","kind":"semantic"}]`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	raw, _ = json.Marshal(m)
+	got, err = project(raw, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":"","ns":"ns","key":"k","kind":"semantic","tags":["a"],"version":0,`; !strings.HasPrefix(string(got), want) {
+		t.Errorf("--no-content should drop content and keep the struct order, got %s", got)
+	}
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove expired memories",
+		Long:  "Soft-delete memories whose TTL has passed (restorable with undelete), or remove them entirely with --hard.",
+		Run:   runPrune,
+	}
+
+	cmd.Flags().Bool("expired", false, "Sweep memories whose expiry has passed (required)")
+	cmd.Flags().Bool("hard", false, "Permanently delete instead of soft-deleting")
+	addYesFlag(cmd)
+
+	cmd.MarkFlagRequired("expired")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runPrune(cmd *cobra.Command, args []string) {
+	hard, _ := cmd.Flags().GetBool("hard")
+
+	action := "soft-delete all expired memories"
+	if hard {
+		action = "permanently delete all expired memories"
+	}
+	confirm(cmd, action, false)
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	n, err := s.SweepExpired(cmd.Context(), hard)
+	if err != nil {
+		exitErr("prune", err)
+	}
+
+	if quiet {
+		return
+	}
+
+	printJSON(map[string]interface{}{"swept": n, "hard": hard})
+}
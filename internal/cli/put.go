@@ -2,11 +2,13 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
+	"github.com/rcliao/agent-memory/internal/model"
 	"github.com/rcliao/agent-memory/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -15,20 +17,39 @@ func init() {
 	cmd := &cobra.Command{
 		Use:   "put [content]",
 		Short: "Store a memory",
-		Long:  "Store a memory. Content can be a positional arg or piped via stdin.",
-		Run:   runPut,
+		Long: `Store a memory. Content can be a positional arg or piped via stdin.
+
+With --batch, stdin is a JSON array of memories stored in one transaction:
+[{"ns", "key", "content", "kind", "tags", "priority", "meta", "ttl",
+"expires_at", "append"}, ...]. ns defaults to --ns, and meta may be an object
+or a JSON string. Failed entries are reported and skipped, or with --atomic
+abort the whole batch.`,
+		Run: runPut,
 	}
 
-	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
-	cmd.Flags().StringP("key", "k", "", "Key (required)")
+	cmd.Flags().StringP("ns", "n", "", "Namespace (required; with --batch, the default for entries)")
+	cmd.Flags().StringP("key", "k", "", "Key (required unless --batch)")
 	cmd.Flags().String("kind", "semantic", "Kind: semantic, episodic, procedural")
 	cmd.Flags().StringP("tags", "t", "", "Comma-separated tags")
 	cmd.Flags().StringP("priority", "p", "normal", "Priority: low, normal, high, critical")
 	cmd.Flags().String("meta", "", "JSON metadata")
+	cmd.Flags().Bool("meta-raw", false, "Store --meta verbatim without validating it as JSON")
+	cmd.Flags().Bool("append", false, "Store an episodic event under a unique key (<key>/<id>) instead of a new version")
 	cmd.Flags().String("ttl", "", "Time-to-live (e.g. 7d, 24h, 30m)")
+	cmd.Flags().String("expires-at", "", "Absolute expiry time (RFC3339, e.g. 2024-06-30T23:59:59Z)")
+	cmd.Flags().Bool("allow-past", false, "Allow an --expires-at in the past")
+	cmd.Flags().Bool("detect-conflicts", false, "Report similar memories with different content (needs an embedding provider)")
+	cmd.Flags().Float64("conflict-threshold", store.DefaultConflictThreshold, "Similarity above which a memory is reported as a conflict")
+	cmd.Flags().String("chunk-format", "", "Chunking strategy: markdown, plain, lines (default: detect)")
+	cmd.Flags().Int("chunk-size", 0, "Target chunk size in chars (default 400)")
+	cmd.Flags().Int("chunk-overlap", 0, "Chars shared between consecutive chunks (default $AGENT_MEMORY_CHUNK_OVERLAP)")
+	cmd.Flags().Bool("batch", false, "Read a JSON array of memories from stdin and store them in one transaction")
+	cmd.Flags().Bool("atomic", false, "With --batch, store nothing if any entry fails")
+	cmd.Flags().Bool("dry-run", false, "Report how the content would be chunked and embedded without storing it")
 
-	cmd.MarkFlagRequired("ns")
-	cmd.MarkFlagRequired("key")
+	cmd.MarkFlagsMutuallyExclusive("ttl", "expires-at")
+	cmd.MarkFlagsMutuallyExclusive("batch", "key")
+	cmd.MarkFlagsMutuallyExclusive("batch", "dry-run")
 
 	RootCmd.AddCommand(cmd)
 }
@@ -40,7 +61,33 @@ func runPut(cmd *cobra.Command, args []string) {
 	tagsStr, _ := cmd.Flags().GetString("tags")
 	priority, _ := cmd.Flags().GetString("priority")
 	meta, _ := cmd.Flags().GetString("meta")
+	metaRaw, _ := cmd.Flags().GetBool("meta-raw")
 	ttl, _ := cmd.Flags().GetString("ttl")
+	expiresAt, _ := cmd.Flags().GetString("expires-at")
+	allowPast, _ := cmd.Flags().GetBool("allow-past")
+	chunkFormat, _ := cmd.Flags().GetString("chunk-format")
+	chunkSize, _ := cmd.Flags().GetInt("chunk-size")
+	overlap, _ := cmd.Flags().GetInt("chunk-overlap")
+	appendEvent, _ := cmd.Flags().GetBool("append")
+	detectConflicts, _ := cmd.Flags().GetBool("detect-conflicts")
+	conflictThreshold, _ := cmd.Flags().GetFloat64("conflict-threshold")
+	batch, _ := cmd.Flags().GetBool("batch")
+	atomic, _ := cmd.Flags().GetBool("atomic")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if appendEvent && !cmd.Flags().Changed("kind") {
+		kind = "episodic"
+	}
+
+	if batch {
+		runPutBatch(cmd, ns, atomic)
+		return
+	}
+	if ns == "" && nsRequired {
+		exitErr("put", fmt.Errorf("--ns is required: --ns-required is set, so the default namespace is not used"))
+	}
+	if ns == "" || key == "" {
+		exitErr("put", fmt.Errorf("--ns and --key are required (or use --batch)"))
+	}
 
 	// Get content: positional arg first, then check stdin
 	var content string
@@ -49,10 +96,24 @@ func runPut(cmd *cobra.Command, args []string) {
 	} else {
 		stat, _ := os.Stdin.Stat()
 		if (stat.Mode() & os.ModeCharDevice) == 0 {
-			b, err := io.ReadAll(os.Stdin)
+			limit, err := store.MaxContentSize()
+			if err != nil {
+				exitErr("put", err)
+			}
+			var r io.Reader = os.Stdin
+			if limit > 0 {
+				// Read one byte past the limit so oversized input is detected
+				// without buffering all of it.
+				r = io.LimitReader(os.Stdin, int64(limit)+1)
+			}
+			b, err := io.ReadAll(r)
 			if err != nil {
 				exitErr("read stdin", err)
 			}
+			if limit > 0 && len(b) > limit {
+				exitErr("put", fmt.Errorf("%w: stdin exceeds the %d byte limit (set AGENT_MEMORY_MAX_CONTENT to raise it)",
+					store.ErrContentTooLarge, limit))
+			}
 			content = string(b)
 		}
 	}
@@ -77,20 +138,162 @@ func runPut(cmd *cobra.Command, args []string) {
 	}
 	defer s.Close()
 
-	mem, err := s.Put(cmd.Context(), store.PutParams{
-		NS:       ns,
-		Key:      key,
-		Content:  strings.TrimSpace(content),
-		Kind:     kind,
-		Tags:     tags,
-		Priority: priority,
-		Meta:     meta,
-		TTL:      ttl,
-	})
+	params := store.PutParams{
+		NS:              ns,
+		Key:             key,
+		Content:         strings.TrimSpace(content),
+		Kind:            kind,
+		Tags:            tags,
+		Priority:        priority,
+		Meta:            meta,
+		MetaRaw:         metaRaw,
+		TTL:             ttl,
+		Append:          appendEvent,
+		ExpiresAt:       expiresAt,
+		AllowPast:       allowPast,
+		Format:          chunkFormat,
+		ChunkTargetSize: chunkSize,
+		ChunkOverlap:    overlap,
+	}
+
+	if dryRun {
+		plan, err := s.PlanPut(params)
+		if err != nil {
+			exitErr("put", err)
+		}
+		printJSON(plan)
+		return
+	}
+
+	mem, err := s.Put(cmd.Context(), params)
 	if err != nil {
 		exitErr("put", err)
 	}
 
+	if quiet {
+		return
+	}
+
+	if detectConflicts {
+		out := struct {
+			*model.Memory
+			Conflicts     []store.Conflict `json:"conflicts"`
+			ConflictCheck string           `json:"conflict_check,omitempty"`
+		}{Memory: mem, Conflicts: []store.Conflict{}}
+
+		conflicts, err := s.DetectConflicts(cmd.Context(), store.ConflictParams{
+			NS:        mem.NS,
+			Key:       mem.Key,
+			Threshold: conflictThreshold,
+		})
+		switch {
+		case errors.Is(err, store.ErrNoEmbedder):
+			out.ConflictCheck = "skipped: " + err.Error()
+		case err != nil:
+			exitErr("detect conflicts", err)
+		default:
+			out.Conflicts = conflicts
+		}
+
+		b, _ := json.Marshal(out)
+		fmt.Println(string(b))
+		return
+	}
+
 	b, _ := json.Marshal(mem)
 	fmt.Println(string(b))
 }
+
+// putSpec is one entry of a --batch array.
+type putSpec struct {
+	NS        string          `json:"ns"`
+	Key       string          `json:"key"`
+	Content   string          `json:"content"`
+	Kind      string          `json:"kind"`
+	Tags      []string        `json:"tags"`
+	Priority  string          `json:"priority"`
+	Meta      json.RawMessage `json:"meta"`
+	TTL       string          `json:"ttl"`
+	ExpiresAt string          `json:"expires_at"`
+	Append    bool            `json:"append"`
+}
+
+// batchLimitFactor is how many times the content size limit a --batch
+// array read from stdin may be.
+const batchLimitFactor = 16
+
+func runPutBatch(cmd *cobra.Command, defaultNS string, atomic bool) {
+	limit, err := store.MaxContentSize()
+	if err != nil {
+		exitErr("put", err)
+	}
+	limit *= batchLimitFactor
+	var r io.Reader = os.Stdin
+	if limit > 0 {
+		r = io.LimitReader(os.Stdin, int64(limit)+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		exitErr("read stdin", err)
+	}
+	if limit > 0 && len(data) > limit {
+		exitErr("put", fmt.Errorf("%w: batch input exceeds the %d byte limit (set AGENT_MEMORY_MAX_CONTENT to raise it)",
+			store.ErrContentTooLarge, limit))
+	}
+
+	var specs []putSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		exitErr("put", fmt.Errorf("parse batch: %w", err))
+	}
+
+	items := make([]store.PutParams, len(specs))
+	for i, sp := range specs {
+		ns := sp.NS
+		if ns == "" {
+			ns = defaultNS
+		}
+		// meta may be given as an object or as a JSON-encoded string
+		meta := string(sp.Meta)
+		var str string
+		if json.Unmarshal(sp.Meta, &str) == nil {
+			meta = str
+		} else if meta == "null" {
+			meta = ""
+		}
+		items[i] = store.PutParams{
+			NS:        ns,
+			Key:       sp.Key,
+			Content:   strings.TrimSpace(sp.Content),
+			Kind:      sp.Kind,
+			Tags:      sp.Tags,
+			Priority:  sp.Priority,
+			Meta:      meta,
+			TTL:       sp.TTL,
+			ExpiresAt: sp.ExpiresAt,
+			Append:    sp.Append,
+		}
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.PutBatch(cmd.Context(), items, atomic)
+	if err != nil {
+		exitErr("put", err)
+	}
+
+	for _, item := range result.Items {
+		if item.Error != "" {
+			fmt.Fprintf(os.Stderr, "entry %d (%s/%s): %s\n", item.Index, item.NS, item.Key, item.Error)
+		}
+	}
+	if !quiet {
+		printJSON(result)
+	}
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
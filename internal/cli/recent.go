@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "recent",
+		Short: "Show recently created or accessed memories",
+		Run:   runRecent,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Filter by namespace")
+	cmd.Flags().String("by", store.RecentByCreated, "Order by: created or accessed")
+	cmd.Flags().IntP("limit", "l", 20, "Max results")
+	cmd.Flags().Bool("include-never", false, "With --by accessed, include never-accessed memories")
+	addProjectionFlags(cmd)
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runRecent(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	by, _ := cmd.Flags().GetString("by")
+	limit, _ := cmd.Flags().GetInt("limit")
+	includeNever, _ := cmd.Flags().GetBool("include-never")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	memories, err := s.Recent(cmd.Context(), store.RecentParams{
+		NS:           ns,
+		By:           by,
+		Limit:        limit,
+		IncludeNever: includeNever,
+	})
+	if err != nil {
+		exitErr("recent", err)
+	}
+
+	printProjected(cmd, memories)
+}
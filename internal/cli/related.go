@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "related",
+		Short: "Find memories similar to a given memory",
+		Long:  "Use the memory's embedding (or its key terms when embeddings are unavailable) to find similar memories, excluding the memory itself.",
+		Run:   runRelated,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	cmd.Flags().StringP("key", "k", "", "Key (required)")
+	cmd.Flags().IntP("limit", "l", 10, "Max results")
+	addProjectionFlags(cmd)
+
+	cmd.MarkFlagRequired("ns")
+	cmd.MarkFlagRequired("key")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runRelated(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	key, _ := cmd.Flags().GetString("key")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	results, err := s.Related(cmd.Context(), store.RelatedParams{
+		NS:    ns,
+		Key:   key,
+		Limit: limit,
+	})
+	if err != nil {
+		exitErr("related", err)
+	}
+
+	printProjected(cmd, results)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Replace the database with a snapshot from backup",
+		Long: `Check a snapshot written by backup (integrity_check, the store's tables, and
+a schema version this build supports), then replace the active database with
+it. The current database is kept next to it as <db>.pre-restore-<time>.
+Refuses while any other process has the database open.`,
+		Run: runRestore,
+	}
+
+	cmd.Flags().String("from", "", "Snapshot file to restore (required)")
+	addYesFlag(cmd)
+	cmd.MarkFlagRequired("from")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runRestore(cmd *cobra.Command, args []string) {
+	from, _ := cmd.Flags().GetString("from")
+
+	if readOnly {
+		exitErr("restore", store.ErrReadOnly)
+	}
+	dbPath := getDBPath()
+	confirm(cmd, fmt.Sprintf("replace %s with %s", dbPath, from), false)
+
+	result, err := store.Restore(cmd.Context(), dbPath, from)
+	if err != nil {
+		exitErr("restore", err)
+	}
+
+	// Reopen so older snapshots are migrated and the result is known to load.
+	s, err := openStore()
+	if err != nil {
+		exitErr("open restored store", err)
+	}
+	s.Close()
+
+	if quiet {
+		return
+	}
+	printJSON(result)
+}
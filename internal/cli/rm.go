@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/rcliao/agent-memory/internal/store"
 	"github.com/spf13/cobra"
@@ -15,12 +16,17 @@ func init() {
 	}
 
 	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
-	cmd.Flags().StringP("key", "k", "", "Key (required)")
+	cmd.Flags().StringP("key", "k", "", "Key")
+	cmd.Flags().String("key-glob", "", "Delete every key matching a glob pattern (e.g. 'tmp-*'); dry run unless --confirm")
+	cmd.Flags().Bool("confirm", false, "With --key-glob, actually delete the matches")
 	cmd.Flags().Bool("all-versions", false, "Delete all versions")
 	cmd.Flags().Bool("hard", false, "Permanent delete (irreversible)")
+	cmd.Flags().Bool("cascade", false, "With --hard, also delete links to and from the memory")
+	addYesFlag(cmd)
 
 	cmd.MarkFlagRequired("ns")
-	cmd.MarkFlagRequired("key")
+	cmd.MarkFlagsOneRequired("key", "key-glob")
+	cmd.MarkFlagsMutuallyExclusive("key", "key-glob")
 
 	RootCmd.AddCommand(cmd)
 }
@@ -30,6 +36,19 @@ func runRm(cmd *cobra.Command, args []string) {
 	key, _ := cmd.Flags().GetString("key")
 	allVersions, _ := cmd.Flags().GetBool("all-versions")
 	hard, _ := cmd.Flags().GetBool("hard")
+	cascade, _ := cmd.Flags().GetBool("cascade")
+	glob, _ := cmd.Flags().GetString("key-glob")
+	confirmGlob, _ := cmd.Flags().GetBool("confirm")
+
+	if hard || allVersions {
+		target := ns + "/" + key
+		if glob != "" {
+			target = "every key matching " + ns + "/" + glob
+		}
+		if glob == "" || confirmGlob {
+			confirm(cmd, rmAction(target, allVersions, hard), hard && allVersions)
+		}
+	}
 
 	s, err := openStore()
 	if err != nil {
@@ -37,15 +56,68 @@ func runRm(cmd *cobra.Command, args []string) {
 	}
 	defer s.Close()
 
+	if glob != "" {
+		runRmGlob(cmd, s, store.RmParams{
+			NS:          ns,
+			Glob:        glob,
+			AllVersions: allVersions,
+			Hard:        hard,
+			Cascade:     cascade,
+		}, confirmGlob)
+		return
+	}
+
 	err = s.Rm(cmd.Context(), store.RmParams{
 		NS:          ns,
 		Key:         key,
 		AllVersions: allVersions,
 		Hard:        hard,
+		Cascade:     cascade,
 	})
 	if err != nil {
 		exitErr("rm", err)
 	}
 
+	if quiet {
+		return
+	}
 	fmt.Fprintf(cmd.OutOrStdout(), `{"ok":true,"ns":%q,"key":%q}`+"\n", ns, key)
 }
+
+// rmAction describes an rm for the confirmation prompt.
+func rmAction(target string, allVersions, hard bool) string {
+	what := "the latest version of"
+	if allVersions {
+		what = "all versions of"
+	}
+	if hard {
+		return "permanently delete " + what + " " + target
+	}
+	return "delete " + what + " " + target
+}
+
+// runRmGlob deletes the keys matching p.Glob, or with confirm unset only
+// reports what would be deleted.
+func runRmGlob(cmd *cobra.Command, s *store.SQLiteStore, p store.RmParams, confirm bool) {
+	if !confirm {
+		keys, err := s.MatchKeys(cmd.Context(), p)
+		if err != nil {
+			exitErr("rm", err)
+		}
+		printJSON(map[string]interface{}{"dry_run": true, "ns": p.NS, "matched": len(keys), "keys": keys})
+		if len(keys) > 0 {
+			fmt.Fprintf(os.Stderr, "%d keys match; re-run with --confirm to delete them\n", len(keys))
+		}
+		return
+	}
+
+	keys, err := s.RmGlob(cmd.Context(), p)
+	if err != nil {
+		exitErr("rm", err)
+	}
+
+	if quiet {
+		return
+	}
+	printJSON(map[string]interface{}{"ok": true, "ns": p.NS, "deleted": keys})
+}
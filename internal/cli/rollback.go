@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore a previous version as the new latest",
+		Long:  "Copy a previous version's content, tags, and meta into a new version. Existing versions are kept.",
+		Run:   runRollback,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	cmd.Flags().StringP("key", "k", "", "Key (required)")
+	cmd.Flags().Int("to", 0, "Version to restore (required)")
+
+	cmd.MarkFlagRequired("ns")
+	cmd.MarkFlagRequired("key")
+	cmd.MarkFlagRequired("to")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runRollback(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	key, _ := cmd.Flags().GetString("key")
+	to, _ := cmd.Flags().GetInt("to")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	mem, err := s.Rollback(cmd.Context(), store.RollbackParams{
+		NS:  ns,
+		Key: key,
+		To:  to,
+	})
+	if err != nil {
+		exitErr("rollback", err)
+	}
+
+	if quiet {
+		return
+	}
+	printJSON(mem)
+}
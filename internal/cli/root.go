@@ -6,13 +6,22 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/mattn/go-isatty"
+	"github.com/rcliao/agent-memory/internal/embedding"
 	"github.com/rcliao/agent-memory/internal/store"
 	"github.com/spf13/cobra"
 )
 
 var (
-	dbPath     string
-	formatFlag string
+	dbPath        string
+	formatFlag    string
+	forceDims     bool
+	readOnly      bool
+	quiet         bool
+	compactOutput bool
+	actor         string
+	nsRequired    bool
+	noEmbed       bool
 )
 
 // RootCmd is the top-level command.
@@ -20,11 +29,20 @@ var RootCmd = &cobra.Command{
 	Use:   "agent-memory",
 	Short: "Persistent memory for AI agents",
 	Long:  "A tiny CLI for persistent agent memory. Text in, text out. SQLite-backed, single binary.",
+
+	PersistentPreRun: applyConfig,
 }
 
 func init() {
 	RootCmd.PersistentFlags().StringVarP(&dbPath, "db", "d", "", "Database path (default: $AGENT_MEMORY_DB or ~/.agent-memory/memory.db)")
-	RootCmd.PersistentFlags().StringVarP(&formatFlag, "format", "f", "json", "Output format: json or text")
+	RootCmd.PersistentFlags().StringVarP(&formatFlag, "format", "f", "json", "Output format: json, text, md (context, export), csv (export), or table (list, search)")
+	RootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Open the database read-only (no writes or access tracking)")
+	RootCmd.PersistentFlags().BoolVar(&compactOutput, "compact", false, "Print JSON on a single line instead of indented")
+	RootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress success output from write commands (errors still go to stderr)")
+	RootCmd.PersistentFlags().StringVar(&actor, "actor", "", "Who is making changes, recorded on new memories and in the event log (default: $AGENT_MEMORY_ACTOR)")
+	RootCmd.PersistentFlags().BoolVar(&forceDims, "force-dims", false, "Allow embeddings whose dimensions differ from the existing index")
+	RootCmd.PersistentFlags().BoolVar(&noEmbed, "no-embed", false, "Do not call the embedder for this command: puts store no vectors and search uses keywords only")
+	RootCmd.PersistentFlags().BoolVar(&nsRequired, "ns-required", false, "Require put to name its namespace with -n instead of using the default (also $AGENT_MEMORY_NS_REQUIRED)")
 }
 
 func getDBPath() string {
@@ -39,7 +57,46 @@ func getDBPath() string {
 }
 
 func openStore() (*store.SQLiteStore, error) {
-	return store.NewSQLiteStore(getDBPath())
+	var embedder embedding.Embedder
+	if !noEmbed {
+		embedder = embedding.NewFromEnv()
+	}
+	opts := []store.Option{store.WithEmbedder(embedder)}
+	if readOnly {
+		opts = append(opts, store.WithReadOnly())
+	}
+	s, err := store.NewSQLiteStore(getDBPath(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	s.SetForceDims(forceDims)
+	if err := s.SetAllowedNamespaces(loadedConfig.AllowedNS); err != nil {
+		s.Close()
+		return nil, err
+	}
+	if actor != "" {
+		s.SetActor(actor)
+	} else {
+		s.SetActor(os.Getenv("AGENT_MEMORY_ACTOR"))
+	}
+	if embedder != nil {
+		noteEmbeddings(embedder)
+	}
+	return s, nil
+}
+
+// embeddingsNoted keeps the embeddings note to once per invocation.
+var embeddingsNoted bool
+
+// noteEmbeddings tells an interactive user that puts and searches call the
+// configured embedder. Scripts (stderr not a terminal) and --quiet skip it.
+func noteEmbeddings(e embedding.Embedder) {
+	fd := os.Stderr.Fd()
+	if embeddingsNoted || quiet || !(isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)) {
+		return
+	}
+	embeddingsNoted = true
+	fmt.Fprintf(os.Stderr, "note: embeddings on (%s/%s)\n", os.Getenv("AGENT_MEMORY_EMBED_PROVIDER"), e.Model())
 }
 
 func exitErr(msg string, err error) {
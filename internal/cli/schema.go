@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Manage per-namespace meta schemas",
+		Long: `A namespace schema is a JSON Schema that the meta of every put to the
+namespace must satisfy; non-conforming puts are rejected with the validation
+errors. Supported keywords: type, enum, properties, required,
+additionalProperties (boolean), items, minItems, maxItems, minLength,
+maxLength, minimum, and maximum.`,
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set the meta schema for a namespace",
+		Run:   runSchemaSet,
+	}
+	setCmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	setCmd.Flags().String("file", "", "JSON Schema file (- for stdin, required)")
+	setCmd.MarkFlagRequired("ns")
+	setCmd.MarkFlagRequired("file")
+
+	getCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the meta schema for a namespace",
+		Run:   runSchemaGet,
+	}
+	getCmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	getCmd.MarkFlagRequired("ns")
+
+	rmCmd := &cobra.Command{
+		Use:   "rm",
+		Short: "Remove the meta schema for a namespace",
+		Run:   runSchemaRm,
+	}
+	rmCmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	rmCmd.MarkFlagRequired("ns")
+
+	cmd.AddCommand(setCmd, getCmd, rmCmd)
+	return cmd
+}
+
+func runSchemaSet(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	file, _ := cmd.Flags().GetString("file")
+
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		exitErr("read schema", err)
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	sch, err := s.SetSchema(cmd.Context(), ns, data)
+	if err != nil {
+		exitErr("set schema", err)
+	}
+
+	if quiet {
+		return
+	}
+	printJSON(sch)
+}
+
+func runSchemaGet(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	sch, err := s.GetSchema(cmd.Context(), ns)
+	if err != nil {
+		exitErr("get schema", err)
+	}
+	if sch == nil {
+		exitErr("get schema", fmt.Errorf("namespace %q has no schema", ns))
+	}
+	printJSON(sch)
+}
+
+func runSchemaRm(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	ok, err := s.DeleteSchema(cmd.Context(), ns)
+	if err != nil {
+		exitErr("remove schema", err)
+	}
+	if !ok {
+		exitErr("remove schema", fmt.Errorf("namespace %q has no schema", ns))
+	}
+
+	if quiet {
+		return
+	}
+	printJSON(map[string]interface{}{"ok": true, "ns": ns})
+}
@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/rcliao/agent-memory/internal/store"
@@ -21,6 +21,10 @@ func init() {
 	cmd.Flags().StringP("ns", "n", "", "Filter by namespace")
 	cmd.Flags().String("kind", "", "Filter by kind")
 	cmd.Flags().IntP("limit", "l", 20, "Max results")
+	cmd.Flags().String("mode", "hybrid", "Search mode: hybrid, keyword, vector")
+	cmd.Flags().Bool("raw", false, "Pass the query to FTS5 unmodified (phrases, NOT, OR)")
+	cmd.Flags().Bool("explain", false, "Include a per-result scoring breakdown (explain)")
+	addProjectionFlags(cmd)
 
 	RootCmd.AddCommand(cmd)
 }
@@ -29,6 +33,9 @@ func runSearch(cmd *cobra.Command, args []string) {
 	ns, _ := cmd.Flags().GetString("ns")
 	kind, _ := cmd.Flags().GetString("kind")
 	limit, _ := cmd.Flags().GetInt("limit")
+	mode, _ := cmd.Flags().GetString("mode")
+	raw, _ := cmd.Flags().GetBool("raw")
+	explain, _ := cmd.Flags().GetBool("explain")
 	query := strings.Join(args, " ")
 
 	s, err := openStore()
@@ -38,20 +45,32 @@ func runSearch(cmd *cobra.Command, args []string) {
 	defer s.Close()
 
 	results, err := s.Search(cmd.Context(), store.SearchParams{
-		NS:    ns,
-		Query: query,
-		Kind:  kind,
-		Limit: limit,
+		NS:       ns,
+		Query:    query,
+		Kind:     kind,
+		Limit:    limit,
+		Mode:     mode,
+		RawQuery: raw,
+		Explain:  explain,
 	})
 	if err != nil {
 		exitErr("search", err)
 	}
 
+	if formatFlag == "table" {
+		headers := append(append([]string{}, memoryTableHeaders...), "SCORE")
+		rows := make([][]string, len(results))
+		for i, r := range results {
+			rows[i] = append(memoryTableRow(r.Memory), fmt.Sprintf("%.3f", r.Score))
+		}
+		renderTable(os.Stdout, headers, rows, terminalWidth(), useColor())
+		return
+	}
+
 	if len(results) == 0 {
 		fmt.Println("[]")
 		return
 	}
 
-	b, _ := json.MarshalIndent(results, "", "  ")
-	fmt.Println(string(b))
+	printProjected(cmd, results)
 }
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/server"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve health and Prometheus metrics over HTTP",
+		Long: `Listen on --addr and serve monitoring endpoints until interrupted (Ctrl-C):
+
+  GET /health   database and embedder status as JSON (503 if the database is down)
+  GET /metrics  Prometheus text: operation counters, search latency, and
+                memory/chunk gauges
+
+The embedder check makes a real embedding request, so its result is reused
+for a minute. Operation counters cover this process only.`,
+		Run: runServe,
+	}
+
+	cmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) {
+	addr, _ := cmd.Flags().GetString("addr")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: addr, Handler: server.Handler(s), ReadHeaderTimeout: 10 * time.Second}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "listening on %s\n", addr)
+	}
+
+	select {
+	case err := <-errc:
+		exitErr("serve", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		exitErr("serve", err)
+	}
+}
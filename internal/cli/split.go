@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "split",
+		Short: "Break one memory into several, one per section",
+		Long: `Store each markdown section (or, with --by chunk, each search chunk) of a
+memory as a new memory keyed <key>#<section-slug>, linked to the original with
+refines. The new memories keep the original's kind, tags, priority, and meta.
+
+By default sections split at the shallowest heading level used more than
+once, so a single top-level title does not stop the split; use --level to
+choose. Text before the first heading becomes <key>#intro.`,
+		Run: runSplit,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	cmd.Flags().StringP("key", "k", "", "Key (required)")
+	cmd.Flags().String("by", store.SplitByHeading, "Split strategy: heading, chunk")
+	cmd.Flags().Int("level", 0, "With --by heading, heading level to split at (1-6)")
+	cmd.Flags().Bool("rm-original", false, "Soft-delete every version of the original after splitting")
+
+	cmd.MarkFlagRequired("ns")
+	cmd.MarkFlagRequired("key")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runSplit(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	key, _ := cmd.Flags().GetString("key")
+	by, _ := cmd.Flags().GetString("by")
+	level, _ := cmd.Flags().GetInt("level")
+	rmOriginal, _ := cmd.Flags().GetBool("rm-original")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.Split(cmd.Context(), store.SplitParams{
+		NS:         ns,
+		Key:        key,
+		By:         by,
+		Level:      level,
+		RmOriginal: rmOriginal,
+	})
+	if err != nil {
+		exitErr("split", err)
+	}
+
+	if quiet {
+		return
+	}
+	printJSON(result)
+}
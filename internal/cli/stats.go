@@ -1,9 +1,7 @@
 package cli
 
 import (
-	"encoding/json"
-	"fmt"
-
+	"github.com/rcliao/agent-memory/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -14,21 +12,30 @@ func init() {
 		Run:   runStats,
 	}
 
+	cmd.Flags().Bool("per-kind", false, "Include counts by kind")
+	cmd.Flags().Bool("per-priority", false, "Include counts by priority")
+
 	RootCmd.AddCommand(cmd)
 }
 
 func runStats(cmd *cobra.Command, args []string) {
+	perKind, _ := cmd.Flags().GetBool("per-kind")
+	perPriority, _ := cmd.Flags().GetBool("per-priority")
+
 	s, err := openStore()
 	if err != nil {
 		exitErr("open store", err)
 	}
 	defer s.Close()
 
-	stats, err := s.Stats(cmd.Context(), getDBPath())
+	stats, err := s.Stats(cmd.Context(), store.StatsParams{
+		DBPath:      getDBPath(),
+		PerKind:     perKind,
+		PerPriority: perPriority,
+	})
 	if err != nil {
 		exitErr("stats", err)
 	}
 
-	b, _ := json.MarshalIndent(stats, "", "  ")
-	fmt.Println(string(b))
+	printJSON(stats)
 }
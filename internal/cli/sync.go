@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Reconcile this store with another database in both directions",
+		Long: `Copy every memory version missing on either side between this store and
+another database, preserving ids, timestamps, chunks, and links.
+
+Versions are matched by ns/key/version. If both sides wrote the same version
+independently, that key is reported as a conflict and left untouched from
+that version on. Versions in namespaces outside this store's allowed_ns are not
+pulled and are reported under disallowed.`,
+		Run: runSync,
+	}
+
+	cmd.Flags().String("from", "", "Path of the other database (required)")
+	cmd.MarkFlagRequired("from")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) {
+	from, _ := cmd.Flags().GetString("from")
+
+	if samePath(from, getDBPath()) {
+		exitErr("sync", fmt.Errorf("--from is the same database as --db"))
+	}
+	if _, err := os.Stat(from); err != nil {
+		exitErr("sync", err)
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	other, err := store.NewSQLiteStore(from)
+	if err != nil {
+		exitErr("open "+from, err)
+	}
+	defer other.Close()
+
+	result, err := s.Sync(cmd.Context(), other)
+	if err != nil {
+		exitErr("sync", err)
+	}
+
+	if quiet {
+		return
+	}
+	printJSON(result)
+}
+
+// samePath reports whether a and b resolve to the same file.
+func samePath(a, b string) bool {
+	if ai, err := os.Stat(a); err == nil {
+		if bi, err := os.Stat(b); err == nil {
+			return os.SameFile(ai, bi)
+		}
+	}
+	aa, _ := filepath.Abs(a)
+	ab, _ := filepath.Abs(b)
+	return aa == ab
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-isatty"
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// defaultTableWidth is used when stdout is not a terminal and $COLUMNS is unset.
+const defaultTableWidth = 120
+
+// minColumnWidth is the narrowest a column is shrunk to when fitting the table.
+const minColumnWidth = 6
+
+var memoryTableHeaders = []string{"NS", "KEY", "KIND", "PRIORITY", "VERSION", "CREATED"}
+
+func memoryTableRow(m model.Memory) []string {
+	return []string{
+		m.NS,
+		m.Key,
+		m.Kind,
+		m.Priority,
+		strconv.Itoa(m.Version),
+		m.CreatedAt.Local().Format("2006-01-02 15:04"),
+	}
+}
+
+// stdoutIsTerminal reports whether stdout is an interactive terminal.
+func stdoutIsTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// useColor reports whether table output should be colored: only on a
+// terminal and only when $NO_COLOR is unset.
+func useColor() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return stdoutIsTerminal()
+}
+
+// terminalWidth returns $COLUMNS if set, otherwise defaultTableWidth.
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultTableWidth
+}
+
+// renderTable writes rows as space-separated columns, shrinking the widest
+// columns until the table fits in width. Truncated cells end in "…". When
+// color is set, the header is bold, keys are cyan, and high/critical
+// priorities are highlighted.
+func renderTable(w io.Writer, headers []string, rows [][]string, width int, color bool) {
+	const gap = 2
+
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	total := func() int {
+		t := gap * (len(widths) - 1)
+		for _, w := range widths {
+			t += w
+		}
+		return t
+	}
+	for total() > width {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+	}
+
+	writeRow := func(cells []string, style func(col int, cell string) string) {
+		var b strings.Builder
+		for i, cell := range cells {
+			cell = truncateCell(cell, widths[i])
+			pad := widths[i] - utf8.RuneCountInString(cell)
+			if style != nil {
+				cell = style(i, cell)
+			}
+			b.WriteString(cell)
+			if i < len(cells)-1 {
+				b.WriteString(strings.Repeat(" ", pad+gap))
+			}
+		}
+		fmt.Fprintln(w, b.String())
+	}
+
+	if color {
+		writeRow(headers, func(_ int, cell string) string { return ansiBold + cell + ansiReset })
+	} else {
+		writeRow(headers, nil)
+	}
+	for _, row := range rows {
+		if !color {
+			writeRow(row, nil)
+			continue
+		}
+		writeRow(row, func(col int, cell string) string {
+			switch {
+			case headers[col] == "KEY":
+				return ansiCyan + cell + ansiReset
+			case headers[col] == "PRIORITY" && cell == "critical":
+				return ansiRed + cell + ansiReset
+			case headers[col] == "PRIORITY" && cell == "high":
+				return ansiYellow + cell + ansiReset
+			}
+			return cell
+		})
+	}
+}
+
+// truncateCell shortens s to at most n runes, marking the cut with "…".
+func truncateCell(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string([]rune(s)[:n])
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
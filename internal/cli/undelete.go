@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "undelete",
+		Short: "Restore a soft-deleted memory",
+		Long:  "Restore the most recently deleted version of a key, a specific version, or all deleted versions.",
+		Run:   runUndelete,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Namespace (required)")
+	cmd.Flags().StringP("key", "k", "", "Key (required)")
+	cmd.Flags().IntP("version", "v", 0, "Specific version to restore")
+	cmd.Flags().Bool("all-versions", false, "Restore every deleted version")
+
+	cmd.MarkFlagRequired("ns")
+	cmd.MarkFlagRequired("key")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runUndelete(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	key, _ := cmd.Flags().GetString("key")
+	version, _ := cmd.Flags().GetInt("version")
+	allVersions, _ := cmd.Flags().GetBool("all-versions")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	restored, err := s.Undelete(cmd.Context(), store.UndeleteParams{
+		NS:          ns,
+		Key:         key,
+		Version:     version,
+		AllVersions: allVersions,
+	})
+	if err != nil {
+		exitErr("undelete", err)
+	}
+
+	if quiet {
+		return
+	}
+
+	if len(restored) > 1 {
+		printJSON(restored)
+	} else {
+		printJSON(restored[0])
+	}
+}
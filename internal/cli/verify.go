@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:     "verify",
+		Aliases: []string{"fsck"},
+		Short:   "Check the database for integrity problems",
+		Long:    "Check for dangling chunks and links, FTS index drift, invalid kinds/priorities, and SQLite corruption.",
+		Run:     runVerify,
+	}
+
+	cmd.Flags().Bool("fix", false, "Remove orphaned chunks/links and rebuild the FTS index")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runVerify(cmd *cobra.Command, args []string) {
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	result, err := s.Verify(cmd.Context(), fix)
+	if err != nil {
+		exitErr("verify", err)
+	}
+
+	printJSON(result)
+	if !result.OK {
+		os.Exit(1)
+	}
+}
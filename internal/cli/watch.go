@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Stream new memories as JSON Lines as they are written",
+		Long: `Poll the database for memory versions created since the last check and
+print each one as a JSON line, like tail -f. Runs until interrupted (Ctrl-C).
+
+Every new version is printed, including appended episodic events. Versions
+that are soft-deleted before the next poll are not.`,
+		Run: runWatch,
+	}
+
+	cmd.Flags().StringP("ns", "n", "", "Only watch this namespace")
+	cmd.Flags().Duration("interval", time.Second, "Poll interval")
+	cmd.Flags().Duration("since", 0, "Also replay memories created within this long before starting (e.g. 10m)")
+
+	RootCmd.AddCommand(cmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) {
+	ns, _ := cmd.Flags().GetString("ns")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	since, _ := cmd.Flags().GetDuration("since")
+
+	if interval <= 0 {
+		exitErr("watch", fmt.Errorf("--interval must be positive"))
+	}
+
+	s, err := openStore()
+	if err != nil {
+		exitErr("open store", err)
+	}
+	defer s.Close()
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cursor := store.NewWatchCursor(time.Now().Add(-since))
+	if since == 0 {
+		// Skip anything written earlier in the current second.
+		if _, err := s.WatchNext(ctx, ns, cursor); err != nil {
+			exitErr("watch", err)
+		}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		memories, err := s.WatchNext(ctx, ns, cursor)
+		if err != nil && ctx.Err() == nil {
+			exitErr("watch", err)
+		}
+		for _, m := range memories {
+			if err := enc.Encode(m); err != nil {
+				// Reader went away (e.g. piped into head).
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
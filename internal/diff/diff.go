@@ -0,0 +1,174 @@
+// Package diff computes line-based differences between two texts.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of change a line represents.
+type Op byte
+
+const (
+	Equal  Op = ' '
+	Delete Op = '-'
+	Insert Op = '+'
+)
+
+// Line is one line of a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// DefaultContext is the number of unchanged lines shown around each change.
+const DefaultContext = 3
+
+// Lines returns the line-by-line edit script turning a into b, computed from
+// the longest common subsequence of their lines.
+func Lines(a, b string) []Line {
+	al, bl := splitLines(a), splitLines(b)
+	n, m := len(al), len(bl)
+
+	// lcs[i][j] is the LCS length of al[i:] and bl[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out []Line
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case al[i] == bl[j]:
+			out = append(out, Line{Equal, al[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, Line{Delete, al[i]})
+			i++
+		default:
+			out = append(out, Line{Insert, bl[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, Line{Delete, al[i]})
+	}
+	for ; j < m; j++ {
+		out = append(out, Line{Insert, bl[j]})
+	}
+	return out
+}
+
+// Unified renders a unified diff of a and b with the given file labels.
+// It returns an empty string when the texts are identical.
+func Unified(a, b, fromLabel, toLabel string, context int) string {
+	lines := Lines(a, b)
+
+	changed := false
+	for _, l := range lines {
+		if l.Op != Equal {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+
+	// Walk the script, emitting hunks of changes padded with context lines
+	aLine, bLine := 1, 1
+	for idx := 0; idx < len(lines); {
+		if lines[idx].Op == Equal {
+			idx++
+			aLine++
+			bLine++
+			continue
+		}
+
+		// Back up to include leading context
+		start := idx
+		for k := 0; k < context && start > 0 && lines[start-1].Op == Equal; k++ {
+			start--
+		}
+		aStart, bStart := aLine-(idx-start), bLine-(idx-start)
+
+		// Extend until a run of more than 2*context unchanged lines
+		end := idx
+		for end < len(lines) {
+			if lines[end].Op != Equal {
+				end++
+				continue
+			}
+			run := end
+			for run < len(lines) && lines[run].Op == Equal {
+				run++
+			}
+			if run == len(lines) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+
+		aCount, bCount := 0, 0
+		for _, l := range lines[start:end] {
+			if l.Op != Insert {
+				aCount++
+			}
+			if l.Op != Delete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for _, l := range lines[start:end] {
+			sb.WriteByte(byte(l.Op))
+			sb.WriteString(l.Text)
+			sb.WriteByte('\n')
+		}
+
+		for _, l := range lines[idx:end] {
+			if l.Op != Insert {
+				aLine++
+			}
+			if l.Op != Delete {
+				bLine++
+			}
+		}
+		idx = end
+	}
+	return sb.String()
+}
+
+// hunkRange formats a unified diff range; empty ranges point at the line before.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
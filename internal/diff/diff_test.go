@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLines_Identical(t *testing.T) {
+	for _, l := range Lines("a\nb", "a\nb") {
+		if l.Op != Equal {
+			t.Fatalf("expected only equal lines, got %+v", l)
+		}
+	}
+	if got := Unified("a\nb", "a\nb", "a", "b", DefaultContext); got != "" {
+		t.Errorf("expected empty diff, got %q", got)
+	}
+}
+
+func TestLines_Changes(t *testing.T) {
+	got := Lines("one\ntwo\nthree", "one\n2\nthree\nfour")
+	want := []Line{
+		{Equal, "one"},
+		{Delete, "two"},
+		{Insert, "2"},
+		{Equal, "three"},
+		{Insert, "four"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d lines, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestUnified(t *testing.T) {
+	got := Unified("a\nb\nc", "a\nB\nc", "v1", "v2", DefaultContext)
+	want := "--- v1\n+++ v2\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n"
+	if got != want {
+		t.Errorf("Unified =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		a = append(a, "line")
+		b = append(b, "line")
+	}
+	a[1], b[1] = "old-top", "new-top"
+	a[18], b[18] = "old-bottom", "new-bottom"
+
+	got := Unified(strings.Join(a, "\n"), strings.Join(b, "\n"), "a", "b", DefaultContext)
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", n, got)
+	}
+	if !strings.Contains(got, "@@ -1,5 +1,5 @@") || !strings.Contains(got, "@@ -16,5 +16,5 @@") {
+		t.Errorf("unexpected hunk headers:\n%s", got)
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"math"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,7 +22,35 @@ type Vector = []float32
 // Embedder generates embedding vectors from text.
 type Embedder interface {
 	Embed(ctx context.Context, text string) (Vector, error)
+	// Dims is the length of the vectors Embed returns. Providers learn it
+	// from their first response, so it may call the provider once.
 	Dims() int
+	Model() string
+}
+
+// AsymmetricEmbedder is implemented by embedders whose models embed stored
+// text and search queries differently. Embed behaves like EmbedDocument.
+type AsymmetricEmbedder interface {
+	Embedder
+	EmbedDocument(ctx context.Context, text string) (Vector, error)
+	EmbedQuery(ctx context.Context, text string) (Vector, error)
+}
+
+// EmbedDocument embeds text that will be stored and searched over, using
+// e's document mode if it has one.
+func EmbedDocument(ctx context.Context, e Embedder, text string) (Vector, error) {
+	if a, ok := e.(AsymmetricEmbedder); ok {
+		return a.EmbedDocument(ctx, text)
+	}
+	return e.Embed(ctx, text)
+}
+
+// EmbedQuery embeds a search query, using e's query mode if it has one.
+func EmbedQuery(ctx context.Context, e Embedder, text string) (Vector, error) {
+	if a, ok := e.(AsymmetricEmbedder); ok {
+		return a.EmbedQuery(ctx, text)
+	}
+	return e.Embed(ctx, text)
 }
 
 // CosineSimilarity computes cosine similarity between two vectors.
@@ -39,14 +70,129 @@ func CosineSimilarity(a, b Vector) float64 {
 	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
 }
 
+// dimsCache remembers the vector length a provider actually returns, so
+// Dims reflects the model in use rather than a guess from its name.
+type dimsCache struct {
+	mu    sync.Mutex
+	dims  int // 0 until a vector has been seen
+	probe sync.Once
+}
+
+// observe records the length of a vector the provider returned.
+func (c *dimsCache) observe(v Vector) {
+	if len(v) == 0 {
+		return
+	}
+	c.mu.Lock()
+	c.dims = len(v)
+	c.mu.Unlock()
+}
+
+func (c *dimsCache) known() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dims
+}
+
+// get returns the observed dims. If none has been seen it embeds a probe
+// once; if that fails (e.g. offline) it returns fallback without retrying.
+func (c *dimsCache) get(embed func(ctx context.Context, text string) (Vector, error), fallback int) int {
+	if d := c.known(); d > 0 {
+		return d
+	}
+	c.probe.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if v, err := embed(ctx, "dimension probe"); err == nil {
+			c.observe(v)
+		}
+	})
+	if d := c.known(); d > 0 {
+		return d
+	}
+	return fallback
+}
+
+// DefaultTimeout is how long an embedding request may take before it fails.
+// Override with $AGENT_MEMORY_EMBED_TIMEOUT or a constructor's timeout.
+const DefaultTimeout = 30 * time.Second
+
+// newHTTPClient returns the client providers send requests with. It honors
+// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// timeoutFromEnv reads $AGENT_MEMORY_EMBED_TIMEOUT as a duration (90s, 2m)
+// or whole seconds. Unset or invalid values mean DefaultTimeout.
+func timeoutFromEnv() time.Duration {
+	env := os.Getenv("AGENT_MEMORY_EMBED_TIMEOUT")
+	if env == "" {
+		return DefaultTimeout
+	}
+	if d, err := time.ParseDuration(env); err == nil && d > 0 {
+		return d
+	}
+	if n, err := strconv.Atoi(env); err == nil && n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return DefaultTimeout
+}
+
 // --- Ollama Provider ---
 
+// ollamaDims lists the embedding length of common Ollama models, used only
+// when Ollama cannot be asked.
+var ollamaDims = map[string]int{
+	"nomic-embed-text":         768,
+	"all-minilm":               384,
+	"mxbai-embed-large":        1024,
+	"snowflake-arctic-embed":   1024,
+	"snowflake-arctic-embed:s": 384,
+	"snowflake-arctic-embed:m": 768,
+	"snowflake-arctic-embed2":  1024,
+	"bge-m3":                   1024,
+	"bge-large":                1024,
+	"granite-embedding":        384,
+	"paraphrase-multilingual":  768,
+}
+
+// ollamaModelDims looks up model, with or without its tag, in ollamaDims.
+// Unknown models get 768, the size of the default model.
+func ollamaModelDims(model string) int {
+	if d, ok := ollamaDims[model]; ok {
+		return d
+	}
+	name, tag, _ := strings.Cut(model, ":")
+	if d, ok := ollamaDims[name+":"+strings.SplitN(tag, "-", 2)[0]]; ok {
+		return d
+	}
+	if d, ok := ollamaDims[name]; ok {
+		return d
+	}
+	return 768
+}
+
+// ollamaPrefixes holds the document and query prefixes asymmetric Ollama
+// models were trained with. Models not listed embed text as-is.
+var ollamaPrefixes = map[string][2]string{
+	"nomic-embed-text":       {"search_document: ", "search_query: "},
+	"mxbai-embed-large":      {"", "Represent this sentence for searching relevant passages: "},
+	"snowflake-arctic-embed": {"", "Represent this sentence for searching relevant passages: "},
+}
+
 // OllamaEmbedder uses a local Ollama instance for embeddings.
 type OllamaEmbedder struct {
 	baseURL string
 	model   string
-	dims    int
+	dims    int // from ollamaDims; the fallback when the model cannot be probed
 	client  *http.Client
+	actual  dimsCache
 }
 
 type ollamaRequest struct {
@@ -58,22 +204,18 @@ type ollamaResponse struct {
 	Embedding []float32 `json:"embedding"`
 }
 
-// NewOllamaEmbedder creates an embedder using Ollama's API.
-// Default model: nomic-embed-text (768 dims), all-minilm (384 dims).
-func NewOllamaEmbedder(model string) *OllamaEmbedder {
+// NewOllamaEmbedder creates an embedder using Ollama's API. A zero timeout
+// means DefaultTimeout. Default model: nomic-embed-text (768 dims).
+func NewOllamaEmbedder(model string, timeout time.Duration) *OllamaEmbedder {
 	baseURL := os.Getenv("OLLAMA_HOST")
 	if baseURL == "" {
 		baseURL = "http://localhost:11434"
 	}
-	dims := 768 // default for nomic-embed-text
-	if model == "all-minilm" {
-		dims = 384
-	}
 	return &OllamaEmbedder{
 		baseURL: baseURL,
 		model:   model,
-		dims:    dims,
-		client:  &http.Client{Timeout: 30 * time.Second},
+		dims:    ollamaModelDims(model),
+		client:  newHTTPClient(timeout),
 	}
 }
 
@@ -100,10 +242,32 @@ func (e *OllamaEmbedder) Embed(ctx context.Context, text string) (Vector, error)
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
+	e.actual.observe(result.Embedding)
 	return result.Embedding, nil
 }
 
-func (e *OllamaEmbedder) Dims() int { return e.dims }
+// EmbedDocument embeds stored text, with the model's document prefix if it
+// has one (e.g. "search_document: " for nomic-embed-text).
+func (e *OllamaEmbedder) EmbedDocument(ctx context.Context, text string) (Vector, error) {
+	return e.Embed(ctx, e.prefixes()[0]+text)
+}
+
+// EmbedQuery embeds a search query, with the model's query prefix if it has one.
+func (e *OllamaEmbedder) EmbedQuery(ctx context.Context, text string) (Vector, error) {
+	return e.Embed(ctx, e.prefixes()[1]+text)
+}
+
+func (e *OllamaEmbedder) prefixes() [2]string {
+	name, _, _ := strings.Cut(e.model, ":")
+	return ollamaPrefixes[name]
+}
+
+// Dims returns the length of the vectors Ollama returns for the model,
+// asking it once if no Embed call has yet. Offline, it falls back to the
+// known size for the model name.
+func (e *OllamaEmbedder) Dims() int { return e.actual.get(e.Embed, e.dims) }
+
+func (e *OllamaEmbedder) Model() string { return e.model }
 
 // --- OpenAI-compatible Provider ---
 
@@ -112,8 +276,9 @@ type OpenAIEmbedder struct {
 	baseURL string
 	apiKey  string
 	model   string
-	dims    int
+	dims    int // the constructor's hint; the fallback when the API cannot be probed
 	client  *http.Client
+	actual  dimsCache
 }
 
 type openaiEmbedRequest struct {
@@ -127,8 +292,9 @@ type openaiEmbedResponse struct {
 	} `json:"data"`
 }
 
-// NewOpenAIEmbedder creates an embedder using an OpenAI-compatible API.
-func NewOpenAIEmbedder(baseURL, apiKey, model string, dims int) *OpenAIEmbedder {
+// NewOpenAIEmbedder creates an embedder using an OpenAI-compatible API. A
+// zero timeout means DefaultTimeout.
+func NewOpenAIEmbedder(baseURL, apiKey, model string, dims int, timeout time.Duration) *OpenAIEmbedder {
 	if baseURL == "" {
 		baseURL = "https://api.openai.com/v1"
 	}
@@ -143,7 +309,7 @@ func NewOpenAIEmbedder(baseURL, apiKey, model string, dims int) *OpenAIEmbedder
 		apiKey:  apiKey,
 		model:   model,
 		dims:    dims,
-		client:  &http.Client{Timeout: 30 * time.Second},
+		client:  newHTTPClient(timeout),
 	}
 }
 
@@ -176,32 +342,268 @@ func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) (Vector, error)
 	if len(result.Data) == 0 {
 		return nil, fmt.Errorf("no embedding returned")
 	}
+	e.actual.observe(result.Data[0].Embedding)
 	return result.Data[0].Embedding, nil
 }
 
-func (e *OpenAIEmbedder) Dims() int { return e.dims }
+// Dims returns the length of the vectors the API returns, asking it once
+// if no Embed call has yet. If it cannot be reached, it falls back to the
+// dims given to the constructor.
+func (e *OpenAIEmbedder) Dims() int { return e.actual.get(e.Embed, e.dims) }
+
+func (e *OpenAIEmbedder) Model() string { return e.model }
+
+// --- Google Gemini Provider ---
+
+// GeminiEmbedder uses the Gemini API's embedContent endpoint.
+type GeminiEmbedder struct {
+	baseURL string
+	apiKey  string
+	model   string
+	dims    int // the constructor's hint; the fallback when the API cannot be probed
+	client  *http.Client
+	actual  dimsCache
+}
+
+type geminiEmbedRequest struct {
+	Model   string        `json:"model"`
+	Content geminiContent `json:"content"`
+}
+
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiEmbedResponse struct {
+	Embedding struct {
+		Values []float32 `json:"values"`
+	} `json:"embedding"`
+}
+
+// NewGeminiEmbedder creates an embedder using the Gemini API. A zero timeout
+// means DefaultTimeout. Default model: text-embedding-004 (768 dims).
+func NewGeminiEmbedder(baseURL, apiKey, model string, dims int, timeout time.Duration) *GeminiEmbedder {
+	if baseURL == "" {
+		baseURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	if model == "" {
+		model = "text-embedding-004"
+	}
+	if dims == 0 {
+		dims = 768
+	}
+	return &GeminiEmbedder{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		model:   model,
+		dims:    dims,
+		client:  newHTTPClient(timeout),
+	}
+}
+
+func (e *GeminiEmbedder) Embed(ctx context.Context, text string) (Vector, error) {
+	body, _ := json.Marshal(geminiEmbedRequest{
+		Model:   "models/" + e.model,
+		Content: geminiContent{Parts: []geminiPart{{Text: text}}},
+	})
+	req, err := http.NewRequestWithContext(ctx, "POST",
+		e.baseURL+"/models/"+e.model+":embedContent", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		// A header rather than ?key= so the key never appears in error messages.
+		req.Header.Set("x-goog-api-key", e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gemini request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("gemini error %d: %s", resp.StatusCode, string(b))
+	}
+
+	var result geminiEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.Embedding.Values) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+	e.actual.observe(result.Embedding.Values)
+	return result.Embedding.Values, nil
+}
+
+// Dims returns the length of the vectors the API returns, asking it once
+// if no Embed call has yet. If it cannot be reached, it falls back to the
+// dims given to the constructor.
+func (e *GeminiEmbedder) Dims() int { return e.actual.get(e.Embed, e.dims) }
+
+func (e *GeminiEmbedder) Model() string { return e.model }
+
+// --- Cohere Provider ---
+
+// Cohere input types for stored text and for search queries.
+const (
+	cohereDocument = "search_document"
+	cohereQuery    = "search_query"
+)
+
+// CohereEmbedder uses Cohere's v2 embed API. It is asymmetric: documents
+// and queries are embedded with different input types.
+type CohereEmbedder struct {
+	baseURL string
+	apiKey  string
+	model   string
+	dims    int // the constructor's hint; the fallback when the API cannot be probed
+	client  *http.Client
+	actual  dimsCache
+}
+
+type cohereEmbedRequest struct {
+	Model          string   `json:"model"`
+	Texts          []string `json:"texts"`
+	InputType      string   `json:"input_type"`
+	EmbeddingTypes []string `json:"embedding_types"`
+}
+
+type cohereEmbedResponse struct {
+	Embeddings struct {
+		Float [][]float32 `json:"float"`
+	} `json:"embeddings"`
+}
+
+// NewCohereEmbedder creates an embedder using Cohere's API. A zero timeout
+// means DefaultTimeout. Default model: embed-english-v3.0 (1024 dims).
+func NewCohereEmbedder(baseURL, apiKey, model string, dims int, timeout time.Duration) *CohereEmbedder {
+	if baseURL == "" {
+		baseURL = "https://api.cohere.com/v2"
+	}
+	if model == "" {
+		model = "embed-english-v3.0"
+	}
+	if dims == 0 {
+		dims = 1024
+	}
+	return &CohereEmbedder{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		model:   model,
+		dims:    dims,
+		client:  newHTTPClient(timeout),
+	}
+}
+
+// Embed embeds text as a document.
+func (e *CohereEmbedder) Embed(ctx context.Context, text string) (Vector, error) {
+	return e.embed(ctx, text, cohereDocument)
+}
+
+func (e *CohereEmbedder) EmbedDocument(ctx context.Context, text string) (Vector, error) {
+	return e.embed(ctx, text, cohereDocument)
+}
+
+func (e *CohereEmbedder) EmbedQuery(ctx context.Context, text string) (Vector, error) {
+	return e.embed(ctx, text, cohereQuery)
+}
+
+func (e *CohereEmbedder) embed(ctx context.Context, text, inputType string) (Vector, error) {
+	body, _ := json.Marshal(cohereEmbedRequest{
+		Model:          e.model,
+		Texts:          []string{text},
+		InputType:      inputType,
+		EmbeddingTypes: []string{"float"},
+	})
+	req, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cohere request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("cohere error %d: %s", resp.StatusCode, string(b))
+	}
+
+	var result cohereEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.Embeddings.Float) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+	e.actual.observe(result.Embeddings.Float[0])
+	return result.Embeddings.Float[0], nil
+}
+
+// Dims returns the length of the vectors the API returns, asking it once
+// if no Embed call has yet. If it cannot be reached, it falls back to the
+// dims given to the constructor.
+func (e *CohereEmbedder) Dims() int { return e.actual.get(e.Embed, e.dims) }
+
+func (e *CohereEmbedder) Model() string { return e.model }
 
 // --- Factory ---
 
 // NewFromEnv creates an embedder from environment variables.
-// AGENT_MEMORY_EMBED_PROVIDER: "ollama" | "openai" | "" (disabled)
+// AGENT_MEMORY_EMBED_PROVIDER: "ollama" | "openai" | "gemini" | "cohere" | "" (disabled)
 // AGENT_MEMORY_EMBED_MODEL: model name
 // AGENT_MEMORY_EMBED_URL: base URL override
+// AGENT_MEMORY_EMBED_TIMEOUT: per-request timeout, e.g. 90s (default 30s)
+// AGENT_MEMORY_EMBED_RPM: max embedding requests per minute (default unlimited)
 // OPENAI_API_KEY: for openai provider
+// GOOGLE_API_KEY: for gemini provider
+// COHERE_API_KEY: for cohere provider
 func NewFromEnv() Embedder {
+	e := providerFromEnv()
+	if e == nil {
+		return nil
+	}
+	rpm, _ := strconv.Atoi(os.Getenv("AGENT_MEMORY_EMBED_RPM"))
+	return RateLimited(e, rpm)
+}
+
+func providerFromEnv() Embedder {
 	provider := os.Getenv("AGENT_MEMORY_EMBED_PROVIDER")
 	model := os.Getenv("AGENT_MEMORY_EMBED_MODEL")
+	timeout := timeoutFromEnv()
 
 	switch provider {
 	case "ollama":
 		if model == "" {
 			model = "nomic-embed-text"
 		}
-		return NewOllamaEmbedder(model)
+		return NewOllamaEmbedder(model, timeout)
 	case "openai":
 		url := os.Getenv("AGENT_MEMORY_EMBED_URL")
 		key := os.Getenv("OPENAI_API_KEY")
-		return NewOpenAIEmbedder(url, key, model, 0)
+		return NewOpenAIEmbedder(url, key, model, 0, timeout)
+	case "gemini":
+		url := os.Getenv("AGENT_MEMORY_EMBED_URL")
+		key := os.Getenv("GOOGLE_API_KEY")
+		return NewGeminiEmbedder(url, key, model, 0, timeout)
+	case "cohere":
+		url := os.Getenv("AGENT_MEMORY_EMBED_URL")
+		key := os.Getenv("COHERE_API_KEY")
+		return NewCohereEmbedder(url, key, model, 0, timeout)
 	default:
 		return nil // embeddings disabled
 	}
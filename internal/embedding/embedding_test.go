@@ -1,8 +1,14 @@
 package embedding
 
 import (
+	"encoding/json"
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestCosineSimilarity(t *testing.T) {
@@ -38,3 +44,204 @@ func TestNewFromEnv_Disabled(t *testing.T) {
 		t.Error("expected nil embedder when no provider configured")
 	}
 }
+
+func TestOllamaDimsProbed(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"embedding":[0.1,0.2,0.3,0.4,0.5]}`))
+	}))
+	defer srv.Close()
+	t.Setenv("OLLAMA_HOST", srv.URL)
+
+	e := NewOllamaEmbedder("nomic-embed-text", 0)
+	if d := e.Dims(); d != 5 {
+		t.Errorf("expected the probed length 5, got %d", d)
+	}
+	e.Dims()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected one probe request, got %d", n)
+	}
+
+	// A real Embed result is enough; no probe is needed.
+	e = NewOllamaEmbedder("nomic-embed-text", 0)
+	e.Embed(t.Context(), "text")
+	e.Dims()
+	if n := calls.Load(); n != 2 {
+		t.Errorf("expected Dims to reuse the Embed result, got %d requests", n)
+	}
+}
+
+func TestOllamaDimsOffline(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	t.Setenv("OLLAMA_HOST", srv.URL)
+
+	for model, want := range map[string]int{
+		"mxbai-embed-large":        1024,
+		"all-minilm:latest":        384,
+		"snowflake-arctic-embed:s": 384,
+		"some-new-model":           768,
+	} {
+		if d := NewOllamaEmbedder(model, 0).Dims(); d != want {
+			t.Errorf("%s: expected fallback %d, got %d", model, want, d)
+		}
+	}
+}
+
+func TestOpenAIDimsProbed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[{"embedding":[0.1,0.2,0.3]}]}`))
+	}))
+	defer srv.Close()
+
+	// The constructor's 1536 is only a hint; the API returns 3.
+	e := NewOpenAIEmbedder(srv.URL, "k", "", 0, 0)
+	if d := e.Dims(); d != 3 {
+		t.Errorf("expected the probed length 3, got %d", d)
+	}
+
+	srv.Close()
+	e = NewOpenAIEmbedder(srv.URL, "k", "", 256, 0)
+	if d := e.Dims(); d != 256 {
+		t.Errorf("expected the constructor hint 256 when offline, got %d", d)
+	}
+}
+
+func TestGeminiEmbedder(t *testing.T) {
+	var req geminiEmbedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/text-embedding-004:embedContent" || r.Header.Get("x-goog-api-key") != "k" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"embedding":{"values":[0.1,0.2,0.3,0.4]}}`))
+	}))
+	defer srv.Close()
+
+	e := NewGeminiEmbedder(srv.URL, "k", "", 0, 0)
+	v, err := e.Embed(t.Context(), "hello")
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(v) != 4 || e.Dims() != 4 {
+		t.Errorf("expected 4 dims, got %d (Dims %d)", len(v), e.Dims())
+	}
+	if req.Model != "models/text-embedding-004" || len(req.Content.Parts) != 1 || req.Content.Parts[0].Text != "hello" {
+		t.Errorf("unexpected request body: %+v", req)
+	}
+
+	bad := NewGeminiEmbedder(srv.URL, "wrong", "", 0, 0)
+	if _, err := bad.Embed(t.Context(), "hello"); err == nil || !strings.Contains(err.Error(), "gemini error 400") {
+		t.Errorf("expected a gemini error, got %v", err)
+	}
+}
+
+func TestNewFromEnv_Gemini(t *testing.T) {
+	t.Setenv("AGENT_MEMORY_EMBED_PROVIDER", "gemini")
+	t.Setenv("GOOGLE_API_KEY", "k")
+	e, ok := NewFromEnv().(*GeminiEmbedder)
+	if !ok {
+		t.Fatal("expected a GeminiEmbedder")
+	}
+	if e.Model() != "text-embedding-004" || e.apiKey != "k" {
+		t.Errorf("unexpected embedder: model %q", e.Model())
+	}
+}
+
+func TestCohereInputTypes(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req cohereEmbedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/embed" || r.Header.Get("Authorization") != "Bearer k" || len(req.Texts) != 1 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		got = append(got, req.InputType)
+		w.Write([]byte(`{"embeddings":{"float":[[0.1,0.2]]}}`))
+	}))
+	defer srv.Close()
+
+	e := NewCohereEmbedder(srv.URL, "k", "", 0, 0)
+	ctx := t.Context()
+	if _, err := EmbedDocument(ctx, e, "stored"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EmbedQuery(ctx, e, "searched"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Embed(ctx, "plain"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"search_document", "search_query", "search_document"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("input types = %v, want %v", got, want)
+	}
+	if e.Dims() != 2 {
+		t.Errorf("expected 2 dims, got %d", e.Dims())
+	}
+}
+
+func TestOllamaPrefixes(t *testing.T) {
+	var prompts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		prompts = append(prompts, req.Prompt)
+		w.Write([]byte(`{"embedding":[0.1,0.2]}`))
+	}))
+	defer srv.Close()
+	t.Setenv("OLLAMA_HOST", srv.URL)
+	ctx := t.Context()
+
+	nomic := NewOllamaEmbedder("nomic-embed-text:latest", 0)
+	EmbedDocument(ctx, nomic, "stored")
+	EmbedQuery(ctx, nomic, "searched")
+	nomic.Embed(ctx, "raw")
+
+	minilm := NewOllamaEmbedder("all-minilm", 0)
+	EmbedDocument(ctx, minilm, "stored")
+	EmbedQuery(ctx, minilm, "searched")
+
+	want := []string{"search_document: stored", "search_query: searched", "raw", "stored", "searched"}
+	if strings.Join(prompts, "|") != strings.Join(want, "|") {
+		t.Errorf("prompts = %q, want %q", prompts, want)
+	}
+}
+
+func TestEmbedTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"data":[{"embedding":[0.1]}]}`))
+	}))
+	defer srv.Close()
+
+	e := NewOpenAIEmbedder(srv.URL, "", "", 0, 50*time.Millisecond)
+	start := time.Now()
+	if _, err := e.Embed(t.Context(), "slow"); err == nil {
+		t.Fatal("expected the slow server to trip the timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("timeout took %v", elapsed)
+	}
+}
+
+func TestTimeoutFromEnv(t *testing.T) {
+	for env, want := range map[string]time.Duration{
+		"":      DefaultTimeout,
+		"90s":   90 * time.Second,
+		"2m":    2 * time.Minute,
+		"45":    45 * time.Second,
+		"bogus": DefaultTimeout,
+	} {
+		t.Setenv("AGENT_MEMORY_EMBED_TIMEOUT", env)
+		if got := timeoutFromEnv(); got != want {
+			t.Errorf("%q: got %v, want %v", env, got, want)
+		}
+	}
+}
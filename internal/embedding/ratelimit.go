@@ -0,0 +1,88 @@
+package embedding
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimited wraps e so that all its embed calls together stay within
+// perMinute requests per minute. Calls over the limit wait for their turn
+// (or for ctx to be done) instead of failing. perMinute <= 0 returns e
+// unchanged. Document and query modes of asymmetric embedders are kept.
+func RateLimited(e Embedder, perMinute int) Embedder {
+	if e == nil || perMinute <= 0 {
+		return e
+	}
+	return &rateLimited{
+		Embedder: e,
+		bucket:   newTokenBucket(float64(perMinute)/60, 1),
+	}
+}
+
+type rateLimited struct {
+	Embedder
+	bucket *tokenBucket
+}
+
+func (r *rateLimited) Embed(ctx context.Context, text string) (Vector, error) {
+	if err := r.bucket.wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.Embedder.Embed(ctx, text)
+}
+
+func (r *rateLimited) EmbedDocument(ctx context.Context, text string) (Vector, error) {
+	if err := r.bucket.wait(ctx); err != nil {
+		return nil, err
+	}
+	return EmbedDocument(ctx, r.Embedder, text)
+}
+
+func (r *rateLimited) EmbedQuery(ctx context.Context, text string) (Vector, error) {
+	if err := r.bucket.wait(ctx); err != nil {
+		return nil, err
+	}
+	return EmbedQuery(ctx, r.Embedder, text)
+}
+
+// tokenBucket refills at rate tokens per second up to burst tokens.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes a token, sleeping until one is available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingEmbedder returns a fixed vector and counts calls by mode.
+type countingEmbedder struct {
+	calls, docs, queries int
+}
+
+func (c *countingEmbedder) Embed(ctx context.Context, text string) (Vector, error) {
+	c.calls++
+	return Vector{1, 0}, nil
+}
+
+func (c *countingEmbedder) EmbedDocument(ctx context.Context, text string) (Vector, error) {
+	c.docs++
+	return c.Embed(ctx, text)
+}
+
+func (c *countingEmbedder) EmbedQuery(ctx context.Context, text string) (Vector, error) {
+	c.queries++
+	return c.Embed(ctx, text)
+}
+
+func (c *countingEmbedder) Dims() int     { return 2 }
+func (c *countingEmbedder) Model() string { return "counting" }
+
+func TestRateLimited(t *testing.T) {
+	inner := &countingEmbedder{}
+	e := RateLimited(inner, 1200) // one request every 50ms
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := e.Embed(ctx, "x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first call is free; the next three each wait ~50ms.
+	if elapsed := time.Since(start); elapsed < 130*time.Millisecond {
+		t.Errorf("expected calls to be spaced out, took %v", elapsed)
+	}
+
+	EmbedDocument(ctx, e, "doc")
+	EmbedQuery(ctx, e, "query")
+	if inner.docs != 1 || inner.queries != 1 {
+		t.Errorf("expected modes to pass through, got docs=%d queries=%d", inner.docs, inner.queries)
+	}
+	if e.Model() != "counting" || e.Dims() != 2 {
+		t.Error("expected Model and Dims to pass through")
+	}
+}
+
+func TestRateLimitedRespectsContext(t *testing.T) {
+	e := RateLimited(&countingEmbedder{}, 1) // one request per minute
+	e.Embed(context.Background(), "first")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := e.Embed(ctx, "second"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait to end with the context, got %v", err)
+	}
+}
+
+func TestRateLimitedDisabled(t *testing.T) {
+	inner := &countingEmbedder{}
+	if RateLimited(inner, 0) != Embedder(inner) {
+		t.Error("expected a non-positive rate to leave the embedder unwrapped")
+	}
+}
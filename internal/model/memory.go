@@ -20,17 +20,19 @@ type Memory struct {
 	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
 	Meta           string     `json:"meta,omitempty"`
 	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Actor          string     `json:"actor,omitempty"`
 	ChunkCount     int        `json:"chunks,omitempty"`
 }
 
 // Chunk represents an internal text chunk of a memory.
 type Chunk struct {
-	ID        string `json:"id"`
-	MemoryID  string `json:"memory_id"`
-	Seq       int    `json:"seq"`
-	Text      string `json:"text"`
-	StartLine int    `json:"start_line,omitempty"`
-	EndLine   int    `json:"end_line,omitempty"`
+	ID           string `json:"id"`
+	MemoryID     string `json:"memory_id"`
+	Seq          int    `json:"seq"`
+	Text         string `json:"text"`
+	StartLine    int    `json:"start_line,omitempty"`
+	EndLine      int    `json:"end_line,omitempty"`
+	HasEmbedding bool   `json:"has_embedding"`
 }
 
 // ValidKinds are the allowed memory kinds.
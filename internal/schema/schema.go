@@ -0,0 +1,248 @@
+// Package schema validates JSON values against a subset of JSON Schema.
+//
+// Supported keywords: type, enum, properties, required,
+// additionalProperties (boolean), items, minItems, maxItems, minLength,
+// maxLength, minimum, and maximum. Unknown keywords are ignored, as the
+// specification allows.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a parsed JSON Schema.
+type Schema struct {
+	Type                 typeList           `json:"type,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+}
+
+// ValidationError lists every way a value failed its schema.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "schema validation failed: " + strings.Join(e.Problems, "; ")
+}
+
+var validTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// typeList accepts "type" as either a single name or a list of names.
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*t = typeList{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return fmt.Errorf("type must be a string or array of strings")
+	}
+	*t = many
+	return nil
+}
+
+// Parse decodes and checks a schema document.
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	if err := s.check("$"); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// check rejects unknown type names anywhere in the schema.
+func (s *Schema) check(path string) error {
+	for _, t := range s.Type {
+		if !validTypes[t] {
+			return fmt.Errorf("%s: unknown type %q", path, t)
+		}
+	}
+	for name, p := range s.Properties {
+		if p == nil {
+			continue
+		}
+		if err := p.check(path + "." + name); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.check(path + "[]")
+	}
+	return nil
+}
+
+// ValidateJSON validates a JSON document. It returns a *ValidationError
+// listing every problem, or an error if data is not valid JSON.
+func (s *Schema) ValidateJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return s.Validate(v)
+}
+
+// Validate validates a decoded JSON value (as produced by encoding/json,
+// with or without UseNumber).
+func (s *Schema) Validate(v interface{}) error {
+	var problems []string
+	s.validate("$", v, &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, v interface{}, problems *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if len(s.Type) > 0 && !s.Type.matches(v) {
+		fail("expected %s, got %s", strings.Join(s.Type, " or "), typeName(v))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		fail("value is not one of the allowed values")
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if p, ok := s.Properties[name]; ok && p != nil {
+				p.validate(path+"."+name, val[name], problems)
+			} else if !ok && s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				fail("unexpected property %q", name)
+			}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			fail("expected at least %d items, got %d", *s.MinItems, len(val))
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			fail("expected at most %d items, got %d", *s.MaxItems, len(val))
+		}
+		if s.Items != nil {
+			for i, item := range val {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(val)
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("expected at least %d characters, got %d", *s.MinLength, n)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("expected at most %d characters, got %d", *s.MaxLength, n)
+		}
+	default:
+		if f, ok := toFloat(v); ok {
+			if s.Minimum != nil && f < *s.Minimum {
+				fail("expected at least %v, got %v", *s.Minimum, f)
+			}
+			if s.Maximum != nil && f > *s.Maximum {
+				fail("expected at most %v, got %v", *s.Maximum, f)
+			}
+		}
+	}
+}
+
+func (t typeList) matches(v interface{}) bool {
+	name := typeName(v)
+	for _, want := range t {
+		if want == name || (want == "number" && name == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName returns the JSON Schema type of a decoded value.
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if f, ok := toFloat(v); ok {
+		if f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// inEnum compares v to each allowed value by its JSON encoding.
+func inEnum(v interface{}, enum []interface{}) bool {
+	got, _ := json.Marshal(normalize(v))
+	for _, e := range enum {
+		want, _ := json.Marshal(normalize(e))
+		if bytes.Equal(got, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize turns json.Number into float64 so equal numbers encode alike.
+func normalize(v interface{}) interface{} {
+	if f, ok := toFloat(v); ok {
+		return f
+	}
+	return v
+}
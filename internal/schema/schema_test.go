@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["source"],
+	"additionalProperties": false,
+	"properties": {
+		"source": {"type": "string", "enum": ["web", "chat"]},
+		"score":  {"type": "integer", "minimum": 0, "maximum": 10},
+		"title":  {"type": "string", "minLength": 2, "maxLength": 5},
+		"tags":   {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+		"note":   {"type": ["string", "null"]}
+	}
+}`
+
+func TestValidate_OK(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []string{
+		`{"source":"web"}`,
+		`{"source":"chat","score":10,"title":"héllo","tags":["a","b"],"note":null}`,
+		`{"source":"web","score":3.0}`,
+	} {
+		if err := s.ValidateJSON([]byte(doc)); err != nil {
+			t.Errorf("%s: unexpected error: %v", doc, err)
+		}
+	}
+}
+
+func TestValidate_Problems(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		doc  string
+		want []string
+	}{
+		{`[]`, []string{"$: expected object, got array"}},
+		{`{}`, []string{`$: missing required property "source"`}},
+		{`{"source":"mail"}`, []string{"$.source: value is not one of the allowed values"}},
+		{`{"source":"web","extra":1}`, []string{`$: unexpected property "extra"`}},
+		{`{"source":"web","score":1.5}`, []string{"$.score: expected integer, got number"}},
+		{`{"source":"web","score":11}`, []string{"$.score: expected at most 10, got 11"}},
+		{`{"source":"web","title":"a"}`, []string{"$.title: expected at least 2 characters, got 1"}},
+		{`{"source":"web","tags":["a",1,"c"]}`, []string{
+			"$.tags: expected at most 2 items, got 3",
+			"$.tags[1]: expected string, got integer",
+		}},
+	}
+	for _, tt := range tests {
+		err := s.ValidateJSON([]byte(tt.doc))
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: expected ValidationError, got %v", tt.doc, err)
+			continue
+		}
+		if strings.Join(verr.Problems, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s: expected %q, got %q", tt.doc, tt.want, verr.Problems)
+		}
+	}
+}
+
+func TestValidateJSON_Invalid(t *testing.T) {
+	s, _ := Parse([]byte(`{}`))
+	err := s.ValidateJSON([]byte(`not json`))
+	var verr *ValidationError
+	if err == nil || errors.As(err, &verr) {
+		t.Errorf("expected a JSON syntax error, got %v", err)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, doc := range []string{
+		`not json`,
+		`{"type":"thing"}`,
+		`{"type":7}`,
+		`{"properties":{"a":{"items":{"type":"map"}}}}`,
+	} {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("%s: expected error", doc)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package server provides the HTTP endpoints of agent-memory serve.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/embedding"
+	"github.com/rcliao/agent-memory/internal/store"
+)
+
+// healthTimeout bounds each dependency check made by /health.
+const healthTimeout = 5 * time.Second
+
+// embedderCheckTTL is how long an embedder check is reused. Each check is a
+// real (possibly billed and rate-limited) embedding request, so frequent
+// probes must not each make one.
+const embedderCheckTTL = time.Minute
+
+// Health is the body of a /health response.
+type Health struct {
+	Status   string `json:"status"` // ok, degraded (embedder down), or down (db down)
+	DB       string `json:"db"`
+	Embedder string `json:"embedder"` // ok, disabled, or the error
+}
+
+// Handler serves /health and /metrics for s. /health checks the store's
+// embedder at most once per embedderCheckTTL.
+func Handler(s *store.SQLiteStore) http.Handler {
+	probe := &embedderProbe{e: s.Embedder()}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		h := Health{Status: "ok", DB: "ok", Embedder: probe.check(r.Context())}
+
+		// The service is down without its database but only degraded
+		// without the embedder, since keyword search still works.
+		ctx, cancel := context.WithTimeout(r.Context(), healthTimeout)
+		defer cancel()
+		if err := s.Ping(ctx); err != nil {
+			h.Status, h.DB = "down", err.Error()
+		} else if h.Embedder != "ok" && h.Embedder != "disabled" {
+			h.Status = "degraded"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if h.Status == "down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(h)
+	})
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := s.WriteMetrics(r.Context(), w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	return mux
+}
+
+// embedderProbe caches the result of embedding a short text.
+type embedderProbe struct {
+	e embedding.Embedder
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	result    string
+}
+
+// check returns "disabled", "ok", or the error of the last embedding
+// request, making a new one if the cached result is older than
+// embedderCheckTTL.
+func (p *embedderProbe) check(ctx context.Context) string {
+	if p.e == nil {
+		return "disabled"
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.checkedAt.IsZero() && time.Since(p.checkedAt) < embedderCheckTTL {
+		return p.result
+	}
+
+	embedCtx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+	result := "ok"
+	if _, err := p.e.Embed(embedCtx, "ping"); err != nil {
+		result = err.Error()
+	}
+	if ctx.Err() == nil {
+		// Don't cache a failure caused by the client going away
+		p.checkedAt, p.result = time.Now(), result
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rcliao/agent-memory/internal/embedding"
+	"github.com/rcliao/agent-memory/internal/store"
+)
+
+type stubEmbedder struct {
+	err   error
+	calls int
+}
+
+func (s *stubEmbedder) Embed(ctx context.Context, text string) (embedding.Vector, error) {
+	s.calls++
+	return embedding.Vector{1}, s.err
+}
+func (*stubEmbedder) Dims() int     { return 1 }
+func (*stubEmbedder) Model() string { return "stub" }
+
+func newStore(t *testing.T, e embedding.Embedder) *store.SQLiteStore {
+	t.Helper()
+	s, err := store.NewMemoryStore(store.WithEmbedder(e))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func getHealth(t *testing.T, h http.Handler) (int, Health) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	var body Health
+	json.NewDecoder(rec.Body).Decode(&body)
+	return rec.Code, body
+}
+
+func TestHealth(t *testing.T) {
+	s := newStore(t, nil)
+	code, h := getHealth(t, Handler(s))
+	if code != 200 || h != (Health{Status: "ok", DB: "ok", Embedder: "disabled"}) {
+		t.Errorf("no embedder: %d %+v", code, h)
+	}
+
+	code, h = getHealth(t, Handler(newStore(t, &stubEmbedder{})))
+	if code != 200 || h.Status != "ok" || h.Embedder != "ok" {
+		t.Errorf("healthy embedder: %d %+v", code, h)
+	}
+	code, h = getHealth(t, Handler(newStore(t, &stubEmbedder{err: errors.New("connection refused")})))
+	if code != 200 || h.Status != "degraded" || h.Embedder != "connection refused" {
+		t.Errorf("failing embedder: %d %+v", code, h)
+	}
+
+	s.Close()
+	code, h = getHealth(t, Handler(s))
+	if code != http.StatusServiceUnavailable || h.Status != "down" {
+		t.Errorf("closed db: %d %+v", code, h)
+	}
+}
+
+func TestHealthCachesEmbedderCheck(t *testing.T) {
+	e := &stubEmbedder{}
+	h := Handler(newStore(t, e))
+	for i := 0; i < 3; i++ {
+		getHealth(t, h)
+	}
+	if e.calls != 1 {
+		t.Errorf("expected one embedding request across probes, got %d", e.calls)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	s := newStore(t, nil)
+	ctx := context.Background()
+	s.Put(ctx, store.PutParams{NS: "ns", Key: "a", Content: "hello world"})
+	s.Get(ctx, store.GetParams{NS: "ns", Key: "a"})
+	s.Search(ctx, store.SearchParams{Query: "hello"})
+	s.Search(ctx, store.SearchParams{Query: "world"})
+
+	rec := httptest.NewRecorder()
+	Handler(s).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	out := string(body)
+	if rec.Code != 200 || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{
+		`agent_memory_ops_total{op="put"} 1`,
+		`agent_memory_ops_total{op="get"} 1`,
+		`agent_memory_ops_total{op="search"} 2`,
+		`agent_memory_search_duration_seconds_bucket{le="+Inf"} 2`,
+		`agent_memory_search_duration_seconds_count 2`,
+		"agent_memory_memories 1\n",
+		"agent_memory_chunks 1\n",
+		"# TYPE agent_memory_search_duration_seconds histogram",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
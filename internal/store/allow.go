@@ -0,0 +1,41 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ErrNSNotAllowed is returned by writes to a namespace outside the allow-list.
+var ErrNSNotAllowed = errors.New("namespace not allowed")
+
+// SetAllowedNamespaces restricts writes of new memory versions to namespaces
+// matching one of patterns. Patterns are exact names or globs such as
+// "project:*". An empty list allows every namespace.
+func (s *SQLiteStore) SetAllowedNamespaces(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %w", p, err)
+		}
+	}
+	s.allowedNS = patterns
+	return nil
+}
+
+// checkNamespace rejects an empty namespace, and one that matches no
+// pattern when an allow-list is set.
+func (s *SQLiteStore) checkNamespace(ns string) error {
+	if strings.TrimSpace(ns) == "" {
+		return fmt.Errorf("namespace is required")
+	}
+	if len(s.allowedNS) == 0 {
+		return nil
+	}
+	for _, p := range s.allowedNS {
+		if ok, _ := path.Match(p, ns); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q (allowed: %s)", ErrNSNotAllowed, ns, strings.Join(s.allowedNS, ", "))
+}
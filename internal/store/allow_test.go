@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAllowedNamespaces(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.Put(ctx, PutParams{NS: " ", Key: "k", Content: "x"}); err == nil {
+		t.Error("expected an error for an empty namespace")
+	}
+
+	if err := s.SetAllowedNamespaces([]string{"project:*", "shared"}); err != nil {
+		t.Fatalf("SetAllowedNamespaces: %v", err)
+	}
+	for _, ns := range []string{"project:app", "shared"} {
+		if _, err := s.Put(ctx, PutParams{NS: ns, Key: "k", Content: "x"}); err != nil {
+			t.Errorf("put to %s: %v", ns, err)
+		}
+	}
+
+	_, err := s.Put(ctx, PutParams{NS: "scratch", Key: "k", Content: "x"})
+	if !errors.Is(err, ErrNSNotAllowed) || !strings.Contains(err.Error(), "allowed: project:*, shared") {
+		t.Errorf("expected ErrNSNotAllowed listing the allow-list, got %v", err)
+	}
+
+	// Batches and merges write through the same check
+	result, err := s.PutBatch(ctx, []PutParams{{NS: "shared", Key: "a", Content: "a"}, {NS: "scratch", Key: "b", Content: "b"}}, false)
+	if err != nil || result.Failed != 1 {
+		t.Errorf("expected the scratch entry to fail, got %+v, %v", result, err)
+	}
+	if _, err := s.Merge(ctx, MergeParams{IntoNS: "scratch", IntoKey: "m", From: []MemoryRef{{"shared", "k"}}}); !errors.Is(err, ErrNSNotAllowed) {
+		t.Errorf("expected merge into scratch to be rejected, got %v", err)
+	}
+
+	if err := s.SetAllowedNamespaces([]string{"["}); err == nil {
+		t.Error("expected an invalid pattern error")
+	}
+	s.SetAllowedNamespaces(nil)
+	if _, err := s.Put(ctx, PutParams{NS: "scratch", Key: "k", Content: "x"}); err != nil {
+		t.Errorf("expected every namespace allowed without a list, got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupResult reports the snapshot written by Backup.
+type BackupResult struct {
+	DBPath string `json:"db_path"`
+	Out    string `json:"out"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// Backup writes a consistent single-file snapshot of the database to out
+// using VACUUM INTO. It reads from one transaction, so it is safe while
+// other processes are writing, and the snapshot has no WAL file. The
+// snapshot is written next to out and renamed into place, so an existing
+// file is only replaced (with overwrite set) once the copy is complete.
+func (s *SQLiteStore) Backup(ctx context.Context, out string, overwrite bool) (*BackupResult, error) {
+	path, err := s.filePath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(out)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		if src, err := filepath.Abs(path); err == nil && src == abs {
+			return nil, fmt.Errorf("backup path %s is the database itself", out)
+		}
+	}
+	if info, err := os.Stat(abs); err == nil {
+		if info.IsDir() {
+			return nil, fmt.Errorf("backup path %s is a directory", out)
+		}
+		if !overwrite {
+			return nil, fmt.Errorf("backup file %s already exists (use --force to replace it)", out)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(abs), ".agent-memory-backup-*")
+	if err != nil {
+		return nil, fmt.Errorf("create backup: %w", err)
+	}
+	tmp.Close()
+	// VACUUM INTO refuses to write over a non-empty file, but an empty one is fine.
+	defer os.Remove(tmp.Name())
+
+	if _, err := s.db.ExecContext(ctx, `VACUUM INTO ?`, tmp.Name()); err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), abs); err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+
+	res := &BackupResult{DBPath: path, Out: abs}
+	if info, err := os.Stat(abs); err == nil {
+		res.Bytes = info.Size()
+	}
+	return res, nil
+}
+
+// RestoreResult reports what Restore replaced.
+type RestoreResult struct {
+	DBPath     string `json:"db_path"`
+	From       string `json:"from"`
+	SafetyCopy string `json:"safety_copy,omitempty"`
+	Memories   int    `json:"memories"`
+	Bytes      int64  `json:"bytes"`
+}
+
+// snapshotTables must exist in a snapshot for Restore to accept it. Columns
+// added since are filled in by the migration when the restored store opens.
+var snapshotTables = []string{"memories", "chunks", "memory_links", "store_meta"}
+
+// Restore replaces the database at dbPath with the snapshot at from. The
+// snapshot must pass integrity_check, have the store's tables, and not have
+// a newer schema version than this build. The
+// current database is kept as a safety copy next to it, and the snapshot is
+// copied beside it and renamed into place. No store may have dbPath open:
+// Restore takes exclusive access first and refuses if another connection
+// holds it.
+func Restore(ctx context.Context, dbPath, from string) (*RestoreResult, error) {
+	src, err := filepath.Abs(from)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := filepath.Abs(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if src == dst {
+		return nil, fmt.Errorf("snapshot %s is the database itself", from)
+	}
+	res := &RestoreResult{DBPath: dst, From: src}
+	if res.Memories, err = checkSnapshot(ctx, src); err != nil {
+		return nil, err
+	}
+
+	_, statErr := os.Stat(dst)
+	exists := statErr == nil
+	if exists {
+		if err := takeExclusive(ctx, dst); err != nil {
+			return nil, err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".agent-memory-restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("restore: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := copyInto(tmp, src); err != nil {
+		return nil, fmt.Errorf("restore: %w", err)
+	}
+
+	if exists {
+		res.SafetyCopy = fmt.Sprintf("%s.pre-restore-%s", dst, time.Now().UTC().Format("20060102T150405Z"))
+		if err := os.Rename(dst, res.SafetyCopy); err != nil {
+			return nil, fmt.Errorf("keep safety copy: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		if exists {
+			os.Rename(res.SafetyCopy, dst)
+		}
+		return nil, fmt.Errorf("restore: %w", err)
+	}
+	if info, err := os.Stat(dst); err == nil {
+		res.Bytes = info.Size()
+	}
+	return res, nil
+}
+
+// checkSnapshot validates the snapshot at path and returns its number of
+// memory rows.
+func checkSnapshot(ctx context.Context, path string) (int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("open snapshot: %w", err)
+	}
+	if info.IsDir() {
+		return 0, fmt.Errorf("snapshot %s is a directory", path)
+	}
+	if wal, err := os.Stat(path + "-wal"); err == nil && wal.Size() > 0 {
+		return 0, fmt.Errorf("snapshot %s has a write-ahead log; create snapshots with agent-memory backup", path)
+	}
+
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return 0, fmt.Errorf("open snapshot: %w", err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRowContext(ctx, `PRAGMA integrity_check`).Scan(&result); err != nil {
+		return 0, fmt.Errorf("snapshot %s is not a readable database: %w", path, err)
+	}
+	if result != "ok" {
+		return 0, fmt.Errorf("snapshot %s failed integrity check: %s", path, result)
+	}
+	for _, table := range snapshotTables {
+		var n int
+		if err := db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&n); err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			return 0, fmt.Errorf("snapshot %s is not an agent-memory database (no %s table)", path, table)
+		}
+	}
+	version, err := schemaVersion(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	if version > LatestSchemaVersion() {
+		return 0, fmt.Errorf("snapshot %s has schema version %d, newer than this build supports (%d)",
+			path, version, LatestSchemaVersion())
+	}
+	var memories int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM memories`).Scan(&memories); err != nil {
+		return 0, err
+	}
+	return memories, nil
+}
+
+// takeExclusive switches the database at path out of WAL mode, which SQLite
+// only allows with no other connection open. This both proves nothing else
+// is using the file and checkpoints the WAL into it, leaving a single file
+// to move aside.
+func takeExclusive(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite", fmt.Sprintf("%s?_pragma=busy_timeout(0)", path))
+	if err != nil {
+		return fmt.Errorf("open db: %w", err)
+	}
+	defer db.Close()
+
+	var mode string
+	err = db.QueryRowContext(ctx, `PRAGMA journal_mode=DELETE`).Scan(&mode)
+	if err != nil && !isBusy(err) {
+		return fmt.Errorf("open db: %w", err)
+	}
+	if err != nil || mode != "delete" {
+		return fmt.Errorf("database %s is in use by another process; close it before restoring", path)
+	}
+	return nil
+}
+
+// copyInto copies the file at src into f and syncs it to disk.
+func copyInto(f *os.File, src string) error {
+	defer f.Close()
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if _, err := io.Copy(f, in); err != nil {
+		return err
+	}
+	return f.Sync()
+}
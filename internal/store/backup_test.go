@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackup(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "first"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "second"})
+
+	out := filepath.Join(t.TempDir(), "snapshot.db")
+	res, err := s.Backup(ctx, out, false)
+	if err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	if res.Bytes == 0 {
+		t.Error("expected a non-empty snapshot")
+	}
+	if _, err := os.Stat(out + "-wal"); err == nil {
+		t.Error("expected a single-file snapshot without a WAL")
+	}
+
+	// Writes after the snapshot are not in it.
+	s.Put(ctx, PutParams{NS: "ns", Key: "b", Content: "later"})
+
+	snap, err := NewReadOnlyStore(out)
+	if err != nil {
+		t.Fatalf("open snapshot: %v", err)
+	}
+	defer snap.Close()
+	got, err := snap.Get(ctx, GetParams{NS: "ns", Key: "a"})
+	if err != nil || got[0].Content != "second" || got[0].Version != 2 {
+		t.Errorf("expected the latest version in the snapshot, got %+v (%v)", got, err)
+	}
+	if _, err := snap.Get(ctx, GetParams{NS: "ns", Key: "b"}); err == nil {
+		t.Error("expected ns/b to be missing from the snapshot")
+	}
+
+	if _, err := s.Backup(ctx, out, false); err == nil {
+		t.Error("expected an error backing up over an existing file")
+	}
+	if _, err := s.Backup(ctx, out, true); err != nil {
+		t.Errorf("backup with overwrite: %v", err)
+	}
+	if _, err := s.Backup(ctx, res.DBPath, true); err == nil {
+		t.Error("expected an error backing up onto the database itself")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "memory.db")
+	snapshot := filepath.Join(dir, "snapshot.db")
+
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "kept"})
+	if _, err := s.Backup(ctx, snapshot, false); err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+	s.Put(ctx, PutParams{NS: "ns", Key: "b", Content: "after the snapshot"})
+
+	if _, err := Restore(ctx, dbPath, snapshot); err == nil {
+		t.Error("expected restore to refuse while the store is open")
+	}
+	s.Close()
+
+	res, err := Restore(ctx, dbPath, snapshot)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if res.Memories != 1 || res.SafetyCopy == "" {
+		t.Errorf("unexpected restore result: %+v", res)
+	}
+
+	s, err = NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s.Close()
+	if _, err := s.Get(ctx, GetParams{NS: "ns", Key: "a"}); err != nil {
+		t.Errorf("expected ns/a after restore: %v", err)
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "ns", Key: "b"}); err == nil {
+		t.Error("expected ns/b to be gone after restore")
+	}
+
+	old, err := NewReadOnlyStore(res.SafetyCopy)
+	if err != nil {
+		t.Fatalf("open safety copy: %v", err)
+	}
+	defer old.Close()
+	if _, err := old.Get(ctx, GetParams{NS: "ns", Key: "b"}); err != nil {
+		t.Errorf("expected the safety copy to keep ns/b: %v", err)
+	}
+}
+
+func TestRestoreRejectsBadSnapshot(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	junk := filepath.Join(dir, "junk.db")
+	os.WriteFile(junk, []byte("not a database"), 0o644)
+	if _, err := Restore(ctx, filepath.Join(dir, "memory.db"), junk); err == nil {
+		t.Error("expected an error restoring a file that is not a database")
+	}
+
+	other := filepath.Join(dir, "other.db")
+	db, err := sql.Open("sqlite", other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Exec(`CREATE TABLE unrelated (x INTEGER)`)
+	db.Close()
+	if _, err := Restore(ctx, filepath.Join(dir, "memory.db"), other); err == nil {
+		t.Error("expected an error restoring a database without the store's tables")
+	}
+}
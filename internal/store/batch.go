@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errBatchItemIncomplete reports a PutBatch entry missing a required field.
+var errBatchItemIncomplete = errors.New("ns, key, and content are required")
+
+// PutBatchItem reports the outcome of one entry of a PutBatch.
+type PutBatchItem struct {
+	Index   int    `json:"index"`
+	ID      string `json:"id,omitempty"`
+	NS      string `json:"ns"`
+	Key     string `json:"key"`
+	Version int    `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PutBatchResult summarizes a PutBatch call.
+type PutBatchResult struct {
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Items     []PutBatchItem `json:"items"`
+}
+
+// PutBatch stores many memories in one transaction. Each entry is applied
+// under its own savepoint, so a failed entry leaves no partial rows and is
+// reported in the result while the rest are kept. With atomic set, the first
+// failure aborts the batch and nothing is stored.
+func (s *SQLiteStore) PutBatch(ctx context.Context, items []PutParams, atomic bool) (*PutBatchResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	// Validate every entry first so rejected ones cost no embedding calls,
+	// then chunk and embed the rest before taking the write lock.
+	prepared := make([][]embeddedChunk, len(items))
+	prepErrs := make([]error, len(items))
+	for i, p := range items {
+		if strings.TrimSpace(p.NS) == "" || p.Key == "" || strings.TrimSpace(p.Content) == "" {
+			prepErrs[i] = errBatchItemIncomplete
+		} else {
+			prepErrs[i] = s.checkPut(p)
+		}
+		if prepErrs[i] != nil && atomic {
+			return nil, fmt.Errorf("item %d (%s/%s): %w", i, p.NS, p.Key, prepErrs[i])
+		}
+	}
+	for i, p := range items {
+		if prepErrs[i] != nil {
+			continue
+		}
+		prepared[i], prepErrs[i] = s.prepareChunks(ctx, p)
+		if prepErrs[i] != nil && atomic {
+			return nil, fmt.Errorf("item %d (%s/%s): %w", i, p.NS, p.Key, prepErrs[i])
+		}
+	}
+
+	index, err := s.EmbedIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &PutBatchResult{Items: make([]PutBatchItem, 0, len(items))}
+	for i, p := range items {
+		item := PutBatchItem{Index: i, NS: p.NS, Key: p.Key}
+		if prepErrs[i] != nil {
+			item.Error = prepErrs[i].Error()
+			result.Failed++
+			result.Items = append(result.Items, item)
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT put_item`); err != nil {
+			return nil, err
+		}
+		saved := index
+		mem, err := s.putTx(ctx, tx, p, prepared[i], &index)
+		if err != nil {
+			if atomic {
+				return nil, fmt.Errorf("item %d (%s/%s): %w", i, p.NS, p.Key, err)
+			}
+			if _, rerr := tx.ExecContext(ctx, `ROLLBACK TO put_item`); rerr != nil {
+				return nil, rerr
+			}
+			index = saved
+			item.Error = err.Error()
+			result.Failed++
+		} else {
+			item.ID, item.Key, item.Version = mem.ID, mem.Key, mem.Version
+			result.Succeeded++
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE put_item`); err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPutBatch(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "a1"})
+
+	result, err := s.PutBatch(ctx, []PutParams{
+		{NS: "test", Key: "a", Content: "a2"},
+		{NS: "test", Key: "bad", Content: "x", TTL: "soon"},
+		{NS: "test", Key: "a", Content: "a3"},
+		{NS: "test", Key: "b", Content: "b1"},
+	}, false)
+	if err != nil {
+		t.Fatalf("PutBatch: %v", err)
+	}
+	if result.Succeeded != 3 || result.Failed != 1 {
+		t.Fatalf("succeeded=%d failed=%d, want 3 and 1", result.Succeeded, result.Failed)
+	}
+	if result.Items[1].Error == "" || result.Items[1].ID != "" {
+		t.Errorf("expected item 1 to fail, got %+v", result.Items[1])
+	}
+	if v := result.Items[2].Version; v != 3 {
+		t.Errorf("expected versions to increment within a batch, got v%d", v)
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "test", Key: "bad"}); err == nil {
+		t.Error("failed item should not be stored")
+	}
+
+	_, err = s.PutBatch(ctx, []PutParams{
+		{NS: "test", Key: "c", Content: "c1"},
+		{NS: "test", Key: "bad", Content: "x", TTL: "soon"},
+	}, true)
+	if err == nil {
+		t.Fatal("expected atomic batch to fail")
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "test", Key: "c"}); err == nil {
+		t.Error("atomic batch should store nothing on failure")
+	}
+
+	events, _ := s.Events(ctx, EventsParams{NS: "test", Key: "c"})
+	if len(events) != 0 {
+		t.Errorf("rolled-back puts should not be logged, got %d events", len(events))
+	}
+}
+
+func TestPutBatchIncompleteEntry(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	items := []PutParams{
+		{NS: "test", Key: "a", Content: "a1"},
+		{NS: "test", Key: "", Content: "no key"},
+		{NS: "", Key: "c", Content: "no ns"},
+		{NS: "test", Key: "d", Content: "  "},
+		{NS: "test", Key: "e", Content: "e1"},
+	}
+	result, err := s.PutBatch(ctx, items, false)
+	if err != nil {
+		t.Fatalf("PutBatch: %v", err)
+	}
+	if result.Succeeded != 2 || result.Failed != 3 {
+		t.Fatalf("succeeded=%d failed=%d, want 2 and 3", result.Succeeded, result.Failed)
+	}
+	for _, i := range []int{1, 2, 3} {
+		if result.Items[i].Error == "" {
+			t.Errorf("expected item %d to fail, got %+v", i, result.Items[i])
+		}
+	}
+
+	if _, err := s.PutBatch(ctx, items, true); err == nil {
+		t.Error("expected atomic batch with an incomplete entry to fail")
+	}
+}
+
+func TestPutBatchSkipsEmbeddingRejectedEntries(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	e := &modeEmbedder{fakeEmbedder: fakeEmbedder{model: "m", dims: 3}}
+	s.SetEmbedder(e)
+	if err := s.SetAllowedNamespaces([]string{"ok"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.PutBatch(ctx, []PutParams{
+		{NS: "ok", Key: "a", Content: "allowed"},
+		{NS: "blocked", Key: "b", Content: "disallowed"},
+	}, false)
+	if err != nil {
+		t.Fatalf("PutBatch: %v", err)
+	}
+	if result.Succeeded != 1 || result.Failed != 1 {
+		t.Fatalf("succeeded=%d failed=%d, want 1 and 1", result.Succeeded, result.Failed)
+	}
+	if e.docs != 1 {
+		t.Errorf("expected only the allowed entry embedded, got %d calls", e.docs)
+	}
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+// GetChunks returns the search chunks stored for one memory version, in
+// order. Encrypted chunk text is decrypted.
+func (s *SQLiteStore) GetChunks(ctx context.Context, memoryID string) ([]model.Chunk, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, seq, text, start_line, end_line, embedding IS NOT NULL
+		 FROM chunks WHERE memory_id = ? ORDER BY seq`, memoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chunks := []model.Chunk{}
+	for rows.Next() {
+		c := model.Chunk{MemoryID: memoryID}
+		var start, end sql.NullInt64
+		if err := rows.Scan(&c.ID, &c.Seq, &c.Text, &start, &end, &c.HasEmbedding); err != nil {
+			return nil, err
+		}
+		if c.Text, err = s.decrypt(c.Text); err != nil {
+			return nil, err
+		}
+		c.StartLine, c.EndLine = int(start.Int64), int(end.Int64)
+		chunks = append(chunks, c)
+	}
+	return chunks, rows.Err()
+}
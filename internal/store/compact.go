@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CompactResult reports the effect of a Compact call.
+type CompactResult struct {
+	DBPath         string `json:"db_path"`
+	BeforeBytes    int64  `json:"before_bytes"`
+	AfterBytes     int64  `json:"after_bytes"`
+	ReclaimedBytes int64  `json:"reclaimed_bytes"`
+	Checkpointed   bool   `json:"checkpointed"`
+	Warning        string `json:"warning,omitempty"`
+}
+
+// Compact runs VACUUM to rebuild the database file and, when checkpoint is
+// set, truncates the write-ahead log. Sizes include the WAL file.
+func (s *SQLiteStore) Compact(ctx context.Context, checkpoint bool) (*CompactResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	path, err := s.filePath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &CompactResult{DBPath: path, BeforeBytes: fileSizeWithWAL(path)}
+
+	if _, err := s.db.ExecContext(ctx, `VACUUM`); err != nil {
+		if isBusy(err) {
+			return nil, fmt.Errorf("database is busy (open by another process?): %w", err)
+		}
+		return nil, fmt.Errorf("vacuum: %w", err)
+	}
+
+	if checkpoint {
+		var busy, logFrames, checkpointed int
+		err := s.db.QueryRowContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`).Scan(&busy, &logFrames, &checkpointed)
+		switch {
+		case err != nil && isBusy(err), err == nil && busy != 0:
+			res.Warning = "wal checkpoint incomplete: database is in use by another process"
+		case err != nil:
+			return nil, fmt.Errorf("wal checkpoint: %w", err)
+		default:
+			res.Checkpointed = true
+		}
+	}
+
+	res.AfterBytes = fileSizeWithWAL(path)
+	res.ReclaimedBytes = res.BeforeBytes - res.AfterBytes
+	return res, nil
+}
+
+// filePath returns the path of the main database file.
+func (s *SQLiteStore) filePath(ctx context.Context) (string, error) {
+	var path string
+	err := s.db.QueryRowContext(ctx, `SELECT file FROM pragma_database_list WHERE name = 'main'`).Scan(&path)
+	if err != nil {
+		return "", fmt.Errorf("resolve db path: %w", err)
+	}
+	return path, nil
+}
+
+// fileSizeWithWAL returns the combined size of the database and its WAL file.
+func fileSizeWithWAL(path string) int64 {
+	var total int64
+	for _, p := range []string{path, path + "-wal"} {
+		if info, err := os.Stat(p); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// isBusy reports whether err is an SQLITE_BUSY/SQLITE_LOCKED error.
+func isBusy(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "SQLITE_BUSY") || strings.Contains(msg, "SQLITE_LOCKED") ||
+		strings.Contains(msg, "database is locked")
+}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	big := strings.Repeat("filler text for compaction. ", 500)
+	for i := 0; i < 20; i++ {
+		s.Put(ctx, PutParams{NS: "ns", Key: fmt.Sprintf("k%d", i), Content: big})
+	}
+	for i := 0; i < 20; i++ {
+		s.Rm(ctx, RmParams{NS: "ns", Key: fmt.Sprintf("k%d", i), Hard: true})
+	}
+
+	res, err := s.Compact(ctx, true)
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if !res.Checkpointed {
+		t.Errorf("expected checkpoint to complete, warning: %q", res.Warning)
+	}
+	if res.AfterBytes >= res.BeforeBytes {
+		t.Errorf("expected size to shrink: before=%d after=%d", res.BeforeBytes, res.AfterBytes)
+	}
+	if res.ReclaimedBytes != res.BeforeBytes-res.AfterBytes {
+		t.Errorf("reclaimed %d does not match before-after", res.ReclaimedBytes)
+	}
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// DefaultCompressThreshold is the content size in bytes above which memory
+// content is gzip-compressed at rest. Override with
+// $AGENT_MEMORY_COMPRESS_THRESHOLD; 0 disables compression.
+const DefaultCompressThreshold = 16 << 10
+
+// encodingGzip marks gzip-compressed content in memories.content_encoding.
+const encodingGzip = "gzip"
+
+// compressThreshold returns the configured compression threshold in bytes.
+func compressThreshold() (int, error) {
+	env := os.Getenv("AGENT_MEMORY_COMPRESS_THRESHOLD")
+	if env == "" {
+		return DefaultCompressThreshold, nil
+	}
+	n, err := strconv.Atoi(env)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid AGENT_MEMORY_COMPRESS_THRESHOLD %q (bytes)", env)
+	}
+	return n, nil
+}
+
+// encodeContent prepares content for the memories table: compressed when it
+// exceeds the threshold (and compression helps), then encrypted if a content
+// key is set. It returns the column value and the content_encoding marker.
+// Chunks are stored uncompressed so FTS keeps working.
+func (s *SQLiteStore) encodeContent(content string) (interface{}, *string, error) {
+	var encoding *string
+	if s.compressAt > 0 && len(content) > s.compressAt {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write([]byte(content))
+		if err := zw.Close(); err != nil {
+			return nil, nil, fmt.Errorf("compress content: %w", err)
+		}
+		if buf.Len() < len(content) {
+			content = buf.String()
+			enc := encodingGzip
+			encoding = &enc
+		}
+	}
+
+	if s.aead == nil {
+		if encoding != nil {
+			return []byte(content), encoding, nil
+		}
+		return content, nil, nil
+	}
+	sealed, err := s.encrypt(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encrypt content: %w", err)
+	}
+	return sealed, encoding, nil
+}
+
+// decodeContent reverses encodeContent.
+func (s *SQLiteStore) decodeContent(raw string, encoding sql.NullString) (string, error) {
+	content, err := s.decrypt(raw)
+	if err != nil {
+		return "", err
+	}
+	switch encoding.String {
+	case "":
+		return content, nil
+	case encodingGzip:
+		zr, err := gzip.NewReader(bytes.NewReader([]byte(content)))
+		if err != nil {
+			return "", fmt.Errorf("decompress content: %w", err)
+		}
+		b, err := io.ReadAll(zr)
+		if err != nil {
+			return "", fmt.Errorf("decompress content: %w", err)
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("unknown content encoding %q", encoding.String)
+	}
+}
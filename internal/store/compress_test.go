@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompressLargeContent(t *testing.T) {
+	ctx := context.Background()
+	content := strings.Repeat("Restart the worker, then check the queue depth.\n", 2000)
+
+	sizes := map[string]int{}
+	for _, threshold := range []string{"0", "1024"} {
+		t.Setenv("AGENT_MEMORY_COMPRESS_THRESHOLD", threshold)
+		s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		if _, err := s.Put(ctx, PutParams{NS: "ops", Key: "runbook", Content: content}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.Get(ctx, GetParams{NS: "ops", Key: "runbook"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[0].Content != content {
+			t.Errorf("threshold %s: content did not round-trip", threshold)
+		}
+
+		var stored int
+		var encoding *string
+		s.db.QueryRow(`SELECT length(content), content_encoding FROM memories`).Scan(&stored, &encoding)
+		sizes[threshold] = stored
+		if threshold == "1024" && (encoding == nil || *encoding != encodingGzip) {
+			t.Errorf("expected gzip encoding, got %v", encoding)
+		}
+
+		results, _ := s.Search(ctx, SearchParams{Query: "queue depth", Mode: "keyword"})
+		if len(results) != 1 {
+			t.Errorf("threshold %s: expected chunks to stay searchable, got %d results", threshold, len(results))
+		}
+	}
+
+	if sizes["1024"]*10 > sizes["0"] {
+		t.Errorf("expected compressed content to be much smaller: %d vs %d bytes", sizes["1024"], sizes["0"])
+	}
+}
+
+func TestCompressSmallContentUntouched(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "short"})
+
+	var encoding *string
+	s.db.QueryRow(`SELECT content_encoding FROM memories`).Scan(&encoding)
+	if encoding != nil {
+		t.Errorf("expected no encoding for small content, got %q", *encoding)
+	}
+}
+
+func TestCompressWithEncryption(t *testing.T) {
+	t.Setenv("AGENT_MEMORY_KEY", "correct horse")
+	t.Setenv("AGENT_MEMORY_COMPRESS_THRESHOLD", "1024")
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	content := strings.Repeat("secret runbook line\n", 500)
+	s.Put(ctx, PutParams{NS: "ops", Key: "runbook", Content: content})
+
+	got, err := s.Get(ctx, GetParams{NS: "ops", Key: "runbook"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Content != content {
+		t.Error("content did not round-trip through compression and encryption")
+	}
+}
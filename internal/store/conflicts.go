@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoEmbedder is returned by operations that need an embedding provider
+// when none is configured.
+var ErrNoEmbedder = errors.New("no embedding provider configured (set AGENT_MEMORY_EMBED_PROVIDER)")
+
+// DefaultConflictThreshold is the similarity above which a memory with
+// different content is reported as a possible contradiction.
+const DefaultConflictThreshold = 0.85
+
+// ConflictParams holds parameters for conflict detection.
+type ConflictParams struct {
+	NS        string
+	Key       string
+	Threshold float64 // 0 means DefaultConflictThreshold
+	Limit     int
+}
+
+// Conflict is a memory that is highly similar to the checked one but has
+// different content. Suggest is the link relation to consider creating.
+type Conflict struct {
+	NS         string  `json:"ns"`
+	Key        string  `json:"key"`
+	Similarity float64 `json:"similarity"`
+	Snippet    string  `json:"snippet"`
+	Suggest    string  `json:"suggest"`
+}
+
+// DetectConflicts finds other memories whose embeddings are close to the
+// latest version of ns/key but whose content differs. It never creates
+// links. Returns ErrNoEmbedder when vector search is unavailable.
+func (s *SQLiteStore) DetectConflicts(ctx context.Context, p ConflictParams) ([]Conflict, error) {
+	if s.embedder == nil {
+		return nil, ErrNoEmbedder
+	}
+	threshold := p.Threshold
+	if threshold <= 0 {
+		threshold = DefaultConflictThreshold
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+
+	target, err := s.latestMemory(ctx, s.db, p.NS, p.Key)
+	if err != nil {
+		return nil, err
+	}
+	vec, err := s.memoryVector(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if vec == nil {
+		return []Conflict{}, nil
+	}
+
+	results, err := s.searchByVector(ctx, SearchParams{}, vec, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []Conflict{}
+	for _, r := range results {
+		if r.NS == target.NS && r.Key == target.Key {
+			continue
+		}
+		if r.Similarity < threshold || r.Content == target.Content {
+			continue
+		}
+		conflicts = append(conflicts, Conflict{
+			NS:         r.NS,
+			Key:        r.Key,
+			Similarity: r.Similarity,
+			Snippet:    r.Snippet,
+			Suggest:    "contradicts",
+		})
+		if len(conflicts) == limit {
+			break
+		}
+	}
+	return conflicts, nil
+}
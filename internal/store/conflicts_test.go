@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDetectConflicts(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	s.embedder = &fakeEmbedder{model: "small", dims: 3}
+
+	s.Put(ctx, PutParams{NS: "prefs", Key: "editor", Content: "prefers vim"})
+	s.Put(ctx, PutParams{NS: "prefs", Key: "dup", Content: "prefers emacs"})
+	s.Put(ctx, PutParams{NS: "prefs", Key: "editor-old", Content: "prefers emacs"})
+	s.Put(ctx, PutParams{NS: "prefs", Key: "new", Content: "prefers emacs"})
+
+	got, err := s.DetectConflicts(ctx, ConflictParams{NS: "prefs", Key: "new"})
+	if err != nil {
+		t.Fatalf("detect: %v", err)
+	}
+	// The fake embedder makes everything identical; only differing content counts
+	if len(got) != 1 || got[0].Key != "editor" || got[0].Suggest != "contradicts" {
+		t.Errorf("expected prefs/editor as the only conflict, got %+v", got)
+	}
+
+	var links int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links`).Scan(&links)
+	if links != 0 {
+		t.Errorf("conflict detection must not create links, found %d", links)
+	}
+
+	if got, _ := s.DetectConflicts(ctx, ConflictParams{NS: "prefs", Key: "new", Threshold: 1.01}); len(got) != 0 {
+		t.Errorf("expected no conflicts above threshold, got %+v", got)
+	}
+}
+
+func TestDetectConflictsWithoutEmbedder(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	s.embedder = nil
+
+	s.Put(ctx, PutParams{NS: "prefs", Key: "editor", Content: "prefers vim"})
+	if _, err := s.DetectConflicts(ctx, ConflictParams{NS: "prefs", Key: "editor"}); !errors.Is(err, ErrNoEmbedder) {
+		t.Errorf("expected ErrNoEmbedder, got %v", err)
+	}
+}
@@ -4,18 +4,27 @@ import (
 	"context"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/rcliao/agent-memory/internal/model"
+	"github.com/rcliao/agent-memory/internal/summarize"
 )
 
 // ContextParams holds parameters for context assembly.
 type ContextParams struct {
-	NS     string
-	Query  string
-	Kind   string
-	Tags   []string
-	Budget int // max chars in output (rough token proxy: 1 token ≈ 4 chars)
+	NS       string
+	Query    string
+	Kind     string
+	Tags     []string
+	Budget   int          // max tokens in output
+	Counter  TokenCounter // token estimator; defaults to CharCounter
+	MinScore float64      // skip candidates scoring below this, even if budget remains
+
+	// Summarizer, if set, compresses memories that no longer fit into briefs
+	// sharing the remaining budget, instead of excerpting one and dropping
+	// the rest.
+	Summarizer summarize.Summarizer
 }
 
 // ContextMemory is a scored memory for context output.
@@ -26,6 +35,16 @@ type ContextMemory struct {
 	Content string  `json:"content"`
 	Score   float64 `json:"score"`
 	Excerpt bool    `json:"excerpt,omitempty"`
+	Summary bool    `json:"summary,omitempty"` // Content is a brief from the summarizer
+}
+
+// ContextLink is a relation between two memories that are both in the output.
+type ContextLink struct {
+	FromNS  string `json:"from_ns"`
+	FromKey string `json:"from_key"`
+	ToNS    string `json:"to_ns"`
+	ToKey   string `json:"to_key"`
+	Rel     string `json:"rel"`
 }
 
 // ContextResult is the assembled context response.
@@ -33,6 +52,7 @@ type ContextResult struct {
 	Budget   int             `json:"budget"`
 	Used     int             `json:"used"`
 	Memories []ContextMemory `json:"memories"`
+	Links    []ContextLink   `json:"links,omitempty"`
 }
 
 // Context assembles relevant memories within a token budget.
@@ -41,8 +61,10 @@ func (s *SQLiteStore) Context(ctx context.Context, p ContextParams) (*ContextRes
 	if budget <= 0 {
 		budget = 4000
 	}
-	// Convert token budget to char budget (rough: 4 chars/token)
-	charBudget := budget * 4
+	counter := p.Counter
+	if counter == nil {
+		counter = CharCounter{}
+	}
 
 	// Search for candidates (get more than we need for scoring)
 	results, err := s.Search(ctx, SearchParams{
@@ -67,11 +89,14 @@ func (s *SQLiteStore) Context(ctx context.Context, p ContextParams) (*ContextRes
 	}
 	var candidates []scored
 
-	for _, r := range results {
+	for i, r := range results {
 		m := r.Memory
-		// Relevance: position-based (earlier = more relevant from search)
-		// Since search already orders by relevance, use inverse position
-		relevance := 1.0 // base relevance from search match
+		// Relevance: normalized match score from search, falling back to
+		// inverse position since search already orders by relevance
+		relevance := r.Score
+		if relevance <= 0 {
+			relevance = 1 / float64(i+1)
+		}
 
 		// Recency: exponential decay, half-life of 7 days
 		age := now.Sub(m.CreatedAt).Hours() / 24.0 // days
@@ -104,9 +129,26 @@ func (s *SQLiteStore) Context(ctx context.Context, p ContextParams) (*ContextRes
 	result := &ContextResult{Budget: budget, Memories: []ContextMemory{}}
 	used := 0
 
-	for _, c := range candidates {
-		contentLen := len(c.memory.Content)
-		if used+contentLen <= charBudget {
+	for i, c := range candidates {
+		if c.score < p.MinScore {
+			break // sorted descending, so the rest score lower
+		}
+		tokens := counter.Count(c.memory.Content)
+		if remaining := budget - used; tokens > remaining && remaining >= 25 && p.Summarizer != nil {
+			// Share what is left among the remaining relevant candidates
+			left := 0
+			for _, rest := range candidates[i:] {
+				if rest.score >= p.MinScore {
+					left++
+				}
+			}
+			cm := s.summarizeForContext(ctx, p.Summarizer, counter, c.memory, remaining, max(remaining/left, 25))
+			cm.Score = math.Round(c.score*100) / 100
+			result.Memories = append(result.Memories, cm)
+			used += counter.Count(cm.Content)
+			continue
+		}
+		if used+tokens <= budget {
 			// Fits entirely
 			result.Memories = append(result.Memories, ContextMemory{
 				NS:      c.memory.NS,
@@ -115,13 +157,10 @@ func (s *SQLiteStore) Context(ctx context.Context, p ContextParams) (*ContextRes
 				Content: c.memory.Content,
 				Score:   math.Round(c.score*100) / 100,
 			})
-			used += contentLen
-		} else if remaining := charBudget - used; remaining >= 100 {
+			used += tokens
+		} else if remaining := budget - used; remaining >= 25 {
 			// Partial fit — excerpt
-			excerpt := c.memory.Content
-			if len(excerpt) > remaining {
-				excerpt = excerpt[:remaining] + "..."
-			}
+			excerpt := truncateToTokens(counter, c.memory.Content, "...", remaining)
 			result.Memories = append(result.Memories, ContextMemory{
 				NS:      c.memory.NS,
 				Key:     c.memory.Key,
@@ -130,19 +169,83 @@ func (s *SQLiteStore) Context(ctx context.Context, p ContextParams) (*ContextRes
 				Score:   math.Round(c.score*100) / 100,
 				Excerpt: true,
 			})
-			used += len(excerpt)
+			used += counter.Count(excerpt)
 			break // budget full
 		} else {
 			break
 		}
 	}
 
-	// Convert used chars back to approximate tokens
-	result.Used = used / 4
+	result.Used = used
+
+	links, err := s.contextLinks(ctx, result.Memories)
+	if err != nil {
+		return nil, err
+	}
+	result.Links = links
 
 	return result, nil
 }
 
+// summarizeForContext asks the summarizer for a brief of about target tokens
+// and cuts it to fit remaining. If summarizing fails, the memory is excerpted.
+func (s *SQLiteStore) summarizeForContext(ctx context.Context, sum summarize.Summarizer, counter TokenCounter, m model.Memory, remaining, target int) ContextMemory {
+	cm := ContextMemory{NS: m.NS, Key: m.Key, Kind: m.Kind, Content: m.Content}
+	if brief, err := sum.Summarize(ctx, m.Content, target); err == nil && brief != m.Content {
+		cm.Content, cm.Summary = brief, true
+	}
+	if counter.Count(cm.Content) > remaining {
+		cm.Content = truncateToTokens(counter, cm.Content, "...", remaining)
+		cm.Excerpt = true
+	}
+	return cm
+}
+
+// contextLinks returns links whose endpoints are both among the selected
+// memories. Links are matched by ns/key so they survive new versions.
+func (s *SQLiteStore) contextLinks(ctx context.Context, memories []ContextMemory) ([]ContextLink, error) {
+	if len(memories) < 2 {
+		return nil, nil
+	}
+
+	selected := map[[2]string]bool{}
+	var conds []string
+	var args []interface{}
+	for _, m := range memories {
+		selected[[2]string{m.NS, m.Key}] = true
+		conds = append(conds, "(f.ns = ? AND f.key = ?)")
+		args = append(args, m.NS, m.Key)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT f.ns, f.key, t.ns, t.key, l.rel
+		FROM memory_links l
+		INNER JOIN memories f ON f.id = l.from_id
+		INNER JOIN memories t ON t.id = l.to_id
+		WHERE f.deleted_at IS NULL AND t.deleted_at IS NULL
+		  AND (`+strings.Join(conds, " OR ")+`)
+		ORDER BY l.created_at`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []ContextLink
+	seen := map[ContextLink]bool{}
+	for rows.Next() {
+		var l ContextLink
+		if err := rows.Scan(&l.FromNS, &l.FromKey, &l.ToNS, &l.ToKey, &l.Rel); err != nil {
+			return nil, err
+		}
+		if !selected[[2]string{l.ToNS, l.ToKey}] || seen[l] {
+			continue
+		}
+		seen[l] = true
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
 func priorityScore(p string) float64 {
 	switch p {
 	case "critical":
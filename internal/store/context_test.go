@@ -2,7 +2,9 @@ package store
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestContextBasic(t *testing.T) {
@@ -106,3 +108,139 @@ func TestContextPriorityBoosting(t *testing.T) {
 		t.Errorf("expected critical-pri first, got %s", result.Memories[0].Key)
 	}
 }
+
+func TestContextRelevanceOutranksRecency(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "focused", Content: "kubernetes kubernetes cluster upgrades for kubernetes"})
+	s.Put(ctx, PutParams{NS: "test", Key: "passing", Content: "weekly notes: " + strings.Repeat("lunch plans and misc chatter ", 10) + "also kubernetes once"})
+	s.Put(ctx, PutParams{NS: "test", Key: "unrelated", Content: "nothing to see here"})
+	// Make the relevant memory a month old so recency favors the other
+	s.db.Exec(`UPDATE memories SET created_at = ? WHERE key = 'focused'`,
+		time.Now().Add(-30*24*time.Hour).UTC().Format(time.RFC3339))
+
+	result, err := s.Context(ctx, ContextParams{NS: "test", Query: "kubernetes", Budget: 4000})
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	if len(result.Memories) != 2 {
+		t.Fatalf("expected 2 memories, got %d", len(result.Memories))
+	}
+	if result.Memories[0].Key != "focused" {
+		t.Errorf("expected more relevant 'focused' first, got %s", result.Memories[0].Key)
+	}
+}
+
+func TestContextLinks(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "api", Content: "the api service talks to postgres"})
+	s.Put(ctx, PutParams{NS: "test", Key: "db", Content: "postgres runs on port 5432"})
+	s.Put(ctx, PutParams{NS: "test", Key: "ui", Content: "frontend only, unrelated"})
+	s.Link(ctx, LinkParams{FromNS: "test", FromKey: "api", ToNS: "test", ToKey: "db", Rel: "depends_on"})
+	s.Link(ctx, LinkParams{FromNS: "test", FromKey: "api", ToNS: "test", ToKey: "ui", Rel: "relates_to"})
+
+	// A new version of an endpoint keeps the link visible
+	s.Put(ctx, PutParams{NS: "test", Key: "db", Content: "postgres runs on port 6432 now"})
+
+	result, err := s.Context(ctx, ContextParams{NS: "test", Query: "postgres", Budget: 4000})
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	if len(result.Links) != 1 {
+		t.Fatalf("expected 1 link among selected memories, got %+v", result.Links)
+	}
+	l := result.Links[0]
+	if l.FromKey != "api" || l.ToKey != "db" || l.Rel != "depends_on" {
+		t.Errorf("unexpected link %+v", l)
+	}
+}
+
+func TestContextMinScore(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "strong", Content: "redis cache redis cluster redis", Priority: "critical"})
+	s.Put(ctx, PutParams{NS: "test", Key: "weak", Content: "a passing mention of redis among many other unrelated words here", Priority: "low"})
+
+	all, err := s.Context(ctx, ContextParams{NS: "test", Query: "redis", Budget: 4000})
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	if len(all.Memories) != 2 {
+		t.Fatalf("expected 2 memories without cutoff, got %d", len(all.Memories))
+	}
+
+	// Cut between the two scores
+	cutoff := (all.Memories[0].Score + all.Memories[1].Score) / 2
+	if all.Memories[0].Score == all.Memories[1].Score {
+		t.Fatalf("expected distinct scores, got %v", all.Memories[0].Score)
+	}
+	filtered, err := s.Context(ctx, ContextParams{NS: "test", Query: "redis", Budget: 4000, MinScore: cutoff})
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	if len(filtered.Memories) != 1 || filtered.Memories[0].Key != all.Memories[0].Key {
+		t.Errorf("expected only %s above %.2f, got %+v", all.Memories[0].Key, cutoff, filtered.Memories)
+	}
+}
+
+// briefSummarizer returns the first word of the text, or fails when err is set.
+type briefSummarizer struct {
+	calls int
+	err   error
+}
+
+func (b *briefSummarizer) Summarize(ctx context.Context, text string, maxTokens int) (string, error) {
+	b.calls++
+	if b.err != nil {
+		return "", b.err
+	}
+	return "brief: " + strings.Fields(text)[0], nil
+}
+
+func TestContextSummarize(t *testing.T) {
+	s := newTestStore(t)
+	defer s.Close()
+	ctx := context.Background()
+
+	long := strings.Repeat("deploy steps and rollback notes for the release. ", 20)
+	for _, key := range []string{"a", "b", "c"} {
+		s.Put(ctx, PutParams{NS: "test", Key: key, Content: key + " " + long})
+	}
+
+	// Without a summarizer one memory fits, one is excerpted, one is dropped
+	plain, err := s.Context(ctx, ContextParams{NS: "test", Query: "deploy", Budget: 300})
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	if len(plain.Memories) != 2 || !plain.Memories[1].Excerpt {
+		t.Fatalf("expected one full memory and one excerpt, got %+v", plain.Memories)
+	}
+
+	sum := &briefSummarizer{}
+	result, err := s.Context(ctx, ContextParams{NS: "test", Query: "deploy", Budget: 300, Summarizer: sum})
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	if len(result.Memories) != 3 || sum.calls != 2 {
+		t.Fatalf("expected 3 memories with 2 summarized, got %d (%d calls)", len(result.Memories), sum.calls)
+	}
+	for _, m := range result.Memories[1:] {
+		if !m.Summary || m.Excerpt || !strings.HasPrefix(m.Content, "brief: ") {
+			t.Errorf("expected a brief for %s, got %+v", m.Key, m)
+		}
+	}
+	if result.Used > result.Budget {
+		t.Errorf("used %d exceeds budget %d", result.Used, result.Budget)
+	}
+
+	// A failing summarizer falls back to excerpts
+	result, _ = s.Context(ctx, ContextParams{NS: "test", Query: "deploy", Budget: 300,
+		Summarizer: &briefSummarizer{err: context.DeadlineExceeded}})
+	if len(result.Memories) < 2 || result.Memories[1].Summary || !result.Memories[1].Excerpt {
+		t.Errorf("expected excerpts when summarizing fails, got %+v", result.Memories)
+	}
+}
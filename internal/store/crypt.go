@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Content encryption at rest: when $AGENT_MEMORY_KEY is set, memory content
+// and chunk text are sealed with AES-256-GCM under a key derived by PBKDF2
+// from the passphrase and a per-database salt. Everything else stays
+// plaintext for querying. FTS only sees ciphertext, so keyword search cannot
+// match encrypted memories; vector search still works, since embeddings are
+// computed from the plaintext.
+
+// encPrefix marks an encrypted value: encPrefix + base64(nonce || sealed).
+const encPrefix = "enc:v1:"
+
+// metaContentSalt is the store_meta key holding the PBKDF2 salt.
+const metaContentSalt = "content_salt"
+
+// keyIterations is the PBKDF2-SHA256 work factor for the passphrase.
+const keyIterations = 600_000
+
+// ErrContentEncrypted is returned when reading encrypted content without
+// $AGENT_MEMORY_KEY set.
+var ErrContentEncrypted = errors.New("content is encrypted; set AGENT_MEMORY_KEY to read it")
+
+// loadCipher derives the content key from $AGENT_MEMORY_KEY, creating the
+// database's salt on first use. It leaves s.aead nil when no key is set.
+func (s *SQLiteStore) loadCipher(ctx context.Context) error {
+	pass := os.Getenv("AGENT_MEMORY_KEY")
+	if pass == "" {
+		return nil
+	}
+
+	var encoded string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT value FROM store_meta WHERE key = ?`, metaContentSalt).Scan(&encoded)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if s.readOnly {
+			// Nothing has been encrypted yet, and nothing can be written.
+			return nil
+		}
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		encoded = base64.StdEncoding.EncodeToString(salt)
+		if _, err := s.db.ExecContext(ctx,
+			`INSERT INTO store_meta (key, value) VALUES (?, ?)`, metaContentSalt, encoded); err != nil {
+			return fmt.Errorf("record salt: %w", err)
+		}
+	case err != nil:
+		return err
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("invalid %s in store_meta: %w", metaContentSalt, err)
+	}
+	key, err := pbkdf2.Key(sha256.New, pass, salt, keyIterations, 32)
+	if err != nil {
+		return err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	s.aead, err = cipher.NewGCM(block)
+	return err
+}
+
+// encrypt seals text if a content key is configured, and returns it
+// unchanged otherwise.
+func (s *SQLiteStore) encrypt(text string) (string, error) {
+	if s.aead == nil {
+		return text, nil
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(text), nil)
+	return encPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt opens a value produced by encrypt. Plaintext values pass through.
+func (s *SQLiteStore) decrypt(text string) (string, error) {
+	if !strings.HasPrefix(text, encPrefix) {
+		return text, nil
+	}
+	if s.aead == nil {
+		return "", ErrContentEncrypted
+	}
+	raw, err := base64.StdEncoding.DecodeString(text[len(encPrefix):])
+	if err != nil || len(raw) < s.aead.NonceSize() {
+		return "", fmt.Errorf("decrypt content: malformed ciphertext")
+	}
+	n := s.aead.NonceSize()
+	plain, err := s.aead.Open(nil, raw[:n], raw[n:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt content: wrong AGENT_MEMORY_KEY or corrupted data")
+	}
+	return string(plain), nil
+}
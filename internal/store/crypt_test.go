@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptedContent(t *testing.T) {
+	t.Setenv("AGENT_MEMORY_KEY", "correct horse")
+	path := filepath.Join(t.TempDir(), "test.db")
+	ctx := context.Background()
+
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Put(ctx, PutParams{NS: "test", Key: "secret", Content: "the password is hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var raw, chunk string
+	s.db.QueryRow(`SELECT content FROM memories WHERE key = 'secret'`).Scan(&raw)
+	s.db.QueryRow(`SELECT text FROM chunks`).Scan(&chunk)
+	if strings.Contains(raw, "hunter2") || strings.Contains(chunk, "hunter2") {
+		t.Fatalf("expected ciphertext at rest, got content %q chunk %q", raw, chunk)
+	}
+
+	got, err := s.Get(ctx, GetParams{NS: "test", Key: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Content != "the password is hunter2" {
+		t.Errorf("expected decrypted content, got %q", got[0].Content)
+	}
+	s.Close()
+
+	t.Setenv("AGENT_MEMORY_KEY", "")
+	s, _ = NewSQLiteStore(path)
+	_, err = s.Get(ctx, GetParams{NS: "test", Key: "secret"})
+	if !errors.Is(err, ErrContentEncrypted) {
+		t.Errorf("expected ErrContentEncrypted without a key, got %v", err)
+	}
+	s.Close()
+
+	t.Setenv("AGENT_MEMORY_KEY", "wrong")
+	s, _ = NewSQLiteStore(path)
+	defer s.Close()
+	if _, err := s.Get(ctx, GetParams{NS: "test", Key: "secret"}); err == nil {
+		t.Error("expected error with the wrong key")
+	}
+}
+
+func TestEncryptedReadsPlaintextRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	ctx := context.Background()
+
+	s, _ := NewSQLiteStore(path)
+	s.Put(ctx, PutParams{NS: "test", Key: "old", Content: "written before the key"})
+	s.Close()
+
+	t.Setenv("AGENT_MEMORY_KEY", "correct horse")
+	s, _ = NewSQLiteStore(path)
+	defer s.Close()
+	got, err := s.Get(ctx, GetParams{NS: "test", Key: "old"})
+	if err != nil || got[0].Content != "written before the key" {
+		t.Errorf("expected plaintext row to stay readable, got %v %v", got, err)
+	}
+}
@@ -0,0 +1,260 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/embedding"
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+// DefaultDedupeThreshold is the similarity at or above which two memories
+// are treated as duplicates.
+const DefaultDedupeThreshold = 0.95
+
+// Dedupe actions.
+const (
+	DedupeReport = "report" // only report clusters
+	DedupeLink   = "link"   // link each duplicate to the keeper with relates_to
+	DedupeRm     = "rm"     // soft-delete every version of each duplicate
+)
+
+// Dedupe methods.
+const (
+	DedupeByEmbedding = "embedding"
+	DedupeByContent   = "exact" // no embedder: identical content only
+)
+
+// DedupeParams holds parameters for finding near-identical memories.
+type DedupeParams struct {
+	NS        string  // empty covers every namespace
+	Threshold float64 // 0 means DefaultDedupeThreshold
+	Action    string  // "" or DedupeReport, DedupeLink, DedupeRm
+	DryRun    bool    // report what Action would do without applying it
+}
+
+// DedupeMember is one memory of a duplicate cluster.
+type DedupeMember struct {
+	ID         string    `json:"id"`
+	NS         string    `json:"ns"`
+	Key        string    `json:"key"`
+	Version    int       `json:"version"`
+	Priority   string    `json:"priority"`
+	CreatedAt  time.Time `json:"created_at"`
+	Similarity float64   `json:"similarity,omitempty"` // to the kept memory
+}
+
+// DedupeCluster is a group of near-identical memories. Keep is the one with
+// the highest priority, newest first on ties.
+type DedupeCluster struct {
+	Keep       DedupeMember   `json:"keep"`
+	Duplicates []DedupeMember `json:"duplicates"`
+}
+
+// DedupeResult summarizes a Dedupe call.
+type DedupeResult struct {
+	Method    string          `json:"method"`
+	Threshold float64         `json:"threshold,omitempty"`
+	Action    string          `json:"action"`
+	DryRun    bool            `json:"dry_run,omitempty"`
+	Clusters  []DedupeCluster `json:"clusters"`
+	Linked    int             `json:"linked,omitempty"`
+	Removed   int             `json:"removed,omitempty"`
+}
+
+// Dedupe clusters the latest versions of memories whose mean chunk
+// embeddings are at least Threshold similar (or whose content is identical)
+// and optionally links or soft-deletes the duplicates. Clustering is
+// transitive and compares every pair, so it is meant for namespaces of up to
+// a few thousand memories. Without an embedder only identical content is
+// clustered.
+func (s *SQLiteStore) Dedupe(ctx context.Context, p DedupeParams) (*DedupeResult, error) {
+	action := p.Action
+	if action == "" {
+		action = DedupeReport
+	}
+	if action != DedupeReport && action != DedupeLink && action != DedupeRm {
+		return nil, fmt.Errorf("invalid dedupe action %q (valid: report, link, rm)", p.Action)
+	}
+	if action != DedupeReport && !p.DryRun && s.readOnly {
+		return nil, ErrReadOnly
+	}
+	threshold := p.Threshold
+	if threshold <= 0 {
+		threshold = DefaultDedupeThreshold
+	}
+
+	memories, err := s.dedupeCandidates(ctx, p.NS)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DedupeResult{Method: DedupeByContent, Action: action, DryRun: p.DryRun}
+	var vecs []embedding.Vector
+	if s.embedder != nil {
+		result.Method, result.Threshold = DedupeByEmbedding, threshold
+		vecs = make([]embedding.Vector, len(memories))
+		for i, m := range memories {
+			if vecs[i], err = s.memoryVector(ctx, m.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	similarity := func(i, j int) float64 {
+		if strings.TrimSpace(memories[i].Content) == strings.TrimSpace(memories[j].Content) {
+			return 1
+		}
+		if vecs == nil || vecs[i] == nil || vecs[j] == nil || len(vecs[i]) != len(vecs[j]) {
+			return 0
+		}
+		return embedding.CosineSimilarity(vecs[i], vecs[j])
+	}
+
+	// Union-find over every pair at or above the threshold
+	parent := make([]int, len(memories))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range memories {
+		for j := i + 1; j < len(memories); j++ {
+			if similarity(i, j) >= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := map[int][]int{}
+	for i := range memories {
+		r := find(i)
+		groups[r] = append(groups[r], i)
+	}
+	result.Clusters = []DedupeCluster{}
+	for _, g := range groups {
+		if len(g) < 2 {
+			continue
+		}
+		sort.Slice(g, func(a, b int) bool { return keepBefore(memories[g[a]], memories[g[b]]) })
+		c := DedupeCluster{Keep: dedupeMember(memories[g[0]], 0)}
+		for _, i := range g[1:] {
+			c.Duplicates = append(c.Duplicates, dedupeMember(memories[i], roundScore(similarity(g[0], i))))
+		}
+		result.Clusters = append(result.Clusters, c)
+	}
+	sort.Slice(result.Clusters, func(a, b int) bool {
+		ka, kb := result.Clusters[a].Keep, result.Clusters[b].Keep
+		if ka.NS != kb.NS {
+			return ka.NS < kb.NS
+		}
+		return ka.Key < kb.Key
+	})
+
+	if action == DedupeReport || p.DryRun || len(result.Clusters) == 0 {
+		return result, nil
+	}
+	if err := s.applyDedupe(ctx, action, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyDedupe links or removes every duplicate in one transaction.
+func (s *SQLiteStore) applyDedupe(ctx context.Context, action string, result *DedupeResult) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, c := range result.Clusters {
+		for _, d := range c.Duplicates {
+			if action == DedupeRm {
+				if err := s.rmTx(ctx, tx, RmParams{NS: d.NS, Key: d.Key, AllVersions: true}); err != nil {
+					return err
+				}
+				result.Removed++
+				continue
+			}
+			lp := LinkParams{FromNS: d.NS, FromKey: d.Key, ToNS: c.Keep.NS, ToKey: c.Keep.Key,
+				Rel: "relates_to", Bidirectional: true}
+			if _, err := linkTx(ctx, tx, lp); err != nil {
+				return err
+			}
+			if err := s.recordLinkEvent(ctx, tx, lp); err != nil {
+				return err
+			}
+			result.Linked++
+		}
+	}
+	return tx.Commit()
+}
+
+// dedupeCandidates returns the latest live version of every key in ns.
+func (s *SQLiteStore) dedupeCandidates(ctx context.Context, ns string) ([]model.Memory, error) {
+	query := `
+		SELECT m.id, m.ns, m.key, m.content, m.kind, m.tags, m.version, m.supersedes,
+		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at, m.content_encoding, m.actor
+		FROM memories m
+		INNER JOIN (
+			SELECT ns, key, MAX(version) AS max_ver
+			FROM memories WHERE deleted_at IS NULL
+			GROUP BY ns, key
+		) latest ON m.ns = latest.ns AND m.key = latest.key AND m.version = latest.max_ver
+		WHERE m.deleted_at IS NULL AND (m.expires_at IS NULL OR m.expires_at > ?)`
+	args := []interface{}{time.Now().UTC().Format(time.RFC3339)}
+	if ns != "" {
+		query += ` AND m.ns = ?`
+		args = append(args, ns)
+	}
+	query += ` ORDER BY m.ns, m.key`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []model.Memory
+	for rows.Next() {
+		m, err := s.scanMemory(rows)
+		if err != nil {
+			return nil, err
+		}
+		memories = append(memories, m)
+	}
+	return memories, rows.Err()
+}
+
+// keepBefore orders cluster members so the one to keep comes first:
+// highest priority, then newest, then key.
+func keepBefore(a, b model.Memory) bool {
+	if pa, pb := priorityScore(a.Priority), priorityScore(b.Priority); pa != pb {
+		return pa > pb
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.NS+"\x00"+a.Key < b.NS+"\x00"+b.Key
+}
+
+func dedupeMember(m model.Memory, similarity float64) DedupeMember {
+	return DedupeMember{
+		ID:         m.ID,
+		NS:         m.NS,
+		Key:        m.Key,
+		Version:    m.Version,
+		Priority:   m.Priority,
+		CreatedAt:  m.CreatedAt,
+		Similarity: similarity,
+	}
+}
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rcliao/agent-memory/internal/embedding"
+)
+
+// topicEmbedder embeds text by its first word, so rewordings that share a
+// first word are identical and everything else is orthogonal.
+type topicEmbedder struct{}
+
+func (topicEmbedder) Embed(ctx context.Context, text string) (embedding.Vector, error) {
+	v := make(embedding.Vector, 4)
+	switch strings.Fields(text)[0] {
+	case "editor:":
+		v[0] = 1
+	case "shell:":
+		v[1] = 1
+	default:
+		v[2] = 1
+	}
+	return v, nil
+}
+
+func (topicEmbedder) Dims() int     { return 4 }
+func (topicEmbedder) Model() string { return "topic" }
+
+func TestDedupe(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	s.embedder = topicEmbedder{}
+
+	s.Put(ctx, PutParams{NS: "prefs", Key: "editor", Content: "editor: uses neovim"})
+	s.Put(ctx, PutParams{NS: "prefs", Key: "editor-2", Content: "editor: prefers neovim", Priority: "high"})
+	s.Put(ctx, PutParams{NS: "prefs", Key: "editor-3", Content: "editor: neovim, always"})
+	s.Put(ctx, PutParams{NS: "prefs", Key: "shell", Content: "shell: zsh"})
+	s.Put(ctx, PutParams{NS: "other", Key: "editor", Content: "editor: emacs"})
+
+	result, err := s.Dedupe(ctx, DedupeParams{NS: "prefs"})
+	if err != nil {
+		t.Fatalf("Dedupe: %v", err)
+	}
+	if result.Method != DedupeByEmbedding || len(result.Clusters) != 1 {
+		t.Fatalf("expected one embedding cluster, got %+v", result)
+	}
+	c := result.Clusters[0]
+	if c.Keep.Key != "editor-2" || len(c.Duplicates) != 2 {
+		t.Fatalf("expected editor-2 kept with 2 duplicates, got %+v", c)
+	}
+	if c.Duplicates[0].Similarity != 1 {
+		t.Errorf("expected similarity 1, got %v", c.Duplicates[0].Similarity)
+	}
+
+	if _, err := s.Dedupe(ctx, DedupeParams{NS: "prefs", Action: DedupeRm, DryRun: true}); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "prefs", Key: "editor"}); err != nil {
+		t.Error("dry run should not delete anything")
+	}
+
+	result, err = s.Dedupe(ctx, DedupeParams{NS: "prefs", Action: DedupeLink})
+	if err != nil || result.Linked != 2 {
+		t.Fatalf("expected 2 links, got %+v, %v", result, err)
+	}
+	var links int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links WHERE rel = 'relates_to'`).Scan(&links)
+	if links != 4 {
+		t.Errorf("expected 4 bidirectional link rows, got %d", links)
+	}
+
+	result, err = s.Dedupe(ctx, DedupeParams{NS: "prefs", Action: DedupeRm})
+	if err != nil || result.Removed != 2 {
+		t.Fatalf("expected 2 removed, got %+v, %v", result, err)
+	}
+	for _, key := range []string{"editor", "editor-3"} {
+		if _, err := s.Get(ctx, GetParams{NS: "prefs", Key: key}); err == nil {
+			t.Errorf("expected prefs/%s to be soft-deleted", key)
+		}
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "other", Key: "editor"}); err != nil {
+		t.Error("other namespaces should be untouched")
+	}
+}
+
+func TestDedupeRmAllVersions(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	s.embedder = nil
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "same fact", Priority: "high"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "older wording"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "same fact"})
+
+	result, err := s.Dedupe(ctx, DedupeParams{NS: "test", Action: DedupeRm})
+	if err != nil || result.Removed != 1 {
+		t.Fatalf("expected 1 removed, got %+v, %v", result, err)
+	}
+	if m, err := s.Get(ctx, GetParams{NS: "test", Key: "b"}); err == nil {
+		t.Errorf("expected every version of test/b deleted, got v%d", m[0].Version)
+	}
+	results, _ := s.Search(ctx, SearchParams{NS: "test", Query: "older"})
+	if len(results) != 0 {
+		t.Errorf("expected no search hits for the removed duplicate, got %d", len(results))
+	}
+}
+
+func TestDedupeWithoutEmbedder(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	s.embedder = nil
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "same fact"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "same fact"})
+	s.Put(ctx, PutParams{NS: "test", Key: "c", Content: "same fact, reworded"})
+
+	result, err := s.Dedupe(ctx, DedupeParams{NS: "test"})
+	if err != nil {
+		t.Fatalf("Dedupe: %v", err)
+	}
+	if result.Method != DedupeByContent || len(result.Clusters) != 1 || len(result.Clusters[0].Duplicates) != 1 {
+		t.Fatalf("expected one exact-content pair, got %+v", result)
+	}
+
+	if _, err := s.Dedupe(ctx, DedupeParams{Action: "merge"}); err == nil {
+		t.Error("expected an invalid action error")
+	}
+}
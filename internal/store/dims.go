@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/rcliao/agent-memory/internal/embedding"
+)
+
+// Keys in the store_meta table describing the vector index.
+const (
+	metaEmbedModel = "embed_model"
+	metaEmbedDims  = "embed_dims"
+)
+
+// ErrDimsMismatch is returned when a vector's dimensions differ from the index.
+var ErrDimsMismatch = errors.New("embedding dims mismatch")
+
+// EmbedIndexInfo describes the embedder that produced the stored chunk vectors.
+type EmbedIndexInfo struct {
+	Model string `json:"model"`
+	Dims  int    `json:"dims"`
+}
+
+// SetForceDims allows embedding and vector search to proceed even when the
+// active embedder's dimensions differ from the indexed dimensions.
+func (s *SQLiteStore) SetForceDims(force bool) {
+	s.forceDims = force
+}
+
+// SetEmbedder replaces the embedder used for puts and vector search. nil
+// disables embedding: puts store no vectors and search uses keywords only.
+func (s *SQLiteStore) SetEmbedder(e embedding.Embedder) {
+	s.embedder = e
+}
+
+// Embedder returns the embedder used for puts and vector search, or nil.
+func (s *SQLiteStore) Embedder() embedding.Embedder {
+	return s.embedder
+}
+
+// EmbedIndex returns the recorded embedder model and dimensions, or nil if
+// no vectors have been stored yet.
+func (s *SQLiteStore) EmbedIndex(ctx context.Context) (*EmbedIndexInfo, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT key, value FROM store_meta WHERE key IN (?, ?)`, metaEmbedModel, metaEmbedDims)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var info EmbedIndexInfo
+	found := false
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return nil, err
+		}
+		switch k {
+		case metaEmbedModel:
+			info.Model = v
+		case metaEmbedDims:
+			info.Dims, _ = strconv.Atoi(v)
+			found = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &info, nil
+}
+
+// setEmbedIndex records the embedder model and dimensions for the vector index.
+func setEmbedIndex(ctx context.Context, tx *sql.Tx, info *EmbedIndexInfo) error {
+	for k, v := range map[string]string{
+		metaEmbedModel: info.Model,
+		metaEmbedDims:  strconv.Itoa(info.Dims),
+	} {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO store_meta (key, value) VALUES (?, ?)
+			 ON CONFLICT(key) DO UPDATE SET value = excluded.value`, k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDims rejects vectors whose length differs from the indexed dimensions.
+func (s *SQLiteStore) checkDims(index *EmbedIndexInfo, dims int) error {
+	if index == nil || index.Dims == dims || s.forceDims {
+		return nil
+	}
+	return fmt.Errorf("%w: embedder %q produces %d dims but index was built with %q (%d dims); reindex or use --force-dims",
+		ErrDimsMismatch, s.embedder.Model(), dims, index.Model, index.Dims)
+}
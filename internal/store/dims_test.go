@@ -0,0 +1,190 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/rcliao/agent-memory/internal/embedding"
+)
+
+// fakeEmbedder returns a constant vector of the configured size.
+type fakeEmbedder struct {
+	model string
+	dims  int
+}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, text string) (embedding.Vector, error) {
+	v := make(embedding.Vector, f.dims)
+	for i := range v {
+		v[i] = 1
+	}
+	return v, nil
+}
+
+func (f *fakeEmbedder) Dims() int     { return f.dims }
+func (f *fakeEmbedder) Model() string { return f.model }
+
+func TestEmbedIndexRecorded(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	info, err := s.EmbedIndex(ctx)
+	if err != nil {
+		t.Fatalf("embed index: %v", err)
+	}
+	if info != nil {
+		t.Fatalf("expected no index before any embeddings, got %+v", info)
+	}
+
+	s.embedder = &fakeEmbedder{model: "small", dims: 3}
+	if _, err := s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "alpha"}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	info, err = s.EmbedIndex(ctx)
+	if err != nil {
+		t.Fatalf("embed index: %v", err)
+	}
+	if info == nil || info.Model != "small" || info.Dims != 3 {
+		t.Fatalf("expected small/3, got %+v", info)
+	}
+}
+
+func TestDimsMismatchRejected(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.embedder = &fakeEmbedder{model: "small", dims: 3}
+	s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "alpha"})
+
+	s.embedder = &fakeEmbedder{model: "large", dims: 5}
+	_, err := s.Put(ctx, PutParams{NS: "ns", Key: "b", Content: "beta"})
+	if !errors.Is(err, ErrDimsMismatch) {
+		t.Fatalf("expected dims mismatch on put, got %v", err)
+	}
+	_, err = s.Search(ctx, SearchParams{Query: "alpha"})
+	if !errors.Is(err, ErrDimsMismatch) {
+		t.Fatalf("expected dims mismatch on search, got %v", err)
+	}
+
+	s.SetForceDims(true)
+	if _, err := s.Put(ctx, PutParams{NS: "ns", Key: "b", Content: "beta"}); err != nil {
+		t.Fatalf("put with force: %v", err)
+	}
+	if _, err := s.Search(ctx, SearchParams{Query: "alpha"}); err != nil {
+		t.Fatalf("search with force: %v", err)
+	}
+}
+
+func TestSetEmbedder(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.SetEmbedder(&fakeEmbedder{model: "small", dims: 3})
+	mem, err := s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "embedded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, _ := s.GetChunks(ctx, mem.ID)
+	if len(chunks) == 0 || !chunks[0].HasEmbedding {
+		t.Error("expected chunks to be embedded after SetEmbedder")
+	}
+
+	s.SetEmbedder(nil)
+	mem, err = s.Put(ctx, PutParams{NS: "ns", Key: "b", Content: "plain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, _ = s.GetChunks(ctx, mem.ID)
+	if len(chunks) == 0 || chunks[0].HasEmbedding {
+		t.Error("expected no embeddings after SetEmbedder(nil)")
+	}
+}
+
+// modeEmbedder records whether it was asked for document or query vectors.
+type modeEmbedder struct {
+	fakeEmbedder
+	docs, queries int
+}
+
+func (m *modeEmbedder) EmbedDocument(ctx context.Context, text string) (embedding.Vector, error) {
+	m.docs++
+	return m.Embed(ctx, text)
+}
+
+func (m *modeEmbedder) EmbedQuery(ctx context.Context, text string) (embedding.Vector, error) {
+	m.queries++
+	return m.Embed(ctx, text)
+}
+
+func TestEmbedDocumentAndQueryModes(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	e := &modeEmbedder{fakeEmbedder: fakeEmbedder{model: "asym", dims: 3}}
+	s.SetEmbedder(e)
+
+	if _, err := s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "stored text"}); err != nil {
+		t.Fatal(err)
+	}
+	if e.docs == 0 || e.queries != 0 {
+		t.Errorf("expected put to embed documents only, got docs=%d queries=%d", e.docs, e.queries)
+	}
+	docs := e.docs
+	if _, err := s.Search(ctx, SearchParams{NS: "ns", Query: "stored"}); err != nil {
+		t.Fatal(err)
+	}
+	if e.queries != 1 || e.docs != docs {
+		t.Errorf("expected search to embed one query, got docs=%d queries=%d", e.docs-docs, e.queries)
+	}
+}
+
+// lockProbeEmbedder writes to the database through a second connection on
+// every call, which fails if the caller holds the write lock.
+type lockProbeEmbedder struct {
+	fakeEmbedder
+	other *SQLiteStore
+	errs  []error
+}
+
+func (l *lockProbeEmbedder) Embed(ctx context.Context, text string) (embedding.Vector, error) {
+	if _, err := l.other.Put(ctx, PutParams{NS: "probe", Key: "k", Content: "x"}); err != nil {
+		l.errs = append(l.errs, err)
+	}
+	return l.fakeEmbedder.Embed(ctx, text)
+}
+
+func TestEmbedOutsideWriteTransaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	other, err := NewSQLiteStore(path, WithEmbedder(nil), WithBusyTimeout(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	e := &lockProbeEmbedder{fakeEmbedder: fakeEmbedder{model: "probe", dims: 3}, other: other}
+	s.SetEmbedder(e)
+	ctx := context.Background()
+
+	if _, err := s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.PutBatch(ctx, []PutParams{{NS: "ns", Key: "b", Content: "second"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Merge(ctx, MergeParams{IntoNS: "ns", IntoKey: "c", From: []MemoryRef{{"ns", "a"}, {"ns", "b"}}}); err != nil {
+		t.Fatal(err)
+	}
+	s.Put(ctx, PutParams{NS: "ns", Key: "doc", Content: "# A\n\none\n\n# B\n\ntwo"})
+	if _, err := s.Split(ctx, SplitParams{NS: "ns", Key: "doc"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.errs) > 0 {
+		t.Errorf("embedding ran under the write lock: %v", e.errs[0])
+	}
+}
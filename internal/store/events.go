@@ -0,0 +1,147 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Event operations recorded in the audit log.
+const (
+	EventPut      = "put"      // a new version was stored
+	EventRm       = "rm"       // versions were soft- or hard-deleted
+	EventUndelete = "undelete" // a soft-deleted version was restored
+	EventUpdate   = "update"   // a version was changed in place (e.g. its expiry)
+	EventLink     = "link"     // a link was created
+	EventUnlink   = "unlink"   // a link was removed
+	EventSchema   = "schema"   // a namespace schema was set or removed
+)
+
+// Event is one entry of the append-only mutation log. It is separate from
+// memory versions: hard deletes and in-place updates are still recorded.
+type Event struct {
+	ID      int64     `json:"id"`
+	At      time.Time `json:"at"`
+	Op      string    `json:"op"`
+	NS      string    `json:"ns"`
+	Key     string    `json:"key,omitempty"`
+	Version int       `json:"version,omitempty"` // 0 when the event spans versions
+	Actor   string    `json:"actor,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+}
+
+// EventsParams holds parameters for querying the event log.
+type EventsParams struct {
+	NS    string
+	Key   string
+	Op    string
+	Since time.Time // zero means the beginning
+	Limit int       // 0 means no limit
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// SetActor sets the name recorded as the actor of subsequent mutations.
+func (s *SQLiteStore) SetActor(actor string) {
+	s.actor = actor
+}
+
+// recordEvent appends an event to the log, inside the caller's transaction
+// when ex is one so the event commits or rolls back with the change.
+func (s *SQLiteStore) recordEvent(ctx context.Context, ex execer, op, ns, key string, version int, detail string) error {
+	_, err := ex.ExecContext(ctx,
+		`INSERT INTO events (at, op, ns, key, version, actor, detail) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		time.Now().UTC().Format(time.RFC3339), op, ns, nullIfEmpty(key), nullIfZero(version),
+		nullIfEmpty(s.actor), nullIfEmpty(detail))
+	if err != nil {
+		return fmt.Errorf("record event: %w", err)
+	}
+	return nil
+}
+
+// Events returns logged mutations matching p, oldest first.
+func (s *SQLiteStore) Events(ctx context.Context, p EventsParams) ([]Event, error) {
+	var where []string
+	var args []interface{}
+	if p.NS != "" {
+		where = append(where, "ns = ?")
+		args = append(args, p.NS)
+	}
+	if p.Key != "" {
+		where = append(where, "key = ?")
+		args = append(args, p.Key)
+	}
+	if p.Op != "" {
+		where = append(where, "op = ?")
+		args = append(args, p.Op)
+	}
+	if !p.Since.IsZero() {
+		where = append(where, "at >= ?")
+		args = append(args, p.Since.UTC().Format(time.RFC3339))
+	}
+
+	query := `SELECT id, at, op, ns, key, version, actor, detail FROM events`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	query += ` ORDER BY id`
+	if p.Limit > 0 {
+		// Keep the most recent Limit events, still in chronological order.
+		query = `SELECT * FROM (` + strings.Replace(query, "ORDER BY id", "ORDER BY id DESC", 1) +
+			` LIMIT ?) ORDER BY id`
+		args = append(args, p.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		var at string
+		var key, actor, detail sql.NullString
+		var version sql.NullInt64
+		if err := rows.Scan(&e.ID, &at, &e.Op, &e.NS, &key, &version, &actor, &detail); err != nil {
+			return nil, err
+		}
+		e.At, _ = time.Parse(time.RFC3339, at)
+		e.Key, e.Version, e.Actor, e.Detail = key.String, int(version.Int64), actor.String, detail.String
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// ParseSince parses an RFC3339 timestamp, or a duration such as 24h or 7d
+// meaning that long before now.
+func ParseSince(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := parseTTL(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q (use RFC3339 or e.g. 24h, 7d)", s)
+	}
+	return time.Now().Add(-d), nil
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullIfZero(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.SetActor("agent-a")
+	s.Put(ctx, PutParams{NS: "a", Key: "x", Content: "one"})
+	s.Put(ctx, PutParams{NS: "a", Key: "x", Content: "two"})
+	s.SetActor("")
+	s.Put(ctx, PutParams{NS: "b", Key: "y", Content: "three"})
+	if _, err := s.Link(ctx, LinkParams{FromNS: "a", FromKey: "x", ToNS: "b", ToKey: "y", Rel: "relates_to"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SetTTL(ctx, "a", "x", "1h"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Rm(ctx, RmParams{NS: "b", Key: "y", Hard: true, Cascade: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Rm(ctx, RmParams{NS: "a", Key: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Undelete(ctx, UndeleteParams{NS: "a", Key: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := s.Events(ctx, EventsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		op, ns, key string
+		version     int
+		actor       string
+	}{
+		{EventPut, "a", "x", 1, "agent-a"},
+		{EventPut, "a", "x", 2, "agent-a"},
+		{EventPut, "b", "y", 1, ""},
+		{EventLink, "a", "x", 0, ""},
+		{EventUpdate, "a", "x", 2, ""},
+		{EventRm, "b", "y", 1, ""},
+		{EventRm, "a", "x", 2, ""},
+		{EventUndelete, "a", "x", 2, ""},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Op != w.op || e.NS != w.ns || e.Key != w.key || e.Version != w.version || e.Actor != w.actor {
+			t.Errorf("event %d: expected %+v, got %+v", i, w, e)
+		}
+	}
+
+	got, _ := s.Events(ctx, EventsParams{NS: "b"})
+	if len(got) != 2 {
+		t.Errorf("expected 2 events for namespace b, got %d", len(got))
+	}
+	got, _ = s.Events(ctx, EventsParams{Op: EventPut, Limit: 1})
+	if len(got) != 1 || got[0].NS != "b" {
+		t.Errorf("expected the most recent put only, got %+v", got)
+	}
+	got, _ = s.Events(ctx, EventsParams{Since: time.Now().Add(time.Hour)})
+	if len(got) != 0 {
+		t.Errorf("expected no events in the future, got %d", len(got))
+	}
+
+	// Failed mutations leave no trace.
+	s.Rm(ctx, RmParams{NS: "a", Key: "missing"})
+	if all, _ := s.Events(ctx, EventsParams{}); len(all) != len(want) {
+		t.Errorf("expected failed rm to record nothing, got %d events", len(all))
+	}
+
+	if _, err := s.db.Exec(`DELETE FROM events`); err == nil {
+		t.Error("expected the event log to reject deletes")
+	}
+}
+
+func TestEventsSweep(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "a", Key: "old", Content: "x", ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339), AllowPast: true})
+	n, err := s.SweepExpired(ctx, false)
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 swept, got %d, %v", n, err)
+	}
+	events, _ := s.Events(ctx, EventsParams{Op: EventRm})
+	if len(events) != 1 || events[0].Key != "old" || events[0].Detail != "expired" {
+		t.Errorf("expected an rm event for the swept memory, got %+v", events)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	if got, err := ParseSince("2024-01-02T03:04:05Z"); err != nil || got.Year() != 2024 {
+		t.Errorf("expected RFC3339 to parse, got %v, %v", got, err)
+	}
+	got, err := ParseSince("2d")
+	if err != nil || time.Since(got) < 47*time.Hour {
+		t.Errorf("expected 2d ago, got %v, %v", got, err)
+	}
+	if _, err := ParseSince("yesterday"); err == nil {
+		t.Error("expected an error for an unparseable value")
+	}
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+// SetTTL sets expires_at on the latest version of a memory to now + ttl.
+// An empty ttl clears the expiry. Already-expired memories can be extended.
+func (s *SQLiteStore) SetTTL(ctx context.Context, ns, key, ttl string) (*model.Memory, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	now := time.Now().UTC()
+	var expiresAt *string
+	if ttl != "" {
+		d, err := parseTTL(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ttl: %w", err)
+		}
+		exp := now.Add(d).Format(time.RFC3339)
+		expiresAt = &exp
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	m, err := s.latestMemory(ctx, tx, ns, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE memories SET expires_at = ? WHERE id = ?`, expiresAt, m.ID); err != nil {
+		return nil, fmt.Errorf("update expiry: %w", err)
+	}
+	detail := "expires_at cleared"
+	if expiresAt != nil {
+		detail = "expires_at=" + *expiresAt
+	}
+	if err := s.recordEvent(ctx, tx, EventUpdate, ns, key, m.Version, detail); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	m.ExpiresAt = nil
+	if expiresAt != nil {
+		t, _ := time.Parse(time.RFC3339, *expiresAt)
+		m.ExpiresAt = &t
+	}
+	return m, nil
+}
+
+// ExpiringMemory is a memory with the time left before it expires.
+type ExpiringMemory struct {
+	model.Memory
+	ExpiresIn        string `json:"expires_in"`         // e.g. "3h12m5s"
+	ExpiresInSeconds int64  `json:"expires_in_seconds"` // for scripts
+}
+
+// Expiring returns the latest version of each memory whose expiry falls
+// within window from now, soonest first. Already-expired memories are not
+// included. An empty ns covers every namespace.
+func (s *SQLiteStore) Expiring(ctx context.Context, window time.Duration, ns string) ([]ExpiringMemory, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive")
+	}
+
+	now := time.Now().UTC()
+	where := []string{"m.deleted_at IS NULL", "m.expires_at > ?", "m.expires_at <= ?"}
+	args := []interface{}{now.Format(time.RFC3339), now.Add(window).Format(time.RFC3339)}
+	if ns != "" {
+		where = append(where, "m.ns = ?")
+		args = append(args, ns)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT m.id, m.ns, m.key, m.content, m.kind, m.tags, m.version, m.supersedes,
+		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at, m.content_encoding, m.actor
+		FROM memories m
+		INNER JOIN (
+			SELECT ns, key, MAX(version) AS max_ver
+			FROM memories WHERE deleted_at IS NULL
+			GROUP BY ns, key
+		) latest ON m.ns = latest.ns AND m.key = latest.key AND m.version = latest.max_ver
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY m.expires_at, m.ns, m.key`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []ExpiringMemory
+	for rows.Next() {
+		m, err := s.scanMemory(rows)
+		if err != nil {
+			return nil, err
+		}
+		left := m.ExpiresAt.Sub(now).Round(time.Second)
+		result = append(result, ExpiringMemory{
+			Memory:           m,
+			ExpiresIn:        left.String(),
+			ExpiresInSeconds: int64(left / time.Second),
+		})
+	}
+	return result, rows.Err()
+}
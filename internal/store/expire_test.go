@@ -0,0 +1,173 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSetTTL(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "k", Content: "v1"})
+	s.Put(ctx, PutParams{NS: "test", Key: "k", Content: "v2"})
+
+	m, err := s.SetTTL(ctx, "test", "k", "1h")
+	if err != nil {
+		t.Fatalf("set ttl: %v", err)
+	}
+	if m.Version != 2 {
+		t.Errorf("expected latest version 2, got %d", m.Version)
+	}
+	if m.ExpiresAt == nil || time.Until(*m.ExpiresAt) < 59*time.Minute {
+		t.Fatalf("expected expiry ~1h from now, got %v", m.ExpiresAt)
+	}
+
+	got, err := s.Get(ctx, GetParams{NS: "test", Key: "k"})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got[0].ExpiresAt == nil {
+		t.Error("expected stored expiry")
+	}
+
+	m, err = s.SetTTL(ctx, "test", "k", "")
+	if err != nil {
+		t.Fatalf("clear ttl: %v", err)
+	}
+	if m.ExpiresAt != nil {
+		t.Errorf("expected cleared expiry, got %v", m.ExpiresAt)
+	}
+}
+
+func TestSetTTLExtendsExpired(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "k", Content: "v1", TTL: "1h"})
+	past := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	s.db.Exec(`UPDATE memories SET expires_at = ?`, past)
+
+	if _, err := s.Get(ctx, GetParams{NS: "test", Key: "k"}); err == nil {
+		t.Fatal("expected expired memory to be hidden")
+	}
+	if _, err := s.SetTTL(ctx, "test", "k", "7d"); err != nil {
+		t.Fatalf("extend: %v", err)
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "test", Key: "k"}); err != nil {
+		t.Errorf("expected extended memory to be visible: %v", err)
+	}
+}
+
+func TestSetTTLErrors(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.SetTTL(ctx, "test", "missing", "1h"); err == nil {
+		t.Error("expected not found error")
+	}
+	s.Put(ctx, PutParams{NS: "test", Key: "k", Content: "v1"})
+	if _, err := s.SetTTL(ctx, "test", "k", "soon"); err == nil {
+		t.Error("expected invalid ttl error")
+	}
+}
+
+func TestPutExpiresAt(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	future := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	m, err := s.Put(ctx, PutParams{NS: "test", Key: "k", Content: "v1", ExpiresAt: future.Format(time.RFC3339)})
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	got, err := s.Get(ctx, GetParams{NS: "test", Key: m.Key})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got[0].ExpiresAt == nil || !got[0].ExpiresAt.Equal(future) {
+		t.Errorf("expected expiry %v, got %v", future, got[0].ExpiresAt)
+	}
+
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	if _, err := s.Put(ctx, PutParams{NS: "test", Key: "past", Content: "v", ExpiresAt: past}); err == nil {
+		t.Error("expected past expires_at to be rejected")
+	}
+	if _, err := s.Put(ctx, PutParams{NS: "test", Key: "past", Content: "v", ExpiresAt: past, AllowPast: true}); err != nil {
+		t.Errorf("expected past expires_at with AllowPast: %v", err)
+	}
+
+	if _, err := s.Put(ctx, PutParams{NS: "test", Key: "both", Content: "v", TTL: "1h", ExpiresAt: future.Format(time.RFC3339)}); err == nil {
+		t.Error("expected ttl and expires_at together to fail")
+	}
+	if _, err := s.Put(ctx, PutParams{NS: "test", Key: "bad", Content: "v", ExpiresAt: "2024-06-30"}); err == nil {
+		t.Error("expected non-RFC3339 expires_at to fail")
+	}
+}
+
+func TestExpiring(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "a", Key: "later", Content: "x", TTL: "2h"})
+	s.Put(ctx, PutParams{NS: "a", Key: "soon", Content: "x", TTL: "30m"})
+	s.Put(ctx, PutParams{NS: "b", Key: "far", Content: "x", TTL: "3d"})
+	s.Put(ctx, PutParams{NS: "a", Key: "forever", Content: "x"})
+	s.Put(ctx, PutParams{NS: "a", Key: "gone", Content: "x", ExpiresAt: time.Now().Add(-time.Minute).Format(time.RFC3339), AllowPast: true})
+	// Only the latest version counts: v2 of renewed has no TTL.
+	s.Put(ctx, PutParams{NS: "a", Key: "renewed", Content: "v1", TTL: "10m"})
+	s.Put(ctx, PutParams{NS: "a", Key: "renewed", Content: "v2"})
+
+	got, err := s.Expiring(ctx, 24*time.Hour, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Key != "soon" || got[1].Key != "later" {
+		t.Fatalf("expected soon, later, got %+v", got)
+	}
+	if got[0].ExpiresInSeconds < 29*60 || got[0].ExpiresInSeconds > 30*60 || got[0].ExpiresIn == "" {
+		t.Errorf("expected ~30m remaining, got %s (%ds)", got[0].ExpiresIn, got[0].ExpiresInSeconds)
+	}
+
+	got, _ = s.Expiring(ctx, 7*24*time.Hour, "b")
+	if len(got) != 1 || got[0].Key != "far" {
+		t.Errorf("expected only b/far, got %+v", got)
+	}
+
+	if _, err := s.Expiring(ctx, 0, ""); err == nil {
+		t.Error("expected an error for a zero window")
+	}
+}
+
+func TestGetIncludeExpired(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	s.Put(ctx, PutParams{NS: "test", Key: "k", Content: "v1", ExpiresAt: past, AllowPast: true})
+	s.Put(ctx, PutParams{NS: "test", Key: "gone", Content: "x", ExpiresAt: past, AllowPast: true})
+	s.Put(ctx, PutParams{NS: "test", Key: "k", Content: "v2"})
+
+	if _, err := s.Get(ctx, GetParams{NS: "test", Key: "k", Version: 1}); err == nil {
+		t.Fatal("expected expired version to be hidden by default")
+	}
+	got, err := s.Get(ctx, GetParams{NS: "test", Key: "k", Version: 1, IncludeExpired: true})
+	if err != nil {
+		t.Fatalf("get expired version: %v", err)
+	}
+	if got[0].Content != "v1" || got[0].ExpiresAt == nil {
+		t.Errorf("expected v1 with its expiry, got %+v", got[0])
+	}
+
+	got, err = s.Get(ctx, GetParams{NS: "test", Key: "gone", IncludeExpired: true})
+	if err != nil || got[0].Content != "x" {
+		t.Fatalf("expected expired latest version, got %v, %v", got, err)
+	}
+
+	var count int
+	s.db.QueryRow(`SELECT SUM(access_count) FROM memories`).Scan(&count)
+	if count != 0 {
+		t.Errorf("expected audit reads not to count as accesses, got %d", count)
+	}
+}
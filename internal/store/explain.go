@@ -0,0 +1,52 @@
+package store
+
+import (
+	"math"
+	"time"
+)
+
+// Ranking stages reported in ScoreExplain.RankedBy.
+const (
+	RankedByFTS        = "fts"        // weighted priority + recency + bm25 match
+	RankedByRecency    = "recency"    // substring match, newest first
+	RankedBySimilarity = "similarity" // vector cosine similarity
+)
+
+// ScoreExplain breaks down how a search result was scored. Fields that do
+// not apply to the stage that found the result are left zero.
+type ScoreExplain struct {
+	RankedBy      string  `json:"ranked_by"`
+	FTSRank       float64 `json:"fts_rank,omitempty"`       // raw bm25; more negative is a better match
+	Similarity    float64 `json:"similarity,omitempty"`     // best chunk cosine similarity, if embedded
+	Priority      float64 `json:"priority"`                 // low=0.25 .. critical=1.0
+	PriorityBoost float64 `json:"priority_boost,omitempty"` // Priority * weight
+	AgeDays       float64 `json:"age_days"`
+	RecencyFactor float64 `json:"recency_factor,omitempty"` // exp(-decay_per_day * age_days)
+	RecencyBoost  float64 `json:"recency_boost,omitempty"`  // RecencyFactor * weight
+	MatchBoost    float64 `json:"match_boost,omitempty"`    // -FTSRank * weight
+	Combined      float64 `json:"combined,omitempty"`       // sum of the boosts; FTS results are ordered by it
+	Score         float64 `json:"score"`                    // the result's final normalized score
+}
+
+// explainFTS reproduces the FTS ORDER BY expression for one result.
+func explainFTS(r SearchResult, rank float64, w RankWeights, now time.Time) *ScoreExplain {
+	e := baseExplain(r, RankedByFTS, now)
+	e.FTSRank = roundScore(rank)
+	e.PriorityBoost = roundScore(e.Priority * w.Priority)
+	factor := math.Exp(-w.DecayPerDay * now.Sub(r.CreatedAt).Hours() / 24)
+	e.RecencyFactor = roundScore(factor)
+	e.RecencyBoost = roundScore(factor * w.Recency)
+	e.MatchBoost = roundScore(-rank * w.Match)
+	e.Combined = roundScore(e.Priority*w.Priority + factor*w.Recency - rank*w.Match)
+	return e
+}
+
+func baseExplain(r SearchResult, rankedBy string, now time.Time) *ScoreExplain {
+	return &ScoreExplain{
+		RankedBy:   rankedBy,
+		Similarity: r.Similarity,
+		Priority:   priorityScore(r.Priority),
+		AgeDays:    roundScore(now.Sub(r.CreatedAt).Hours() / 24),
+		Score:      r.Score,
+	}
+}
@@ -2,59 +2,302 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
 
 	"github.com/rcliao/agent-memory/internal/model"
 )
 
-// ExportAll returns all non-deleted memories, optionally filtered by namespace.
+// ExportParams controls which memories an export includes.
+type ExportParams struct {
+	NS             string
+	IncludeDeleted bool      // also export soft-deleted versions
+	Since          time.Time // only versions created at or after this; zero means all
+	SinceID        string    // only versions with a greater (later) ULID
+}
+
+// ExportAll returns every non-deleted version of every memory, including
+// expired ones, optionally filtered by namespace.
 func (s *SQLiteStore) ExportAll(ctx context.Context, ns string) ([]model.Memory, error) {
-	where := []string{"deleted_at IS NULL"}
-	args := []interface{}{}
+	var memories []model.Memory
+	err := s.ExportEach(ctx, ExportParams{NS: ns}, func(m model.Memory) error {
+		memories = append(memories, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return memories, nil
+}
 
-	if ns != "" {
+// ExportStream writes the selected memories to w as JSON Lines, one version
+// per line, without holding the whole export in memory. It returns the number
+// of memories written.
+func (s *SQLiteStore) ExportStream(ctx context.Context, p ExportParams, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	n := 0
+	err := s.ExportEach(ctx, p, func(m model.Memory) error {
+		if err := enc.Encode(m); err != nil {
+			return fmt.Errorf("write memory %s/%s v%d: %w", m.NS, m.Key, m.Version, err)
+		}
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// ExportEach calls fn for every exported memory in ns/key/version order,
+// stopping at the first error fn returns. fn may use the store.
+func (s *SQLiteStore) ExportEach(ctx context.Context, p ExportParams, fn func(model.Memory) error) error {
+	var where []string
+	var args []interface{}
+
+	if !p.IncludeDeleted {
+		where = append(where, "deleted_at IS NULL")
+	}
+	if p.NS != "" {
 		where = append(where, "ns = ?")
-		args = append(args, ns)
+		args = append(args, p.NS)
+	}
+	if !p.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, p.Since.UTC().Format(time.RFC3339))
+	}
+	if p.SinceID != "" {
+		id, err := ulid.ParseStrict(p.SinceID)
+		if err != nil {
+			return fmt.Errorf("invalid since id %q: %w", p.SinceID, err)
+		}
+		// ULIDs sort by creation time, so this picks up every version
+		// stored after the watermark was taken.
+		where = append(where, "id > ?")
+		args = append(args, id.String())
 	}
 
 	query := `SELECT id, ns, key, content, kind, tags, version, supersedes,
-	                 created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at
-	          FROM memories WHERE ` + strings.Join(where, " AND ") + ` ORDER BY ns, key, version`
+	                 created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor
+	          FROM memories WHERE ` + strings.Join(append(where, "(ns, key, version, id) > (?, ?, ?, ?)"), " AND ") + `
+	          ORDER BY ns, key, version, id LIMIT ?`
 
+	// Read a page at a time and close the rows before calling fn: the pool
+	// has a single connection, so fn could not query the store otherwise.
+	var last model.Memory
+	for {
+		page, err := s.exportPage(ctx, query, append(args, last.NS, last.Key, last.Version, last.ID, exportPageSize)...)
+		if err != nil {
+			return err
+		}
+		for _, m := range page {
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+		last = page[len(page)-1]
+	}
+}
+
+// exportPageSize is how many memories ExportEach reads per query.
+var exportPageSize = 500
+
+func (s *SQLiteStore) exportPage(ctx context.Context, query string, args ...interface{}) ([]model.Memory, error) {
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var memories []model.Memory
+	var page []model.Memory
 	for rows.Next() {
-		m, err := scanMemory(rows)
+		m, err := s.scanMemory(rows)
 		if err != nil {
 			return nil, err
 		}
-		memories = append(memories, m)
+		page = append(page, m)
 	}
-	return memories, nil
+	return page, rows.Err()
+}
+
+// MergeStrategy decides what Import does with a memory whose ns+key already
+// exists in the store.
+type MergeStrategy string
+
+// Merge strategies for Import.
+const (
+	MergeVersion MergeStrategy = "version" // add it as a new version (default)
+	MergeSkip    MergeStrategy = "skip"    // keep the existing memory
+	MergeNewest  MergeStrategy = "newest"  // import only if its created_at is newer
+)
+
+// ImportParams holds parameters for Import.
+type ImportParams struct {
+	Memories   []model.Memory
+	Force      bool // import every memory, even if an identical version exists
+	OnConflict MergeStrategy
+}
+
+// ImportResult reports how many memories Import stored and skipped.
+type ImportResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+}
+
+// Import stores memories from an export, replaying each version as a new put.
+// Versions exported with a deleted_at timestamp are imported soft-deleted.
+//
+// OnConflict applies to keys that already existed before the import began;
+// once a key has been imported, its later versions from the same dump follow.
+// Unless Force is set, a memory is also skipped when the store already holds
+// the same ns+key with identical content, either as the latest version or at
+// the exported version number, so re-running an import does not add versions.
+func (s *SQLiteStore) Import(ctx context.Context, p ImportParams) (*ImportResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	switch p.OnConflict {
+	case "":
+		p.OnConflict = MergeVersion
+	case MergeVersion, MergeSkip, MergeNewest:
+	default:
+		return nil, fmt.Errorf("invalid merge strategy %q (use version, skip, or newest)", p.OnConflict)
+	}
+
+	result := &ImportResult{}
+	accepted := map[string]bool{}
+	for _, m := range p.Memories {
+		id := m.NS + "\x00" + m.Key
+		if !accepted[id] && p.OnConflict != MergeVersion {
+			created, exists, err := s.latestCreatedAt(ctx, m.NS, m.Key)
+			if err != nil {
+				return result, err
+			}
+			if exists && (p.OnConflict == MergeSkip || !m.CreatedAt.After(created)) {
+				result.Skipped++
+				continue
+			}
+		}
+
+		if !p.Force {
+			dup, err := s.hasIdenticalVersion(ctx, m)
+			if err != nil {
+				return result, err
+			}
+			if dup {
+				result.Skipped++
+				continue
+			}
+		}
+
+		if err := s.importVersion(ctx, m); err != nil {
+			return result, err
+		}
+		accepted[id] = true
+		result.Imported++
+	}
+	return result, nil
 }
 
-// Import stores memories from an export. Skips duplicates (same ns+key+version).
-func (s *SQLiteStore) Import(ctx context.Context, memories []model.Memory) (int, error) {
-	imported := 0
-	for _, m := range memories {
-		_, err := s.Put(ctx, PutParams{
-			NS:       m.NS,
-			Key:      m.Key,
-			Content:  m.Content,
-			Kind:     m.Kind,
-			Tags:     m.Tags,
-			Priority: m.Priority,
-			Meta:     m.Meta,
-		})
+// importVersion stores m as a new version of its key. A version exported as
+// deleted is soft-deleted in the same transaction, so it is never live.
+func (s *SQLiteStore) importVersion(ctx context.Context, m model.Memory) error {
+	put := PutParams{
+		NS:       m.NS,
+		Key:      m.Key,
+		Content:  m.Content,
+		Kind:     m.Kind,
+		Tags:     m.Tags,
+		Priority: m.Priority,
+		Meta:     m.Meta,
+		Actor:    m.Actor,
+	}
+	chunks, err := s.prepareChunks(ctx, put)
+	if err != nil {
+		return err
+	}
+	index, err := s.EmbedIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	mem, err := s.putTx(ctx, tx, put, chunks, &index)
+	if err != nil {
+		return err
+	}
+	if m.DeletedAt != nil {
+		if _, err := tx.ExecContext(ctx, `UPDATE memories SET deleted_at = ? WHERE id = ?`,
+			m.DeletedAt.UTC().Format(time.RFC3339), mem.ID); err != nil {
+			return err
+		}
+		if err := s.recordEvent(ctx, tx, EventRm, m.NS, m.Key, mem.Version, "import"); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// latestCreatedAt returns the created_at of the latest non-deleted version of
+// ns/key, and whether one exists.
+func (s *SQLiteStore) latestCreatedAt(ctx context.Context, ns, key string) (time.Time, bool, error) {
+	var created string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT created_at FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL
+		 ORDER BY version DESC LIMIT 1`, ns, key).Scan(&created)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	t, err := time.Parse(time.RFC3339, created)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("parse created_at for %s/%s: %w", ns, key, err)
+	}
+	return t, true, nil
+}
+
+// hasIdenticalVersion reports whether m's ns+key already has m's content as
+// its latest version or at m's version number.
+func (s *SQLiteStore) hasIdenticalVersion(ctx context.Context, m model.Memory) (bool, error) {
+	// Compared in Go rather than SQL because encrypted or compressed content
+	// never matches byte-for-byte.
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT content, content_encoding FROM memories
+		 WHERE ns = ? AND key = ?
+		   AND (version = ? OR version = (SELECT MAX(version) FROM memories WHERE ns = ? AND key = ?))`,
+		m.NS, m.Key, m.Version, m.NS, m.Key)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var content string
+		var encoding sql.NullString
+		if err := rows.Scan(&content, &encoding); err != nil {
+			return false, err
+		}
+		plain, err := s.decodeContent(content, encoding)
 		if err != nil {
-			return imported, err
+			return false, err
+		}
+		if plain == m.Content {
+			return true, nil
 		}
-		imported++
 	}
-	return imported, nil
+	return false, rows.Err()
 }
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// DefaultTokenizer is the FTS5 tokenizer used when none is configured.
+// Porter stemming lets "running" match "run" and "deploys" match "deploy".
+const DefaultTokenizer = "porter unicode61"
+
+const metaFTSTokenizer = "fts_tokenizer"
+
+var tokenizerRegex = regexp.MustCompile(`^[a-z0-9_ ]+$`)
+
+// migrateFTS creates the chunks_fts index with the configured tokenizer,
+// rebuilding it from the chunks table when the tokenizer has changed.
+func (s *SQLiteStore) migrateFTS() error {
+	tokenizer := s.tokenizer
+	if tokenizer == "" {
+		tokenizer = DefaultTokenizer
+	}
+	if !tokenizerRegex.MatchString(tokenizer) {
+		return fmt.Errorf("invalid tokenizer %q", tokenizer)
+	}
+
+	var current string
+	err := s.db.QueryRow(`SELECT value FROM store_meta WHERE key = ?`, metaFTSTokenizer).Scan(&current)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	var exists int
+	if err := s.db.QueryRow(
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'chunks_fts'`).Scan(&exists); err != nil {
+		return err
+	}
+	if exists > 0 && current == tokenizer {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Tables created before tokenizer tracking used the FTS5 default
+	if exists > 0 {
+		if _, err := tx.Exec(`DROP TABLE chunks_fts`); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`CREATE VIRTUAL TABLE chunks_fts USING fts5(
+		text,
+		content=chunks,
+		content_rowid=rowid,
+		tokenize='%s'
+	)`, tokenizer)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO chunks_fts(chunks_fts) VALUES('rebuild')`); err != nil {
+		return fmt.Errorf("rebuild index: %w", err)
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO store_meta (key, value) VALUES (?, ?)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value`, metaFTSTokenizer, tokenizer); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
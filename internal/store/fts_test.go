@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestFTSStemming(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "jog", Content: "went running this morning"})
+
+	results, err := s.Search(ctx, SearchParams{Query: "runs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected stemmed match, got %d results", len(results))
+	}
+}
+
+func TestFTSTokenizerRebuild(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	t.Setenv("AGENT_MEMORY_FTS_TOKENIZER", "unicode61")
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Put(ctx, PutParams{NS: "test", Key: "jog", Content: "went running this morning"})
+	results, _ := s.Search(ctx, SearchParams{Query: "runs"})
+	if len(results) != 0 {
+		t.Fatalf("expected no stemmed match with unicode61, got %d", len(results))
+	}
+	s.Close()
+
+	// Reopening with a different tokenizer rebuilds the index
+	t.Setenv("AGENT_MEMORY_FTS_TOKENIZER", "porter unicode61")
+	s, err = NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	results, _ = s.Search(ctx, SearchParams{Query: "runs"})
+	if len(results) != 1 {
+		t.Fatalf("expected stemmed match after rebuild, got %d", len(results))
+	}
+}
+
+func TestFTSInvalidTokenizer(t *testing.T) {
+	t.Setenv("AGENT_MEMORY_FTS_TOKENIZER", "porter'); DROP TABLE memories; --")
+	if _, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db")); err == nil {
+		t.Fatal("expected error for invalid tokenizer")
+	}
+}
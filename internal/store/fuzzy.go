@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps how many close keys a failed Get suggests.
+const maxSuggestions = 3
+
+// similarKeys returns up to maxSuggestions live keys in ns that are close to
+// key by case-insensitive Levenshtein distance, closest first (ties go to the
+// smaller case-sensitive distance). Keys may differ
+// by at most a third of key's length, so keys shorter than three characters
+// get no suggestions. key itself is never returned.
+func (s *SQLiteStore) similarKeys(ctx context.Context, ns, key string) ([]string, error) {
+	keys, err := s.ListKeys(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	target := strings.ToLower(key)
+	limit := len([]rune(target)) / 3
+	if limit == 0 {
+		return nil, nil
+	}
+
+	type candidate struct {
+		key         string
+		dist, exact int
+	}
+	var matches []candidate
+	for _, k := range keys {
+		if k == key {
+			continue
+		}
+		if d := levenshtein(target, strings.ToLower(k)); d <= limit {
+			matches = append(matches, candidate{k, d, levenshtein(key, k)})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].dist != matches[j].dist {
+			return matches[i].dist < matches[j].dist
+		}
+		return matches[i].exact < matches[j].exact
+	})
+
+	var out []string
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		out = append(out, matches[i].key)
+	}
+	return out, nil
+}
+
+// levenshtein returns the edit distance between a and b in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"deploy-prod", "deploy_prod", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestGetFuzzy(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "deploy_prod", Content: "prod"})
+	s.Put(ctx, PutParams{NS: "test", Key: "deploy_stage", Content: "stage"})
+	s.Put(ctx, PutParams{NS: "test", Key: "Deploy-Prod2", Content: "other"})
+	s.Put(ctx, PutParams{NS: "other", Key: "deploy-prod", Content: "elsewhere"})
+
+	_, err := s.Get(ctx, GetParams{NS: "test", Key: "deploy-prod"})
+	if err == nil || !strings.Contains(err.Error(), "did you mean: deploy_prod, Deploy-Prod2?") {
+		t.Errorf("expected suggestions closest first, got %v", err)
+	}
+
+	got, err := s.Get(ctx, GetParams{NS: "test", Key: "deploy-prod", Fuzzy: true})
+	if err != nil {
+		t.Fatalf("fuzzy get: %v", err)
+	}
+	if got[0].Key != "deploy_prod" || got[0].Content != "prod" {
+		t.Errorf("expected closest match deploy_prod, got %s", got[0].Key)
+	}
+
+	_, err = s.Get(ctx, GetParams{NS: "test", Key: "unrelated", Fuzzy: true})
+	if err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("expected plain not-found for a distant key, got %v", err)
+	}
+
+	// An existing key with a missing version is not a typo.
+	_, err = s.Get(ctx, GetParams{NS: "test", Key: "deploy_prod", Version: 9, Fuzzy: true})
+	if err == nil {
+		t.Error("expected missing version to fail even with Fuzzy")
+	}
+}
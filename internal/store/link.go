@@ -2,7 +2,9 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -14,6 +16,9 @@ type LinkParams struct {
 	ToKey   string
 	Rel     string // relates_to | contradicts | depends_on | refines
 	Remove  bool
+	// Bidirectional also creates (or removes) the reverse edge. Only
+	// symmetric relations (relates_to, contradicts) allow it.
+	Bidirectional bool
 }
 
 // Link represents a relation between two memories.
@@ -22,6 +27,7 @@ type Link struct {
 	ToID      string `json:"to_id"`
 	Rel       string `json:"rel"`
 	CreatedAt string `json:"created_at"`
+	Warning   string `json:"warning,omitempty"`
 }
 
 var validRels = map[string]bool{
@@ -31,40 +37,170 @@ var validRels = map[string]bool{
 	"refines":     true,
 }
 
+// symmetricRels read the same in both directions.
+var symmetricRels = map[string]bool{
+	"relates_to":  true,
+	"contradicts": true,
+}
+
+func validateLink(p LinkParams) error {
+	if !validRels[p.Rel] {
+		return fmt.Errorf("invalid relation %q (valid: relates_to, contradicts, depends_on, refines)", p.Rel)
+	}
+	if p.Bidirectional && !symmetricRels[p.Rel] {
+		return fmt.Errorf("relation %q is directed; only relates_to and contradicts can be bidirectional", p.Rel)
+	}
+	return nil
+}
+
 // Link creates or removes a relation between two memories.
 func (s *SQLiteStore) Link(ctx context.Context, p LinkParams) (*Link, error) {
-	if !validRels[p.Rel] {
-		return nil, fmt.Errorf("invalid relation %q (valid: relates_to, contradicts, depends_on, refines)", p.Rel)
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	if err := validateLink(p); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	link, err := linkTx(ctx, tx, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordLinkEvent(ctx, tx, p); err != nil {
+		return nil, err
+	}
+	return link, tx.Commit()
+}
+
+// recordLinkEvent logs a link or unlink under the source memory.
+func (s *SQLiteStore) recordLinkEvent(ctx context.Context, tx *sql.Tx, p LinkParams) error {
+	op := EventLink
+	if p.Remove {
+		op = EventUnlink
+	}
+	detail := fmt.Sprintf("%s -> %s/%s", p.Rel, p.ToNS, p.ToKey)
+	if p.Bidirectional {
+		detail += " (both)"
+	}
+	return s.recordEvent(ctx, tx, op, p.FromNS, p.FromKey, 0, detail)
+}
+
+// LinkBatchError records why one entry of a batch failed.
+type LinkBatchError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// LinkBatchResult summarizes a LinkBatch call.
+type LinkBatchResult struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Errors    []LinkBatchError `json:"errors,omitempty"`
+}
+
+// LinkBatch applies many links in one transaction. Relations are validated
+// up front and nothing is written if any is invalid. Entries whose memories
+// cannot be resolved are reported in the result and skipped.
+func (s *SQLiteStore) LinkBatch(ctx context.Context, links []LinkParams) (*LinkBatchResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	for i, p := range links {
+		if err := validateLink(p); err != nil {
+			return nil, fmt.Errorf("link %d: %w", i, err)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &LinkBatchResult{}
+	for i, p := range links {
+		if _, err := linkTx(ctx, tx, p); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, LinkBatchError{Index: i, Error: err.Error()})
+			continue
+		}
+		if err := s.recordLinkEvent(ctx, tx, p); err != nil {
+			return nil, err
+		}
+		result.Succeeded++
 	}
+	return result, tx.Commit()
+}
 
-	fromID, err := s.resolveMemoryID(ctx, p.FromNS, p.FromKey)
+// linkTx creates or removes a single link inside tx. The relation must
+// already be validated.
+func linkTx(ctx context.Context, tx *sql.Tx, p LinkParams) (*Link, error) {
+	fromID, err := resolveMemoryID(ctx, tx, p.FromNS, p.FromKey)
 	if err != nil {
 		return nil, fmt.Errorf("resolve from: %w", err)
 	}
-	toID, err := s.resolveMemoryID(ctx, p.ToNS, p.ToKey)
+	toID, err := resolveMemoryID(ctx, tx, p.ToNS, p.ToKey)
 	if err != nil {
 		return nil, fmt.Errorf("resolve to: %w", err)
 	}
 
+	edges := [][2]string{{fromID, toID}}
+	if p.Bidirectional {
+		edges = append(edges, [2]string{toID, fromID})
+	}
+
 	if p.Remove {
-		_, err := s.db.ExecContext(ctx,
-			`DELETE FROM memory_links WHERE from_id = ? AND to_id = ? AND rel = ?`,
-			fromID, toID, p.Rel)
+		for _, e := range edges {
+			_, err := tx.ExecContext(ctx,
+				`DELETE FROM memory_links WHERE from_id = ? AND to_id = ? AND rel = ?`,
+				e[0], e[1], p.Rel)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &Link{FromID: fromID, ToID: toID, Rel: p.Rel}, nil
+	}
+
+	if fromID == toID {
+		return nil, fmt.Errorf("cannot link %s:%s to itself", p.FromNS, p.FromKey)
+	}
+
+	// A directed relation in both directions is usually a mistake (e.g. a
+	// two-node depends_on cycle), so flag it without refusing.
+	var warning string
+	if !symmetricRels[p.Rel] {
+		var n int
+		err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM memory_links l
+			 INNER JOIN memories f ON f.id = l.from_id
+			 INNER JOIN memories t ON t.id = l.to_id
+			 WHERE l.rel = ? AND f.ns = ? AND f.key = ? AND t.ns = ? AND t.key = ?`,
+			p.Rel, p.ToNS, p.ToKey, p.FromNS, p.FromKey).Scan(&n)
 		if err != nil {
 			return nil, err
 		}
-		return &Link{FromID: fromID, ToID: toID, Rel: p.Rel}, nil
+		if n > 0 {
+			warning = fmt.Sprintf("inverse link %s:%s -%s-> %s:%s already exists", p.ToNS, p.ToKey, p.Rel, p.FromNS, p.FromKey)
+		}
 	}
 
 	now := time.Now().UTC().Format(time.RFC3339)
-	_, err = s.db.ExecContext(ctx,
-		`INSERT OR IGNORE INTO memory_links (from_id, to_id, rel, created_at) VALUES (?, ?, ?, ?)`,
-		fromID, toID, p.Rel, now)
-	if err != nil {
-		return nil, err
+	for _, e := range edges {
+		_, err = tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO memory_links (from_id, to_id, rel, created_at) VALUES (?, ?, ?, ?)`,
+			e[0], e[1], p.Rel, now)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return &Link{FromID: fromID, ToID: toID, Rel: p.Rel, CreatedAt: now}, nil
+	return &Link{FromID: fromID, ToID: toID, Rel: p.Rel, CreatedAt: now, Warning: warning}, nil
 }
 
 // GetLinks returns all links for a memory.
@@ -89,9 +225,9 @@ func (s *SQLiteStore) GetLinks(ctx context.Context, memoryID string) ([]Link, er
 }
 
 // resolveMemoryID finds the latest memory ID for a ns:key pair.
-func (s *SQLiteStore) resolveMemoryID(ctx context.Context, ns, key string) (string, error) {
+func resolveMemoryID(ctx context.Context, q rowQuerier, ns, key string) (string, error) {
 	var id string
-	err := s.db.QueryRowContext(ctx,
+	err := q.QueryRowContext(ctx,
 		`SELECT id FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL
 		 ORDER BY version DESC LIMIT 1`, ns, key).Scan(&id)
 	if err != nil {
@@ -99,3 +235,68 @@ func (s *SQLiteStore) resolveMemoryID(ctx context.Context, ns, key string) (stri
 	}
 	return id, nil
 }
+
+// ListLinksParams holds filters for listing links.
+type ListLinksParams struct {
+	NS             string // either endpoint in this namespace
+	Rel            string
+	IncludeDeleted bool // include links whose endpoints are soft-deleted
+}
+
+// NamedLink is a link with its endpoints resolved to ns/key.
+type NamedLink struct {
+	FromNS    string `json:"from_ns"`
+	FromKey   string `json:"from_key"`
+	ToNS      string `json:"to_ns"`
+	ToKey     string `json:"to_key"`
+	Rel       string `json:"rel"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ListLinks returns links with ns/key endpoints. Links recorded against
+// several versions of the same memories are reported once.
+func (s *SQLiteStore) ListLinks(ctx context.Context, p ListLinksParams) ([]NamedLink, error) {
+	var where []string
+	var args []interface{}
+	if !p.IncludeDeleted {
+		where = append(where, "f.deleted_at IS NULL", "t.deleted_at IS NULL")
+	}
+	if p.NS != "" {
+		where = append(where, "(f.ns = ? OR t.ns = ?)")
+		args = append(args, p.NS, p.NS)
+	}
+	if p.Rel != "" {
+		if err := validateLink(LinkParams{Rel: p.Rel}); err != nil {
+			return nil, err
+		}
+		where = append(where, "l.rel = ?")
+		args = append(args, p.Rel)
+	}
+	whereSQL := ""
+	if len(where) > 0 {
+		whereSQL = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT f.ns, f.key, t.ns, t.key, l.rel, MIN(l.created_at)
+		FROM memory_links l
+		INNER JOIN memories f ON f.id = l.from_id
+		INNER JOIN memories t ON t.id = l.to_id
+		`+whereSQL+`
+		GROUP BY f.ns, f.key, t.ns, t.key, l.rel
+		ORDER BY f.ns, f.key, l.rel, t.ns, t.key`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []NamedLink{}
+	for rows.Next() {
+		var l NamedLink
+		if err := rows.Scan(&l.FromNS, &l.FromKey, &l.ToNS, &l.ToKey, &l.Rel, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
@@ -2,6 +2,8 @@ package store
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -81,3 +83,200 @@ func TestLinkInvalidRel(t *testing.T) {
 		t.Fatal("expected error for invalid relation")
 	}
 }
+
+func TestLinkBatch(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "svc", Key: "api", Content: "api"})
+	s.Put(ctx, PutParams{NS: "svc", Key: "db", Content: "db"})
+	s.Put(ctx, PutParams{NS: "svc", Key: "cache", Content: "cache"})
+
+	result, err := s.LinkBatch(ctx, []LinkParams{
+		{FromNS: "svc", FromKey: "api", ToNS: "svc", ToKey: "db", Rel: "depends_on"},
+		{FromNS: "svc", FromKey: "api", ToNS: "svc", ToKey: "missing", Rel: "depends_on"},
+		{FromNS: "svc", FromKey: "api", ToNS: "svc", ToKey: "cache", Rel: "depends_on"},
+	})
+	if err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if result.Succeeded != 2 || result.Failed != 1 {
+		t.Errorf("expected 2 ok / 1 failed, got %+v", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Index != 1 {
+		t.Errorf("expected error for entry 1, got %+v", result.Errors)
+	}
+
+	var n int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links`).Scan(&n)
+	if n != 2 {
+		t.Errorf("expected 2 links stored, got %d", n)
+	}
+}
+
+func TestLinkBatchValidatesRelsFirst(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "svc", Key: "api", Content: "api"})
+	s.Put(ctx, PutParams{NS: "svc", Key: "db", Content: "db"})
+
+	_, err := s.LinkBatch(ctx, []LinkParams{
+		{FromNS: "svc", FromKey: "api", ToNS: "svc", ToKey: "db", Rel: "depends_on"},
+		{FromNS: "svc", FromKey: "db", ToNS: "svc", ToKey: "api", Rel: "owns"},
+	})
+	if err == nil {
+		t.Fatal("expected invalid relation to fail the batch")
+	}
+
+	var n int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links`).Scan(&n)
+	if n != 0 {
+		t.Errorf("expected nothing written, got %d links", n)
+	}
+}
+
+func TestLinkBidirectional(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	a, _ := s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "a"})
+	b, _ := s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "b"})
+
+	_, err := s.Link(ctx, LinkParams{FromNS: "test", FromKey: "a", ToNS: "test", ToKey: "b", Rel: "contradicts", Bidirectional: true})
+	if err != nil {
+		t.Fatalf("link: %v", err)
+	}
+
+	var n int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links WHERE from_id = ? AND to_id = ?`, b.ID, a.ID).Scan(&n)
+	if n != 1 {
+		t.Error("expected reverse edge b -> a")
+	}
+
+	_, err = s.Link(ctx, LinkParams{FromNS: "test", FromKey: "a", ToNS: "test", ToKey: "b", Rel: "contradicts", Bidirectional: true, Remove: true})
+	if err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links`).Scan(&n)
+	if n != 0 {
+		t.Errorf("expected both edges removed, %d left", n)
+	}
+
+	if _, err := s.Link(ctx, LinkParams{FromNS: "test", FromKey: "a", ToNS: "test", ToKey: "b", Rel: "depends_on", Bidirectional: true}); err == nil {
+		t.Error("expected directed relation to reject bidirectional")
+	}
+}
+
+func TestLinkSelf(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "a"})
+	if _, err := s.Link(ctx, LinkParams{FromNS: "test", FromKey: "a", ToNS: "test", ToKey: "a", Rel: "depends_on"}); err == nil {
+		t.Error("expected self-link to be rejected")
+	}
+}
+
+func TestLinkInverseWarning(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "a"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "b"})
+
+	l, err := s.Link(ctx, LinkParams{FromNS: "test", FromKey: "a", ToNS: "test", ToKey: "b", Rel: "depends_on"})
+	if err != nil {
+		t.Fatalf("first link: %v", err)
+	}
+	if l.Warning != "" {
+		t.Fatalf("first link: unexpected warning %q", l.Warning)
+	}
+	l, err = s.Link(ctx, LinkParams{FromNS: "test", FromKey: "b", ToNS: "test", ToKey: "a", Rel: "depends_on"})
+	if err != nil {
+		t.Fatalf("inverse link: %v", err)
+	}
+	if l.Warning == "" {
+		t.Error("expected warning for inverse depends_on")
+	}
+
+	// Symmetric relations are expected in both directions
+	s.Link(ctx, LinkParams{FromNS: "test", FromKey: "a", ToNS: "test", ToKey: "b", Rel: "relates_to"})
+	l, _ = s.Link(ctx, LinkParams{FromNS: "test", FromKey: "b", ToNS: "test", ToKey: "a", Rel: "relates_to"})
+	if l.Warning != "" {
+		t.Errorf("unexpected warning for symmetric relation: %q", l.Warning)
+	}
+}
+
+func TestListLinks(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "svc", Key: "api", Content: "api"})
+	s.Put(ctx, PutParams{NS: "svc", Key: "db", Content: "db"})
+	s.Put(ctx, PutParams{NS: "docs", Key: "guide", Content: "guide"})
+	s.Link(ctx, LinkParams{FromNS: "svc", FromKey: "api", ToNS: "svc", ToKey: "db", Rel: "depends_on"})
+	s.Link(ctx, LinkParams{FromNS: "docs", FromKey: "guide", ToNS: "svc", ToKey: "api", Rel: "refines"})
+
+	// A new version relinked to the same target is still one logical link
+	s.Put(ctx, PutParams{NS: "svc", Key: "api", Content: "api v2"})
+	s.Link(ctx, LinkParams{FromNS: "svc", FromKey: "api", ToNS: "svc", ToKey: "db", Rel: "depends_on"})
+
+	all, err := s.ListLinks(ctx, ListLinksParams{})
+	if err != nil {
+		t.Fatalf("list links: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 links, got %+v", all)
+	}
+
+	deps, _ := s.ListLinks(ctx, ListLinksParams{Rel: "depends_on"})
+	if len(deps) != 1 || deps[0].FromKey != "api" || deps[0].ToKey != "db" {
+		t.Errorf("unexpected depends_on links: %+v", deps)
+	}
+
+	docs, _ := s.ListLinks(ctx, ListLinksParams{NS: "docs"})
+	if len(docs) != 1 || docs[0].FromNS != "docs" {
+		t.Errorf("unexpected docs links: %+v", docs)
+	}
+
+	s.Rm(ctx, RmParams{NS: "svc", Key: "db", AllVersions: true})
+	live, _ := s.ListLinks(ctx, ListLinksParams{})
+	if len(live) != 1 {
+		t.Errorf("expected links to deleted memories hidden, got %+v", live)
+	}
+	withDeleted, _ := s.ListLinks(ctx, ListLinksParams{IncludeDeleted: true})
+	if len(withDeleted) != 2 {
+		t.Errorf("expected deleted endpoints included, got %+v", withDeleted)
+	}
+}
+
+func TestHardDeleteWithLinks(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "svc", Key: "api", Content: "api"})
+	s.Put(ctx, PutParams{NS: "svc", Key: "db", Content: "db"})
+	s.Link(ctx, LinkParams{FromNS: "svc", FromKey: "api", ToNS: "svc", ToKey: "db", Rel: "depends_on"})
+
+	err := s.Rm(ctx, RmParams{NS: "svc", Key: "db", Hard: true})
+	if !errors.Is(err, ErrHasLinks) {
+		t.Fatalf("expected ErrHasLinks, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "svc/api --depends_on--> svc/db") {
+		t.Errorf("expected blocking link in error, got %q", err)
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "svc", Key: "db"}); err != nil {
+		t.Errorf("blocked delete should leave memory intact: %v", err)
+	}
+
+	if err := s.Rm(ctx, RmParams{NS: "svc", Key: "db", Hard: true, AllVersions: true, Cascade: true}); err != nil {
+		t.Fatalf("cascade delete: %v", err)
+	}
+	var memories, links int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memories WHERE key = 'db'`).Scan(&memories)
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links`).Scan(&links)
+	if memories != 0 || links != 0 {
+		t.Errorf("expected memory and links gone, got %d memories, %d links", memories, links)
+	}
+}
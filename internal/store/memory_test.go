@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	var s Store
+	ms, err := NewMemoryStore()
+	if err != nil {
+		t.Fatalf("new memory store: %v", err)
+	}
+	defer ms.Close()
+	s = ms
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "ephemeral alpha note"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "ephemeral beta note"})
+
+	got, err := s.Get(ctx, GetParams{NS: "test", Key: "a"})
+	if err != nil || got[0].Content != "ephemeral alpha note" {
+		t.Fatalf("get: %v %+v", err, got)
+	}
+
+	results, err := s.Search(ctx, SearchParams{Query: "ephemeral"})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("search: expected 2 results, got %d (err %v)", len(results), err)
+	}
+
+	cr, err := s.Context(ctx, ContextParams{Query: "ephemeral"})
+	if err != nil || len(cr.Memories) != 2 {
+		t.Fatalf("context: expected 2 memories, got %+v (err %v)", cr, err)
+	}
+
+	link, err := s.Link(ctx, LinkParams{FromNS: "test", FromKey: "a", ToNS: "test", ToKey: "b", Rel: "relates_to"})
+	if err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	links, _ := s.GetLinks(ctx, link.FromID)
+	if len(links) != 1 {
+		t.Fatalf("expected 1 link, got %d", len(links))
+	}
+
+	stats, err := ms.Stats(ctx, StatsParams{})
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.DBSizeBytes != 0 || stats.ActiveMemories != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestMemoryStoresAreIndependent(t *testing.T) {
+	ctx := context.Background()
+	a, _ := NewMemoryStore()
+	defer a.Close()
+	b, _ := NewMemoryStore()
+	defer b.Close()
+
+	a.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "only in a"})
+	if _, err := b.Get(ctx, GetParams{NS: "ns", Key: "k"}); err == nil {
+		t.Fatal("expected memory stores not to share data")
+	}
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+// DefaultMergeSeparator goes between the merged contents.
+const DefaultMergeSeparator = "\n\n---\n\n"
+
+// MemoryRef names a memory by namespace and key.
+type MemoryRef struct {
+	NS  string `json:"ns"`
+	Key string `json:"key"`
+}
+
+func (r MemoryRef) String() string { return r.NS + "/" + r.Key }
+
+// MergeParams holds parameters for combining memories into one.
+type MergeParams struct {
+	IntoNS    string
+	IntoKey   string
+	From      []MemoryRef
+	Separator string // "" means DefaultMergeSeparator
+	RmSources bool   // soft-delete every version of each source
+	Link      bool   // link the merged memory to each source with refines
+}
+
+// MergeResult reports the merged memory and what was done to the sources.
+type MergeResult struct {
+	Memory  *model.Memory `json:"memory"`
+	Sources []MemoryRef   `json:"sources"`
+	Linked  int           `json:"linked,omitempty"`
+	Removed int           `json:"removed,omitempty"`
+}
+
+// ErrChanged is returned by Merge and Split when a memory they read is
+// updated by someone else before they write.
+var ErrChanged = errors.New("memory changed during the operation; retry")
+
+// Merge stores a new version of IntoNS/IntoKey whose content is the target's
+// current content (if it exists) followed by each source's, joined by
+// Separator. Tags are the union of all of them; kind and meta come from the
+// target, or the first source if the target is new, and priority is the
+// highest among them. Everything happens in one transaction.
+func (s *SQLiteStore) Merge(ctx context.Context, p MergeParams) (*MergeResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	if p.IntoNS == "" || p.IntoKey == "" {
+		return nil, fmt.Errorf("merge target ns and key are required")
+	}
+	into := MemoryRef{NS: p.IntoNS, Key: p.IntoKey}
+	var sources []MemoryRef
+	seen := map[MemoryRef]bool{}
+	for _, r := range p.From {
+		if r == into {
+			return nil, fmt.Errorf("cannot merge %s into itself", r)
+		}
+		if !seen[r] {
+			seen[r] = true
+			sources = append(sources, r)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one source memory is required")
+	}
+	sep := p.Separator
+	if sep == "" {
+		sep = DefaultMergeSeparator
+	}
+
+	// Build and embed the merged memory before taking the write lock, then
+	// check inside the transaction that none of its parts changed meanwhile.
+	parts, err := s.mergeParts(ctx, s.db, into, sources)
+	if err != nil {
+		return nil, err
+	}
+	put := mergedPut(into, parts, sep)
+	chunks, err := s.prepareChunks(ctx, put)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := s.EmbedIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := s.mergeParts(ctx, tx, into, sources)
+	if err != nil {
+		return nil, err
+	}
+	if len(current) != len(parts) {
+		return nil, ErrChanged
+	}
+	for i := range parts {
+		if current[i].ID != parts[i].ID {
+			return nil, ErrChanged
+		}
+	}
+
+	mem, err := s.putTx(ctx, tx, put, chunks, &index)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MergeResult{Memory: mem, Sources: sources}
+	for _, r := range sources {
+		if p.Link {
+			lp := LinkParams{FromNS: into.NS, FromKey: into.Key, ToNS: r.NS, ToKey: r.Key, Rel: "refines"}
+			if _, err := linkTx(ctx, tx, lp); err != nil {
+				return nil, err
+			}
+			if err := s.recordLinkEvent(ctx, tx, lp); err != nil {
+				return nil, err
+			}
+			result.Linked++
+		}
+		if p.RmSources {
+			if err := s.rmTx(ctx, tx, RmParams{NS: r.NS, Key: r.Key, AllVersions: true}); err != nil {
+				return nil, err
+			}
+			result.Removed++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeParts reads the latest versions a merge combines: the target, if it
+// exists, then each source.
+func (s *SQLiteStore) mergeParts(ctx context.Context, q rowQuerier, into MemoryRef, sources []MemoryRef) ([]model.Memory, error) {
+	var parts []model.Memory
+	if target, err := s.latestMemory(ctx, q, into.NS, into.Key); err == nil {
+		parts = append(parts, *target)
+	}
+	for _, r := range sources {
+		m, err := s.latestMemory(ctx, q, r.NS, r.Key)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, *m)
+	}
+	return parts, nil
+}
+
+// mergedPut builds the put that stores parts as one memory.
+func mergedPut(into MemoryRef, parts []model.Memory, sep string) PutParams {
+	put := PutParams{NS: into.NS, Key: into.Key, Kind: parts[0].Kind, Meta: parts[0].Meta, Priority: parts[0].Priority}
+	var contents []string
+	tagSeen := map[string]bool{}
+	for _, m := range parts {
+		contents = append(contents, m.Content)
+		for _, t := range m.Tags {
+			if !tagSeen[t] {
+				tagSeen[t] = true
+				put.Tags = append(put.Tags, t)
+			}
+		}
+		if priorityScore(m.Priority) > priorityScore(put.Priority) {
+			put.Priority = m.Priority
+		}
+	}
+	put.Content = strings.Join(contents, sep)
+	return put
+}
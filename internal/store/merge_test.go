@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "target", Content: "existing", Tags: []string{"a"}})
+	s.Put(ctx, PutParams{NS: "test", Key: "n1", Content: "draft"})
+	s.Put(ctx, PutParams{NS: "test", Key: "n1", Content: "first", Tags: []string{"a", "b"}})
+	s.Put(ctx, PutParams{NS: "test", Key: "n2", Content: "second", Tags: []string{"c"}, Priority: "critical"})
+
+	result, err := s.Merge(ctx, MergeParams{
+		IntoNS:    "test",
+		IntoKey:   "target",
+		From:      []MemoryRef{{"test", "n1"}, {"test", "n2"}, {"test", "n1"}},
+		Separator: "\n--\n",
+		RmSources: true,
+		Link:      true,
+	})
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	m := result.Memory
+	if m.Version != 2 || m.Content != "existing\n--\nfirst\n--\nsecond" {
+		t.Errorf("unexpected merged memory: v%d %q", m.Version, m.Content)
+	}
+	if strings.Join(m.Tags, ",") != "a,b,c" || m.Priority != "critical" {
+		t.Errorf("expected tag union and highest priority, got %v %s", m.Tags, m.Priority)
+	}
+	if len(result.Sources) != 2 || result.Linked != 2 || result.Removed != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	for _, key := range []string{"n1", "n2"} {
+		if live := liveVersions(s, "test", key); live != 0 {
+			t.Errorf("expected every version of source %s soft-deleted, %d live", key, live)
+		}
+	}
+	var links int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links WHERE rel = 'refines' AND from_id = ?`, m.ID).Scan(&links)
+	if links != 2 {
+		t.Errorf("expected 2 refines links from the merged memory, got %d", links)
+	}
+
+	// A missing source aborts the whole merge
+	if _, err := s.Merge(ctx, MergeParams{IntoNS: "test", IntoKey: "new", From: []MemoryRef{{"test", "target"}, {"test", "nope"}}}); err == nil {
+		t.Error("expected an error for a missing source")
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "test", Key: "new"}); err == nil {
+		t.Error("failed merge should not store anything")
+	}
+	if _, err := s.Merge(ctx, MergeParams{IntoNS: "test", IntoKey: "target", From: []MemoryRef{{"test", "target"}}}); err == nil {
+		t.Error("expected an error merging a memory into itself")
+	}
+}
+
+// liveVersions counts the versions of ns/key that are not soft-deleted.
+func liveVersions(s *SQLiteStore, ns, key string) int {
+	var n int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL`, ns, key).Scan(&n)
+	return n
+}
+
+func TestSplit(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "guide", Content: "Old guide."})
+	doc := "Preamble.\n\n## Setup\n\nInstall it.\n\n## Usage\n\nRun it.\n\n## Usage\n\nAgain."
+	s.Put(ctx, PutParams{NS: "test", Key: "guide", Content: doc, Tags: []string{"doc"}, Priority: "high"})
+
+	result, err := s.Split(ctx, SplitParams{NS: "test", Key: "guide", RmOriginal: true})
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	want := []string{"guide#intro", "guide#setup", "guide#usage", "guide#usage-2"}
+	if strings.Join(result.Keys, ",") != strings.Join(want, ",") || !result.Removed {
+		t.Fatalf("expected keys %v and the original removed, got %+v", want, result)
+	}
+
+	got, err := s.Get(ctx, GetParams{NS: "test", Key: "guide#setup"})
+	if err != nil {
+		t.Fatalf("get section: %v", err)
+	}
+	if got[0].Content != "## Setup\n\nInstall it." || got[0].Priority != "high" || len(got[0].Tags) != 1 {
+		t.Errorf("unexpected section memory: %+v", got[0])
+	}
+	if live := liveVersions(s, "test", "guide"); live != 0 {
+		t.Errorf("expected every version of the original soft-deleted, %d live", live)
+	}
+	var links int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links WHERE rel = 'refines'`).Scan(&links)
+	if links != 4 {
+		t.Errorf("expected 4 refines links, got %d", links)
+	}
+
+	if _, err := s.Split(ctx, SplitParams{NS: "test", Key: "guide#setup"}); err == nil {
+		t.Error("expected an error splitting a single section")
+	}
+	if _, err := s.Split(ctx, SplitParams{NS: "test", Key: "guide#intro", By: "paragraph"}); err == nil {
+		t.Error("expected an invalid strategy error")
+	}
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// searchBuckets are the upper bounds, in seconds, of the search latency
+// histogram.
+var searchBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// metrics counts operations on a store for monitoring. The zero value is
+// ready to use.
+type metrics struct {
+	mu           sync.Mutex
+	ops          map[string]uint64
+	searchCounts [11]uint64 // per bucket of searchBuckets, then +Inf
+	searchSum    float64
+	searchTotal  uint64
+}
+
+func (m *metrics) count(op string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ops == nil {
+		m.ops = map[string]uint64{}
+	}
+	m.ops[op]++
+}
+
+// observeSearch records a search that started at start.
+func (m *metrics) observeSearch(start time.Time) {
+	secs := time.Since(start).Seconds()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := sort.SearchFloat64s(searchBuckets, secs)
+	m.searchCounts[i]++
+	m.searchSum += secs
+	m.searchTotal++
+}
+
+// Ping checks that the database answers a query.
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	var one int
+	return s.db.QueryRowContext(ctx, `SELECT 1`).Scan(&one)
+}
+
+// WriteMetrics writes the store's metrics in the Prometheus text exposition
+// format: operation counters and search latency since the store was opened,
+// and gauges of live memories and chunks read from the database.
+func (s *SQLiteStore) WriteMetrics(ctx context.Context, w io.Writer) error {
+	var memories, chunks int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM memories WHERE deleted_at IS NULL`).Scan(&memories); err != nil {
+		return err
+	}
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM chunks`).Scan(&chunks); err != nil {
+		return err
+	}
+
+	m := &s.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := &promWriter{w: w}
+	p.header("agent_memory_ops_total", "counter", "Store operations since the store was opened.")
+	for _, op := range []string{"put", "get", "search"} {
+		p.printf("agent_memory_ops_total{op=%q} %d\n", op, m.ops[op])
+	}
+
+	p.header("agent_memory_search_duration_seconds", "histogram", "Search latency.")
+	var cumulative uint64
+	for i, le := range searchBuckets {
+		cumulative += m.searchCounts[i]
+		p.printf("agent_memory_search_duration_seconds_bucket{le=%q} %d\n",
+			strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+	}
+	cumulative += m.searchCounts[len(searchBuckets)]
+	p.printf("agent_memory_search_duration_seconds_bucket{le=\"+Inf\"} %d\n", cumulative)
+	p.printf("agent_memory_search_duration_seconds_sum %g\n", m.searchSum)
+	p.printf("agent_memory_search_duration_seconds_count %d\n", m.searchTotal)
+
+	p.header("agent_memory_memories", "gauge", "Live (not soft-deleted) memory versions.")
+	p.printf("agent_memory_memories %d\n", memories)
+	p.header("agent_memory_chunks", "gauge", "Indexed chunks.")
+	p.printf("agent_memory_chunks %d\n", chunks)
+	return p.err
+}
+
+// promWriter writes exposition lines, keeping the first error.
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) header(name, kind, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *promWriter) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
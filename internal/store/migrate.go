@@ -0,0 +1,292 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// migration is one numbered step of the schema. up and down each run in
+// their own transaction together with the schema_version bookkeeping, so a
+// database is always at exactly one version.
+//
+// Databases created before versioning have the tables but no
+// schema_version rows, so up must tolerate objects that already exist:
+// CREATE ... IF NOT EXISTS, and addColumn for columns.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+	down    func(tx *sql.Tx) error
+}
+
+// migrations is the schema history, oldest first. Append new steps; never
+// edit or renumber one that has shipped.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "base tables",
+		up: execAll(`
+			CREATE TABLE IF NOT EXISTS memories (
+				id          TEXT PRIMARY KEY,
+				ns          TEXT NOT NULL,
+				key         TEXT NOT NULL,
+				content     TEXT NOT NULL,
+				kind        TEXT NOT NULL DEFAULT 'semantic',
+				tags        TEXT,
+				version     INTEGER NOT NULL DEFAULT 1,
+				supersedes  TEXT,
+				created_at  TEXT NOT NULL,
+				deleted_at  TEXT,
+				priority    TEXT NOT NULL DEFAULT 'normal',
+				access_count INTEGER NOT NULL DEFAULT 0,
+				last_accessed_at TEXT,
+				meta        TEXT
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_memories_ns_key ON memories(ns, key)`,
+			`CREATE INDEX IF NOT EXISTS idx_memories_ns_kind ON memories(ns, kind)`,
+			`CREATE INDEX IF NOT EXISTS idx_memories_created ON memories(created_at DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_memories_deleted ON memories(deleted_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_memories_priority ON memories(ns, priority)`,
+			`CREATE TABLE IF NOT EXISTS chunks (
+				id          TEXT PRIMARY KEY,
+				memory_id   TEXT NOT NULL REFERENCES memories(id),
+				seq         INTEGER NOT NULL,
+				text        TEXT NOT NULL,
+				start_line  INTEGER,
+				end_line    INTEGER
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_chunks_memory ON chunks(memory_id)`,
+			`CREATE TABLE IF NOT EXISTS store_meta (
+				key   TEXT PRIMARY KEY,
+				value TEXT NOT NULL
+			)`),
+		down: execAll(
+			`DROP TABLE IF EXISTS chunks_fts`,
+			`DROP TABLE IF EXISTS store_meta`,
+			`DROP TABLE IF EXISTS chunks`,
+			`DROP TABLE IF EXISTS memories`),
+	},
+	{
+		version: 2,
+		name:    "memory expiry",
+		up: func(tx *sql.Tx) error {
+			if err := addColumn(tx, "memories", "expires_at", "TEXT"); err != nil {
+				return err
+			}
+			return execAll(`CREATE INDEX IF NOT EXISTS idx_memories_expires ON memories(expires_at)`)(tx)
+		},
+		down: execAll(
+			`DROP INDEX IF EXISTS idx_memories_expires`,
+			`ALTER TABLE memories DROP COLUMN expires_at`),
+	},
+	{
+		version: 3,
+		name:    "chunk embeddings",
+		up: func(tx *sql.Tx) error {
+			return addColumn(tx, "chunks", "embedding", "TEXT")
+		},
+		down: execAll(`ALTER TABLE chunks DROP COLUMN embedding`),
+	},
+	{
+		version: 4,
+		name:    "memory links",
+		up: execAll(`
+			CREATE TABLE IF NOT EXISTS memory_links (
+				from_id    TEXT NOT NULL REFERENCES memories(id),
+				to_id      TEXT NOT NULL REFERENCES memories(id),
+				rel        TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				PRIMARY KEY (from_id, to_id, rel)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_links_to ON memory_links(to_id)`),
+		down: execAll(`DROP TABLE IF EXISTS memory_links`),
+	},
+	{
+		version: 5,
+		name:    "content encoding",
+		up: func(tx *sql.Tx) error {
+			return addColumn(tx, "memories", "content_encoding", "TEXT")
+		},
+		down: execAll(`ALTER TABLE memories DROP COLUMN content_encoding`),
+	},
+	{
+		version: 6,
+		name:    "namespace schemas",
+		up: execAll(`
+			CREATE TABLE IF NOT EXISTS ns_schemas (
+				ns         TEXT PRIMARY KEY,
+				schema     TEXT NOT NULL,
+				updated_at TEXT NOT NULL
+			)`),
+		down: execAll(`DROP TABLE IF EXISTS ns_schemas`),
+	},
+	{
+		version: 7,
+		name:    "event log",
+		up: func(tx *sql.Tx) error {
+			if err := addColumn(tx, "memories", "actor", "TEXT"); err != nil {
+				return err
+			}
+			return execAll(`
+				CREATE TABLE IF NOT EXISTS events (
+					id      INTEGER PRIMARY KEY AUTOINCREMENT,
+					at      TEXT NOT NULL,
+					op      TEXT NOT NULL,
+					ns      TEXT NOT NULL,
+					key     TEXT,
+					version INTEGER,
+					actor   TEXT,
+					detail  TEXT
+				)`,
+				`CREATE INDEX IF NOT EXISTS idx_events_ns_at ON events(ns, at)`,
+				`CREATE TRIGGER IF NOT EXISTS events_no_update BEFORE UPDATE ON events BEGIN
+					SELECT RAISE(ABORT, 'events are append-only');
+				END`,
+				`CREATE TRIGGER IF NOT EXISTS events_no_delete BEFORE DELETE ON events BEGIN
+					SELECT RAISE(ABORT, 'events are append-only');
+				END`)(tx)
+		},
+		down: execAll(
+			`DROP TABLE IF EXISTS events`,
+			`ALTER TABLE memories DROP COLUMN actor`),
+	},
+}
+
+// LatestSchemaVersion is the schema version this build migrates to.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// SchemaVersion returns the version the database is at, or 0 if no
+// migration has been recorded.
+func (s *SQLiteStore) SchemaVersion(ctx context.Context) (int, error) {
+	return schemaVersion(ctx, s.db)
+}
+
+func schemaVersion(ctx context.Context, q rowQuerier) (int, error) {
+	var exists int
+	if err := q.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_version'`).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if exists == 0 {
+		return 0, nil
+	}
+	var v int
+	err := q.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&v)
+	return v, err
+}
+
+// MigrateTo moves the schema forward or backward to target, one migration
+// per transaction. Migrating down drops the tables and columns later
+// migrations added, with their data.
+func (s *SQLiteStore) MigrateTo(ctx context.Context, target int) error {
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	if target < 0 || target > LatestSchemaVersion() {
+		return fmt.Errorf("unknown schema version %d (latest is %d)", target, LatestSchemaVersion())
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TEXT NOT NULL
+		)`); err != nil {
+		return fmt.Errorf("create schema_version: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version > target {
+			break
+		}
+		if err := s.applyMigration(ctx, m, true); err != nil {
+			return err
+		}
+	}
+	for i := len(migrations) - 1; i >= 0 && migrations[i].version > target; i-- {
+		if err := s.applyMigration(ctx, migrations[i], false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs m up (or down) unless the database is already past
+// (or before) it. The version is re-read inside the transaction so two
+// processes opening a new database at once apply each step only once.
+func (s *SQLiteStore) applyMigration(ctx context.Context, m migration, up bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if up {
+		if current >= m.version {
+			return nil
+		}
+		if err := m.up(tx); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`,
+			m.version, m.name, time.Now().UTC().Format(time.RFC3339)); err != nil {
+			return err
+		}
+	} else {
+		if current < m.version {
+			return nil
+		}
+		if err := m.down(tx); err != nil {
+			return fmt.Errorf("revert migration %d (%s): %w", m.version, m.name, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM schema_version WHERE version = ?`, m.version); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// execAll returns a migration step running each statement in order.
+func execAll(stmts ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// addColumn adds a column unless the table already has it, as databases
+// upgraded before schema versioning may.
+func addColumn(tx *sql.Tx, table, column, decl string) error {
+	has, err := hasColumn(tx, table, column)
+	if err != nil || has {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl))
+	if err != nil && strings.Contains(err.Error(), "duplicate column name") {
+		// Added since the check, e.g. by an older build opening the file.
+		return nil
+	}
+	return err
+}
+
+// hasColumn reports whether table has column. table must be a trusted
+// identifier, never user input.
+func hasColumn(q rowQuerier, table, column string) (bool, error) {
+	var n int
+	err := q.QueryRowContext(context.Background(),
+		`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&n)
+	return n > 0, err
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateOldSchema(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	// A database from before versioning: no schema_version, expires_at added
+	// by the old ad-hoc ALTER, and none of the later columns or tables.
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE memories (
+			id TEXT PRIMARY KEY, ns TEXT NOT NULL, key TEXT NOT NULL, content TEXT NOT NULL,
+			kind TEXT NOT NULL DEFAULT 'semantic', tags TEXT, version INTEGER NOT NULL DEFAULT 1,
+			supersedes TEXT, created_at TEXT NOT NULL, deleted_at TEXT,
+			priority TEXT NOT NULL DEFAULT 'normal', access_count INTEGER NOT NULL DEFAULT 0,
+			last_accessed_at TEXT, meta TEXT)`,
+		`ALTER TABLE memories ADD COLUMN expires_at TEXT`,
+		`CREATE TABLE chunks (
+			id TEXT PRIMARY KEY, memory_id TEXT NOT NULL REFERENCES memories(id), seq INTEGER NOT NULL,
+			text TEXT NOT NULL, start_line INTEGER, end_line INTEGER)`,
+		`INSERT INTO memories (id, ns, key, content, created_at) VALUES ('01OLD', 'ns', 'a', 'legacy', '2024-01-01T00:00:00Z')`,
+		`INSERT INTO chunks (id, memory_id, seq, text) VALUES ('01OLDC', '01OLD', 0, 'legacy')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup %q: %v", stmt, err)
+		}
+	}
+	db.Close()
+
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("open old database: %v", err)
+	}
+	defer s.Close()
+
+	v, err := s.SchemaVersion(ctx)
+	if err != nil || v != LatestSchemaVersion() {
+		t.Fatalf("expected schema version %d, got %d (%v)", LatestSchemaVersion(), v, err)
+	}
+	for _, col := range [][2]string{{"memories", "content_encoding"}, {"memories", "actor"}, {"chunks", "embedding"}} {
+		if ok, _ := hasColumn(s.db, col[0], col[1]); !ok {
+			t.Errorf("expected %s.%s after migrating", col[0], col[1])
+		}
+	}
+
+	got, err := s.Get(ctx, GetParams{NS: "ns", Key: "a"})
+	if err != nil || got[0].Content != "legacy" {
+		t.Fatalf("expected the legacy memory to survive, got %+v (%v)", got, err)
+	}
+	results, err := s.Search(ctx, SearchParams{NS: "ns", Query: "legacy"})
+	if err != nil || len(results) != 1 {
+		t.Errorf("expected the legacy chunk to be indexed, got %d results (%v)", len(results), err)
+	}
+	if _, err := s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "new", Actor: "me"}); err != nil {
+		t.Errorf("put after migrating: %v", err)
+	}
+}
+
+func TestMigrateDownAndUp(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "kept"})
+
+	if err := s.MigrateTo(ctx, 4); err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	if v, _ := s.SchemaVersion(ctx); v != 4 {
+		t.Errorf("expected version 4, got %d", v)
+	}
+	if ok, _ := hasColumn(s.db, "memories", "actor"); ok {
+		t.Error("expected memories.actor to be dropped")
+	}
+
+	if err := s.MigrateTo(ctx, LatestSchemaVersion()); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "ns", Key: "a"}); err != nil {
+		t.Errorf("expected data in surviving columns to be kept: %v", err)
+	}
+	if err := s.MigrateTo(ctx, LatestSchemaVersion()+1); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+}
+
+func TestMigrateReportsFTSFailure(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "broken.db")
+
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "indexed"})
+	// Replace the FTS index with an unrelated table of the same name. The
+	// recorded tokenizer still matches, so only the backfill notices.
+	if _, err := s.db.Exec(`DROP TABLE chunks_fts`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(`CREATE TABLE chunks_fts (body TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	_, err = NewSQLiteStore(path)
+	if err == nil || !strings.Contains(err.Error(), "fts backfill") {
+		t.Fatalf("expected migrate to report the broken FTS index, got %v", err)
+	}
+}
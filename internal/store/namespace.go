@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NSDeleteResult reports what DeleteNamespace removed.
+type NSDeleteResult struct {
+	NS       string `json:"ns"`
+	Hard     bool   `json:"hard"`
+	Keys     int    `json:"keys"`
+	Versions int    `json:"versions"`
+	Chunks   int    `json:"chunks,omitempty"` // hard deletes only
+	Links    int    `json:"links,omitempty"`  // hard deletes only
+}
+
+// DeleteNamespace removes every memory in ns in one transaction. A soft
+// delete marks every live version deleted (restorable per key with
+// undelete). A hard delete permanently removes every version, including
+// soft-deleted ones, with their chunks, any links touching them, and the
+// namespace's meta schema.
+func (s *SQLiteStore) DeleteNamespace(ctx context.Context, ns string, hard bool) (*NSDeleteResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	if ns == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &NSDeleteResult{NS: ns, Hard: hard}
+	live := ` AND deleted_at IS NULL`
+	if hard {
+		live = ""
+	}
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(DISTINCT key), COUNT(*) FROM memories WHERE ns = ?`+live, ns).
+		Scan(&result.Keys, &result.Versions); err != nil {
+		return nil, err
+	}
+	if result.Versions == 0 {
+		return nil, fmt.Errorf("namespace not found: %s", ns)
+	}
+
+	detail := "namespace"
+	if hard {
+		detail = "namespace, hard"
+		ids := `SELECT id FROM memories WHERE ns = ?`
+		res, err := tx.ExecContext(ctx,
+			`DELETE FROM memory_links WHERE from_id IN (`+ids+`) OR to_id IN (`+ids+`)`, ns, ns)
+		if err != nil {
+			return nil, err
+		}
+		result.Links = rowsAffected(res)
+		if res, err = tx.ExecContext(ctx, `DELETE FROM chunks WHERE memory_id IN (`+ids+`)`, ns); err != nil {
+			return nil, err
+		}
+		result.Chunks = rowsAffected(res)
+		if _, err := tx.ExecContext(ctx, `DELETE FROM memories WHERE ns = ?`, ns); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM ns_schemas WHERE ns = ?`, ns); err != nil {
+			return nil, err
+		}
+	} else if _, err := tx.ExecContext(ctx,
+		`UPDATE memories SET deleted_at = ? WHERE ns = ? AND deleted_at IS NULL`,
+		time.Now().UTC().Format(time.RFC3339), ns); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordEvent(ctx, tx, EventRm, ns, "", 0, detail); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func rowsAffected(res sql.Result) int {
+	n, _ := res.RowsAffected()
+	return int(n)
+}
+
+// NSRenameResult reports what RenameNamespace moved.
+type NSRenameResult struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Keys     int      `json:"keys"`
+	Versions int      `json:"versions"`
+	Merged   []string `json:"merged,omitempty"` // keys that already existed in To
+}
+
+// RenameNamespace moves every version of every memory in from to to, in one
+// transaction. Links follow automatically since they reference memory ids.
+// If to already has some of the same keys, it fails unless force is set, in
+// which case the moved versions are renumbered to follow the existing ones,
+// so the moved memory becomes the latest version of the key. from's meta
+// schema moves too unless to already has one.
+func (s *SQLiteStore) RenameNamespace(ctx context.Context, from, to string, force bool) (*NSRenameResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	if from == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+	if from == to {
+		return nil, fmt.Errorf("source and target namespace are the same")
+	}
+	if err := s.checkNamespace(to); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &NSRenameResult{From: from, To: to}
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(DISTINCT key), COUNT(*) FROM memories WHERE ns = ?`, from).
+		Scan(&result.Keys, &result.Versions); err != nil {
+		return nil, err
+	}
+	if result.Versions == 0 {
+		return nil, fmt.Errorf("namespace not found: %s", from)
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT DISTINCT key FROM memories WHERE ns = ? AND key IN (SELECT key FROM memories WHERE ns = ?) ORDER BY key`,
+		from, to)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		result.Merged = append(result.Merged, key)
+	}
+	rows.Close()
+	if len(result.Merged) > 0 && !force {
+		return nil, fmt.Errorf("namespace %s already has %d of the same keys (%s); use --force to append them as new versions",
+			to, len(result.Merged), strings.Join(result.Merged, ", "))
+	}
+
+	for _, key := range result.Merged {
+		if err := renumberAfter(ctx, tx, from, to, key); err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", from, key, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE memories SET ns = ? WHERE ns = ?`, to, from); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE OR IGNORE ns_schemas SET ns = ? WHERE ns = ?`, to, from); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM ns_schemas WHERE ns = ?`, from); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordEvent(ctx, tx, EventUpdate, from, "", 0, "namespace renamed to "+to); err != nil {
+		return nil, err
+	}
+	if err := s.recordEvent(ctx, tx, EventUpdate, to, "", 0, "namespace renamed from "+from); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// renumberAfter shifts the versions of from/key past the last version of
+// to/key, chaining the first moved version to the existing latest.
+func renumberAfter(ctx context.Context, tx *sql.Tx, from, to, key string) error {
+	var lastID string
+	var last int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT id, version FROM memories WHERE ns = ? AND key = ? ORDER BY version DESC LIMIT 1`,
+		to, key).Scan(&lastID, &last); err != nil {
+		return err
+	}
+	var first int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT MIN(version) FROM memories WHERE ns = ? AND key = ?`, from, key).Scan(&first); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE memories SET version = version - ? + ? WHERE ns = ? AND key = ?`,
+		first-1, last, from, key); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx,
+		`UPDATE memories SET supersedes = ? WHERE ns = ? AND key = ? AND version = ?`,
+		lastID, from, key, last+1)
+	return err
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDeleteNamespace(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "old", Key: "a", Content: "a1"})
+	s.Put(ctx, PutParams{NS: "old", Key: "a", Content: "a2"})
+	s.Put(ctx, PutParams{NS: "old", Key: "b", Content: "b1"})
+	s.Put(ctx, PutParams{NS: "keep", Key: "c", Content: "c1"})
+	s.Link(ctx, LinkParams{FromNS: "keep", FromKey: "c", ToNS: "old", ToKey: "b", Rel: "relates_to"})
+	s.SetSchema(ctx, "old", []byte(`{"type":"object"}`))
+
+	result, err := s.DeleteNamespace(ctx, "old", false)
+	if err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if result.Keys != 2 || result.Versions != 3 || result.Links != 0 {
+		t.Errorf("unexpected soft delete counts: %+v", result)
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "old", Key: "a"}); err == nil {
+		t.Error("expected old/a to be soft-deleted")
+	}
+	if _, err := s.DeleteNamespace(ctx, "old", false); err == nil {
+		t.Error("expected an error soft-deleting an already deleted namespace")
+	}
+
+	result, err = s.DeleteNamespace(ctx, "old", true)
+	if err != nil {
+		t.Fatalf("hard delete: %v", err)
+	}
+	if result.Versions != 3 || result.Chunks != 3 || result.Links != 1 {
+		t.Errorf("unexpected hard delete counts: %+v", result)
+	}
+	var n int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memories WHERE ns = 'old'`).Scan(&n)
+	if n != 0 {
+		t.Errorf("expected no rows left in old, got %d", n)
+	}
+	if sch, _ := s.GetSchema(ctx, "old"); sch != nil {
+		t.Error("expected the namespace schema to be removed")
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "keep", Key: "c"}); err != nil {
+		t.Errorf("other namespaces should be untouched: %v", err)
+	}
+
+	events, _ := s.Events(ctx, EventsParams{NS: "old", Op: EventRm})
+	if len(events) != 2 || events[1].Detail != "namespace, hard" {
+		t.Errorf("expected two namespace rm events, got %+v", events)
+	}
+}
+
+func TestRenameNamespace(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "old", Key: "a", Content: "a1"})
+	s.Put(ctx, PutParams{NS: "old", Key: "a", Content: "a2"})
+	s.Put(ctx, PutParams{NS: "old", Key: "b", Content: "b1"})
+	s.Put(ctx, PutParams{NS: "new", Key: "b", Content: "existing b"})
+	s.SetSchema(ctx, "old", []byte(`{"type":"object"}`))
+
+	if _, err := s.RenameNamespace(ctx, "old", "new", false); err == nil || !strings.Contains(err.Error(), "b") {
+		t.Fatalf("expected a conflict error naming b, got %v", err)
+	}
+	if _, err := s.RenameNamespace(ctx, "missing", "new", false); err == nil {
+		t.Error("expected an error renaming a missing namespace")
+	}
+
+	result, err := s.RenameNamespace(ctx, "old", "new", true)
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if result.Keys != 2 || result.Versions != 3 || len(result.Merged) != 1 {
+		t.Errorf("unexpected rename result: %+v", result)
+	}
+
+	a, err := s.Get(ctx, GetParams{NS: "new", Key: "a"})
+	if err != nil || a[0].Content != "a2" || a[0].Version != 2 {
+		t.Errorf("expected new/a at version 2, got %+v (%v)", a, err)
+	}
+	b, err := s.Get(ctx, GetParams{NS: "new", Key: "b"})
+	if err != nil || b[0].Content != "b1" || b[0].Version != 2 {
+		t.Errorf("expected moved b to follow the existing version, got %+v (%v)", b, err)
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "old", Key: "a"}); err == nil {
+		t.Error("expected nothing left in old")
+	}
+	if sch, _ := s.GetSchema(ctx, "new"); sch == nil {
+		t.Error("expected the schema to move with the namespace")
+	}
+}
+
+func TestNamespaceDetail(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "proj", Key: "a", Content: "one", Tags: []string{"go", "db"}})
+	s.Put(ctx, PutParams{NS: "proj", Key: "a", Content: "two", Tags: []string{"go"}})
+	s.Put(ctx, PutParams{NS: "proj", Key: "b", Content: "three", Tags: []string{"go"}})
+	s.Put(ctx, PutParams{NS: "proj", Key: "c", Content: "gone"})
+	s.Rm(ctx, RmParams{NS: "proj", Key: "c"})
+	s.Put(ctx, PutParams{NS: "other", Key: "x", Content: "noise", Tags: []string{"db"}})
+
+	d, err := s.NamespaceDetail(ctx, "proj")
+	if err != nil {
+		t.Fatalf("detail: %v", err)
+	}
+	if d.Keys != 2 || d.Memories != 3 || d.Versions != 4 || d.Deleted != 1 {
+		t.Errorf("unexpected counts: %+v", d)
+	}
+	if d.Chunks != 4 || d.ContentBytes != int64(len("one")+len("two")+len("three")) {
+		t.Errorf("unexpected chunks/bytes: %+v", d)
+	}
+	if d.Oldest == nil || d.Newest == nil || d.Newest.Before(*d.Oldest) {
+		t.Errorf("unexpected oldest/newest: %v %v", d.Oldest, d.Newest)
+	}
+	// Only the latest version of each key counts, so a's old "db" tag is gone.
+	if len(d.TopTags) != 1 || d.TopTags[0] != (TagCount{Tag: "go", Count: 2}) {
+		t.Errorf("unexpected top tags: %+v", d.TopTags)
+	}
+
+	if _, err := s.NamespaceDetail(ctx, "missing"); err == nil {
+		t.Error("expected an error for a missing namespace")
+	}
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/chunker"
+	"github.com/rcliao/agent-memory/internal/embedding"
+)
+
+// Option configures a store opened with NewSQLiteStore, NewReadOnlyStore, or
+// NewMemoryStore. Settings not given by an option fall back to the
+// environment, as they do for the CLI.
+type Option func(*options)
+
+type options struct {
+	embedder    embedding.Embedder
+	embedderSet bool
+	readOnly    bool
+	busyTimeout *time.Duration
+	chunkOpts   *chunker.Options
+}
+
+// WithEmbedder sets the embedder used for puts and vector search instead of
+// the one configured by $AGENT_MEMORY_EMBED_PROVIDER. nil disables embedding.
+func WithEmbedder(e embedding.Embedder) Option {
+	return func(o *options) { o.embedder, o.embedderSet = e, true }
+}
+
+// WithReadOnly opens the database without write access, like NewReadOnlyStore.
+func WithReadOnly() Option {
+	return func(o *options) { o.readOnly = true }
+}
+
+// WithBusyTimeout sets how long a connection waits on a locked database
+// instead of $AGENT_MEMORY_BUSY_TIMEOUT.
+func WithBusyTimeout(d time.Duration) Option {
+	return func(o *options) { o.busyTimeout = &d }
+}
+
+// WithChunkerOptions sets the default chunking options for puts instead of
+// the package defaults and $AGENT_MEMORY_CHUNK_OVERLAP. Per-put sizes in
+// PutParams still override them.
+func WithChunkerOptions(c chunker.Options) Option {
+	return func(o *options) { o.chunkOpts = &c }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// timeout returns the busy timeout in milliseconds.
+func (o *options) timeout() (int, error) {
+	if o.busyTimeout != nil {
+		return int(*o.busyTimeout / time.Millisecond), nil
+	}
+	return busyTimeout()
+}
+
+// embedderOrEnv returns the configured embedder, or the one from the
+// environment.
+func (o *options) embedderOrEnv() embedding.Embedder {
+	if o.embedderSet {
+		return o.embedder
+	}
+	return embedding.NewFromEnv()
+}
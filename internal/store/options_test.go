@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/chunker"
+)
+
+func TestOptions(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "opts.db")
+
+	s, err := NewSQLiteStore(path,
+		WithEmbedder(&fakeEmbedder{model: "small", dims: 3}),
+		WithBusyTimeout(250*time.Millisecond),
+		WithChunkerOptions(chunker.Options{TargetSize: 20, MinSize: 5, MaxSize: 30}),
+	)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	var timeout int
+	s.db.QueryRow(`PRAGMA busy_timeout`).Scan(&timeout)
+	if timeout != 250 {
+		t.Errorf("expected busy timeout 250ms, got %d", timeout)
+	}
+
+	mem, err := s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: strings.Repeat("several words here. ", 10)})
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	chunks, err := s.GetChunks(ctx, mem.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) < 5 {
+		t.Errorf("expected the small chunk size to give many chunks, got %d", len(chunks))
+	}
+	if !chunks[0].HasEmbedding {
+		t.Error("expected chunks to be embedded with the given embedder")
+	}
+	s.Close()
+
+	ro, err := NewSQLiteStore(path, WithReadOnly())
+	if err != nil {
+		t.Fatalf("open read-only: %v", err)
+	}
+	defer ro.Close()
+	if _, err := ro.Put(ctx, PutParams{NS: "ns", Key: "b", Content: "x"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
+
+func TestWithEmbedderNilDisables(t *testing.T) {
+	t.Setenv("AGENT_MEMORY_EMBED_PROVIDER", "ollama")
+
+	s, err := NewMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.embedder == nil {
+		t.Fatal("expected the environment's embedder without an option")
+	}
+
+	off, err := NewMemoryStore(WithEmbedder(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer off.Close()
+	if off.embedder != nil {
+		t.Error("expected WithEmbedder(nil) to disable embedding despite the environment")
+	}
+}
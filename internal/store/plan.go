@@ -0,0 +1,69 @@
+package store
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ChunkPlan describes one chunk a Put would create.
+type ChunkPlan struct {
+	Seq       int `json:"seq"`
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+	Length    int `json:"length"` // chars
+}
+
+// PutPlan reports how a Put would chunk and embed its content.
+type PutPlan struct {
+	NS         string      `json:"ns"`
+	Key        string      `json:"key"`
+	Format     string      `json:"format"`
+	Bytes      int         `json:"bytes"`
+	ChunkCount int         `json:"chunk_count"`
+	Embeddings int         `json:"embeddings"` // embedding calls the Put would make
+	EmbedModel string      `json:"embed_model,omitempty"`
+	TargetSize int         `json:"target_size"`
+	MaxSize    int         `json:"max_size"`
+	Overlap    int         `json:"overlap,omitempty"`
+	Chunks     []ChunkPlan `json:"chunks"`
+}
+
+// PlanPut chunks p's content the way Put would and reports the result
+// without writing anything or calling the embedding provider.
+func (s *SQLiteStore) PlanPut(p PutParams) (*PutPlan, error) {
+	if s.maxContent > 0 && len(p.Content) > s.maxContent {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the %d byte limit (set AGENT_MEMORY_MAX_CONTENT to raise it)",
+			ErrContentTooLarge, len(p.Content), s.maxContent)
+	}
+	format, chunkr, err := chunkerFor(p)
+	if err != nil {
+		return nil, err
+	}
+	opts := s.chunkOptions(p)
+	chunks := chunkr.Chunk(p.Content, opts)
+
+	plan := &PutPlan{
+		NS:         p.NS,
+		Key:        p.Key,
+		Format:     format,
+		Bytes:      len(p.Content),
+		ChunkCount: len(chunks),
+		TargetSize: opts.TargetSize,
+		MaxSize:    opts.MaxSize,
+		Overlap:    opts.Overlap,
+		Chunks:     make([]ChunkPlan, len(chunks)),
+	}
+	if s.embedder != nil {
+		plan.Embeddings = len(chunks)
+		plan.EmbedModel = s.embedder.Model()
+	}
+	for i, c := range chunks {
+		plan.Chunks[i] = ChunkPlan{
+			Seq:       i,
+			StartLine: c.StartLine,
+			EndLine:   c.EndLine,
+			Length:    utf8.RuneCountInString(c.Text),
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPlanPut(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	var b strings.Builder
+	for i := 0; i < 20; i++ {
+		b.WriteString("## Heading\n\nA paragraph that is long enough to need its own chunk eventually.\n\n")
+	}
+	p := PutParams{NS: "test", Key: "doc", Content: strings.TrimSpace(b.String()), ChunkTargetSize: 150}
+
+	plan, err := s.PlanPut(p)
+	if err != nil {
+		t.Fatalf("PlanPut: %v", err)
+	}
+	if plan.Format != "markdown" {
+		t.Errorf("expected detected markdown format, got %q", plan.Format)
+	}
+	if plan.ChunkCount < 2 || len(plan.Chunks) != plan.ChunkCount {
+		t.Fatalf("expected several chunks, got %d (%d listed)", plan.ChunkCount, len(plan.Chunks))
+	}
+	if plan.Embeddings != 0 {
+		t.Errorf("expected no embeddings without a provider, got %d", plan.Embeddings)
+	}
+	for i, c := range plan.Chunks {
+		if c.Length == 0 || c.Length > plan.MaxSize || c.StartLine > c.EndLine {
+			t.Errorf("chunk %d has bad bounds: %+v", i, c)
+		}
+	}
+
+	mem, err := s.Put(ctx, p)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if mem.ChunkCount != plan.ChunkCount {
+		t.Errorf("Put made %d chunks, plan said %d", mem.ChunkCount, plan.ChunkCount)
+	}
+
+	if _, err := s.PlanPut(PutParams{NS: "test", Key: "other", Content: "short"}); err != nil {
+		t.Fatalf("PlanPut: %v", err)
+	}
+	if _, err := s.Get(ctx, GetParams{NS: "test", Key: "other"}); err == nil {
+		t.Error("PlanPut should not store anything")
+	}
+}
+
+func TestGetChunks(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	content := strings.Repeat("A line of text that fills up the chunk.\n\n", 20)
+	mem, err := s.Put(ctx, PutParams{NS: "test", Key: "doc", Content: content, ChunkTargetSize: 150})
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	chunks, err := s.GetChunks(ctx, mem.ID)
+	if err != nil {
+		t.Fatalf("GetChunks: %v", err)
+	}
+	if len(chunks) != mem.ChunkCount || len(chunks) < 2 {
+		t.Fatalf("expected %d chunks, got %d", mem.ChunkCount, len(chunks))
+	}
+	for i, c := range chunks {
+		if c.Seq != i || c.MemoryID != mem.ID || c.Text == "" || c.StartLine == 0 {
+			t.Errorf("unexpected chunk %d: %+v", i, c)
+		}
+		if c.HasEmbedding {
+			t.Errorf("chunk %d should have no embedding without a provider", i)
+		}
+	}
+
+	chunks, err = s.GetChunks(ctx, "missing")
+	if err != nil || len(chunks) != 0 {
+		t.Errorf("expected no chunks for an unknown id, got %v, %v", chunks, err)
+	}
+}
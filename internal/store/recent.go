@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+// Orderings for Recent.
+const (
+	RecentByCreated  = "created"
+	RecentByAccessed = "accessed"
+)
+
+// RecentParams holds parameters for listing recently touched memories.
+type RecentParams struct {
+	NS           string
+	By           string // created (default) | accessed
+	Limit        int
+	IncludeNever bool // with By=accessed, also list never-accessed memories (last)
+}
+
+// Recent returns the latest version of each memory ordered by creation or
+// last access time, newest first.
+func (s *SQLiteStore) Recent(ctx context.Context, p RecentParams) ([]model.Memory, error) {
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	where := []string{"m.deleted_at IS NULL", "(m.expires_at IS NULL OR m.expires_at > ?)"}
+	args := []interface{}{now}
+	if p.NS != "" {
+		where = append(where, "m.ns = ?")
+		args = append(args, p.NS)
+	}
+
+	var order string
+	switch p.By {
+	case "", RecentByCreated:
+		order = "m.created_at DESC"
+	case RecentByAccessed:
+		if !p.IncludeNever {
+			where = append(where, "m.last_accessed_at IS NOT NULL")
+		}
+		order = "m.last_accessed_at IS NULL, m.last_accessed_at DESC, m.created_at DESC"
+	default:
+		return nil, fmt.Errorf("invalid order %q (want created or accessed)", p.By)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT m.id, m.ns, m.key, m.content, m.kind, m.tags, m.version, m.supersedes,
+		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at, m.content_encoding, m.actor
+		FROM memories m
+		INNER JOIN (
+			SELECT ns, key, MAX(version) AS max_ver
+			FROM memories WHERE deleted_at IS NULL
+			GROUP BY ns, key
+		) latest ON m.ns = latest.ns AND m.key = latest.key AND m.version = latest.max_ver
+		WHERE %s
+		ORDER BY %s
+		LIMIT ?`, strings.Join(where, " AND "), order)
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []model.Memory
+	for rows.Next() {
+		m, err := s.scanMemory(rows)
+		if err != nil {
+			return nil, err
+		}
+		memories = append(memories, m)
+	}
+	return memories, rows.Err()
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+func TestRecentByCreated(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "first"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "second"})
+	s.Put(ctx, PutParams{NS: "other", Key: "c", Content: "third"})
+	s.db.Exec(`UPDATE memories SET created_at = '2024-01-01T00:00:00Z' WHERE key = 'a'`)
+	s.db.Exec(`UPDATE memories SET created_at = '2024-01-02T00:00:00Z' WHERE key = 'b'`)
+	s.db.Exec(`UPDATE memories SET created_at = '2024-01-03T00:00:00Z' WHERE key = 'c'`)
+
+	got, err := s.Recent(ctx, RecentParams{})
+	if err != nil {
+		t.Fatalf("recent: %v", err)
+	}
+	if len(got) != 3 || got[0].Key != "c" || got[2].Key != "a" {
+		t.Errorf("expected c, b, a; got %v", keys(got))
+	}
+
+	got, _ = s.Recent(ctx, RecentParams{NS: "test", Limit: 1})
+	if len(got) != 1 || got[0].Key != "b" {
+		t.Errorf("expected only b, got %v", keys(got))
+	}
+}
+
+func TestRecentByAccessed(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "first"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "second"})
+	s.Put(ctx, PutParams{NS: "test", Key: "never", Content: "untouched"})
+	s.db.Exec(`UPDATE memories SET last_accessed_at = '2024-01-02T00:00:00Z' WHERE key = 'a'`)
+	s.db.Exec(`UPDATE memories SET last_accessed_at = '2024-01-01T00:00:00Z' WHERE key = 'b'`)
+
+	got, err := s.Recent(ctx, RecentParams{By: RecentByAccessed})
+	if err != nil {
+		t.Fatalf("recent: %v", err)
+	}
+	if len(got) != 2 || got[0].Key != "a" || got[1].Key != "b" {
+		t.Errorf("expected a, b; got %v", keys(got))
+	}
+
+	got, _ = s.Recent(ctx, RecentParams{By: RecentByAccessed, IncludeNever: true})
+	if len(got) != 3 || got[2].Key != "never" {
+		t.Errorf("expected never-accessed last, got %v", keys(got))
+	}
+
+	if _, err := s.Recent(ctx, RecentParams{By: "updated"}); err == nil {
+		t.Error("expected error for unknown ordering")
+	}
+}
+
+func keys(ms []model.Memory) []string {
+	var out []string
+	for _, m := range ms {
+		out = append(out, m.Key)
+	}
+	return out
+}
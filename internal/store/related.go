@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rcliao/agent-memory/internal/embedding"
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+// RelatedParams holds parameters for finding memories similar to one.
+type RelatedParams struct {
+	NS    string
+	Key   string
+	Limit int
+}
+
+// relatedTerms is how many key terms of the target feed the keyword query.
+const relatedTerms = 8
+
+// Related returns memories similar to ns/key, excluding the memory itself.
+// When the target has chunk embeddings and an embedder is configured, its
+// mean chunk vector is used for vector search; otherwise its most frequent
+// terms are OR-ed into a keyword search.
+func (s *SQLiteStore) Related(ctx context.Context, p RelatedParams) ([]SearchResult, error) {
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	target, err := s.latestMemory(ctx, s.db, p.NS, p.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	vec, err := s.memoryVector(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if vec != nil && s.embedder != nil {
+		results, err = s.searchByVector(ctx, SearchParams{}, vec, limit+1)
+	} else {
+		terms := keyTerms(target.Content+" "+target.Key, relatedTerms)
+		if len(terms) == 0 {
+			return []SearchResult{}, nil
+		}
+		quoted := make([]string, len(terms))
+		for i, t := range terms {
+			quoted[i] = `"` + t + `"`
+		}
+		results, err = s.Search(ctx, SearchParams{
+			Query:    strings.Join(quoted, " OR "),
+			RawQuery: true,
+			Mode:     SearchKeyword,
+			Limit:    limit + 1,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := []SearchResult{}
+	for _, r := range results {
+		if r.NS == target.NS && r.Key == target.Key {
+			continue
+		}
+		out = append(out, r)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// memoryVector returns the mean of a memory's chunk embeddings, or nil if
+// none of its chunks are embedded.
+func (s *SQLiteStore) memoryVector(ctx context.Context, memoryID string) (embedding.Vector, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT embedding FROM chunks WHERE memory_id = ? AND embedding IS NOT NULL`, memoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sum embedding.Vector
+	n := 0
+	for rows.Next() {
+		var embJSON string
+		if err := rows.Scan(&embJSON); err != nil {
+			return nil, err
+		}
+		var v embedding.Vector
+		if err := json.Unmarshal([]byte(embJSON), &v); err != nil {
+			continue
+		}
+		if sum == nil {
+			sum = make(embedding.Vector, len(v))
+		}
+		if len(v) != len(sum) {
+			continue
+		}
+		for i := range v {
+			sum[i] += v[i]
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil || n == 0 {
+		return nil, err
+	}
+	for i := range sum {
+		sum[i] /= float32(n)
+	}
+	return sum, nil
+}
+
+var stopWords = map[string]bool{
+	"about": true, "after": true, "also": true, "been": true, "before": true,
+	"from": true, "have": true, "into": true, "just": true, "more": true,
+	"only": true, "should": true, "some": true, "than": true, "that": true,
+	"their": true, "them": true, "then": true, "there": true, "these": true,
+	"they": true, "this": true, "what": true, "when": true, "where": true,
+	"which": true, "will": true, "with": true, "would": true, "were": true,
+}
+
+// keyTerms returns up to n of the most frequent words in text, ignoring
+// short words and common stop words.
+func keyTerms(text string, n int) []string {
+	counts := map[string]int{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isTokenRune(r) }) {
+		if len([]rune(w)) < 4 || stopWords[w] {
+			continue
+		}
+		counts[w]++
+	}
+
+	terms := make([]string, 0, len(counts))
+	for w := range counts {
+		terms = append(terms, w)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if counts[terms[i]] != counts[terms[j]] {
+			return counts[terms[i]] > counts[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	return terms
+}
+
+// rowQuerier is satisfied by *sql.DB and *sql.Tx.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// latestMemory returns the latest non-deleted version of ns/key, including
+// expired ones.
+func (s *SQLiteStore) latestMemory(ctx context.Context, q rowQuerier, ns, key string) (*model.Memory, error) {
+	m, err := s.scanMemory(q.QueryRowContext(ctx,
+		`SELECT id, ns, key, content, kind, tags, version, supersedes,
+		        created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor
+		 FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL
+		 ORDER BY version DESC LIMIT 1`, ns, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("memory not found: %s/%s", ns, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRelatedKeyword(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "db", Content: "postgres replication lag alerts fire when replicas fall behind"})
+	s.Put(ctx, PutParams{NS: "test", Key: "db", Content: "postgres replication lag alerts fire when replicas fall behind primary"})
+	s.Put(ctx, PutParams{NS: "ops", Key: "runbook", Content: "check postgres replication before failover"})
+	s.Put(ctx, PutParams{NS: "test", Key: "ui", Content: "button colors follow the brand palette"})
+
+	got, err := s.Related(ctx, RelatedParams{NS: "test", Key: "db"})
+	if err != nil {
+		t.Fatalf("related: %v", err)
+	}
+	if len(got) != 1 || got[0].Key != "runbook" {
+		var ks []string
+		for _, r := range got {
+			ks = append(ks, r.NS+"/"+r.Key)
+		}
+		t.Errorf("expected only ops/runbook, got %v", ks)
+	}
+
+	if _, err := s.Related(ctx, RelatedParams{NS: "test", Key: "missing"}); err == nil {
+		t.Error("expected error for missing memory")
+	}
+}
+
+func TestRelatedVector(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	s.embedder = &fakeEmbedder{model: "small", dims: 3}
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "entirely different words"})
+	s.Put(ctx, PutParams{NS: "test", Key: "c", Content: "nothing in common"})
+
+	// Every vector is identical, so all other memories are related; keyword
+	// search would find none.
+	got, err := s.Related(ctx, RelatedParams{NS: "test", Key: "a", Limit: 5})
+	if err != nil {
+		t.Fatalf("related: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 related memories, got %d", len(got))
+	}
+	for _, r := range got {
+		if r.Key == "a" {
+			t.Error("target memory should be excluded")
+		}
+	}
+}
+
+func TestKeyTerms(t *testing.T) {
+	got := keyTerms("The deploy script: deploy, rollback, deploy! This is what they said about rollback.", 3)
+	want := []string{"deploy", "rollback", "said"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keyTerms = %v, want %v", got, want)
+	}
+}
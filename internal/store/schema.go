@@ -0,0 +1,123 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/schema"
+)
+
+// NSSchema is a JSON Schema that meta must satisfy for puts to a namespace.
+type NSSchema struct {
+	NS        string          `json:"ns"`
+	Schema    json.RawMessage `json:"schema"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// SetSchema stores the meta schema for ns, replacing any existing one.
+// Memories already in the namespace are not re-validated.
+func (s *SQLiteStore) SetSchema(ctx context.Context, ns string, doc []byte) (*NSSchema, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	if ns == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+	if _, err := schema.Parse(doc); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, doc); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO ns_schemas (ns, schema, updated_at) VALUES (?, ?, ?)
+		 ON CONFLICT(ns) DO UPDATE SET schema = excluded.schema, updated_at = excluded.updated_at`,
+		ns, buf.String(), now.Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordEvent(ctx, tx, EventSchema, ns, "", 0, "set"); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &NSSchema{NS: ns, Schema: buf.Bytes(), UpdatedAt: now.Truncate(time.Second)}, nil
+}
+
+// GetSchema returns the meta schema for ns, or nil if it has none.
+func (s *SQLiteStore) GetSchema(ctx context.Context, ns string) (*NSSchema, error) {
+	return getSchema(ctx, s.db, ns)
+}
+
+func getSchema(ctx context.Context, q rowQuerier, ns string) (*NSSchema, error) {
+	var doc, updated string
+	err := q.QueryRowContext(ctx,
+		`SELECT schema, updated_at FROM ns_schemas WHERE ns = ?`, ns).Scan(&doc, &updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t, _ := time.Parse(time.RFC3339, updated)
+	return &NSSchema{NS: ns, Schema: json.RawMessage(doc), UpdatedAt: t}, nil
+}
+
+// DeleteSchema removes the meta schema for ns. It reports whether one existed.
+func (s *SQLiteStore) DeleteSchema(ctx context.Context, ns string) (bool, error) {
+	if s.readOnly {
+		return false, ErrReadOnly
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM ns_schemas WHERE ns = ?`, ns)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	if err := s.recordEvent(ctx, tx, EventSchema, ns, "", 0, "removed"); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// validateMeta checks meta against the schema for ns, if there is one.
+// Empty meta is validated as an empty object.
+func (s *SQLiteStore) validateMeta(ctx context.Context, q rowQuerier, ns, meta string) error {
+	ss, err := getSchema(ctx, q, ns)
+	if err != nil || ss == nil {
+		return err
+	}
+	sch, err := schema.Parse(ss.Schema)
+	if err != nil {
+		return fmt.Errorf("schema for namespace %q: %w", ns, err)
+	}
+	if meta == "" {
+		meta = "{}"
+	}
+	if err := sch.ValidateJSON([]byte(meta)); err != nil {
+		return fmt.Errorf("meta does not match the schema for namespace %q: %w", ns, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNSSchema(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if got, err := s.GetSchema(ctx, "docs"); err != nil || got != nil {
+		t.Fatalf("expected no schema, got %+v, %v", got, err)
+	}
+	if _, err := s.SetSchema(ctx, "docs", []byte(`{"type":"bogus"}`)); err == nil {
+		t.Error("expected an invalid schema to be rejected")
+	}
+
+	doc := `{"type": "object", "required": ["source"],
+		"properties": {"source": {"type": "string"}}}`
+	if _, err := s.SetSchema(ctx, "docs", []byte(doc)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GetSchema(ctx, "docs")
+	if err != nil || got == nil {
+		t.Fatalf("expected schema, got %+v, %v", got, err)
+	}
+	if string(got.Schema) != `{"type":"object","required":["source"],"properties":{"source":{"type":"string"}}}` {
+		t.Errorf("expected compacted schema, got %s", got.Schema)
+	}
+
+	_, err = s.Put(ctx, PutParams{NS: "docs", Key: "a", Content: "x"})
+	if err == nil || !strings.Contains(err.Error(), `missing required property "source"`) {
+		t.Errorf("expected missing meta to be rejected, got %v", err)
+	}
+	_, err = s.Put(ctx, PutParams{NS: "docs", Key: "a", Content: "x", Meta: `{"source":1}`})
+	if err == nil || !strings.Contains(err.Error(), "$.source: expected string, got integer") {
+		t.Errorf("expected wrong type to be rejected, got %v", err)
+	}
+	if _, err := s.Put(ctx, PutParams{NS: "docs", Key: "a", Content: "x", Meta: "raw", MetaRaw: true}); err == nil {
+		t.Error("expected raw non-JSON meta to be rejected when a schema is set")
+	}
+	if _, err := s.Put(ctx, PutParams{NS: "docs", Key: "a", Content: "x", Meta: `{"source":"web"}`}); err != nil {
+		t.Errorf("expected conforming meta to be accepted: %v", err)
+	}
+	if _, err := s.Put(ctx, PutParams{NS: "other", Key: "a", Content: "x"}); err != nil {
+		t.Errorf("expected other namespaces to be unaffected: %v", err)
+	}
+
+	ok, err := s.DeleteSchema(ctx, "docs")
+	if err != nil || !ok {
+		t.Fatalf("expected schema removed, got %v, %v", ok, err)
+	}
+	if _, err := s.Put(ctx, PutParams{NS: "docs", Key: "b", Content: "x"}); err != nil {
+		t.Errorf("expected puts to be unrestricted after removing the schema: %v", err)
+	}
+	if ok, _ := s.DeleteSchema(ctx, "docs"); ok {
+		t.Error("expected second delete to report no schema")
+	}
+}
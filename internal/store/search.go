@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/rcliao/agent-memory/internal/embedding"
 	"github.com/rcliao/agent-memory/internal/model"
@@ -20,22 +22,80 @@ type SearchParams struct {
 	Query string
 	Kind  string
 	Limit int
+	Mode  string // hybrid (default) | keyword | vector
+
+	// RawQuery passes Query to FTS5 MATCH unmodified (phrases, NOT, OR, NEAR).
+	RawQuery bool
+
+	// Weights overrides the FTS ranking weights; nil uses DefaultRankWeights.
+	Weights *RankWeights
+
+	// Explain attaches a per-result scoring breakdown (SearchResult.Explain).
+	Explain bool
+}
+
+// RankWeights controls how keyword matches are ordered.
+type RankWeights struct {
+	Priority    float64 `json:"priority"`      // weight of priority (low=0.25 .. critical=1.0)
+	Recency     float64 `json:"recency"`       // weight of exponential age decay
+	Match       float64 `json:"match"`         // weight of FTS5 bm25 match strength
+	DecayPerDay float64 `json:"decay_per_day"` // recency decay rate; 0.1 ≈ 7-day half-life
+}
+
+// DefaultRankWeights returns the default keyword ranking weights.
+func DefaultRankWeights() RankWeights {
+	return RankWeights{Priority: 0.2, Recency: 0.3, Match: 0.5, DecayPerDay: 0.1}
+}
+
+// Search modes.
+const (
+	SearchHybrid  = "hybrid"
+	SearchKeyword = "keyword"
+	SearchVector  = "vector"
+)
+
+var validSearchModes = map[string]bool{
+	SearchHybrid:  true,
+	SearchKeyword: true,
+	SearchVector:  true,
 }
 
 // SearchResult wraps a memory with optional match info.
 type SearchResult struct {
 	model.Memory
-	MatchChunk *model.Chunk `json:"match_chunk,omitempty"`
-	Similarity float64      `json:"similarity,omitempty"`
+	MatchChunk *model.Chunk  `json:"match_chunk,omitempty"`
+	Snippet    string        `json:"snippet,omitempty"`
+	Similarity float64       `json:"similarity,omitempty"`
+	Score      float64       `json:"score,omitempty"`    // normalized relevance in (0, 1]
+	Degraded   bool          `json:"degraded,omitempty"` // FTS5 failed; matched by LIKE fallback
+	Explain    *ScoreExplain `json:"explain,omitempty"`
 }
 
 // Search finds memories whose content or chunks match the query substring.
 func (s *SQLiteStore) Search(ctx context.Context, p SearchParams) ([]SearchResult, error) {
+	s.metrics.count("search")
+	defer s.metrics.observeSearch(time.Now())
 	limit := p.Limit
 	if limit <= 0 {
 		limit = 20
 	}
 
+	mode := p.Mode
+	if mode == "" {
+		mode = SearchHybrid
+	}
+	if !validSearchModes[mode] {
+		return nil, fmt.Errorf("invalid search mode %q (valid: hybrid, keyword, vector)", mode)
+	}
+
+	// Vector-only: skip FTS and LIKE matching entirely
+	if mode == SearchVector {
+		if s.embedder == nil {
+			return nil, fmt.Errorf("vector search requires an embedding provider (set AGENT_MEMORY_EMBED_PROVIDER)")
+		}
+		return s.searchVector(ctx, p, limit)
+	}
+
 	now := time.Now().UTC().Format(time.RFC3339)
 	where := []string{"m.deleted_at IS NULL", "(m.expires_at IS NULL OR m.expires_at > ?)"}
 	args := []interface{}{now}
@@ -50,12 +110,16 @@ func (s *SQLiteStore) Search(ctx context.Context, p SearchParams) ([]SearchResul
 	}
 
 	// Try FTS5 first for ranked results, fall back to LIKE for simple substrings
-	// FTS5 query: split terms and join with AND for better matching
-	ftsQuery := strings.Join(strings.Fields(p.Query), " AND ")
+	ftsQuery := buildFTSQuery(p.Query, p.RawQuery)
+	if ftsQuery == "" {
+		// Nothing tokenizable (empty or punctuation-only query)
+		return s.searchLike(ctx, p, where, limit)
+	}
 
 	sql := fmt.Sprintf(`
 		SELECT m.id, m.ns, m.key, m.content, m.kind, m.tags, m.version, m.supersedes,
-		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at
+		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at, m.content_encoding, m.actor,
+		       MIN(fts.rank)
 		FROM memories m
 		INNER JOIN (
 			SELECT ns, key, MAX(version) AS max_ver
@@ -67,25 +131,42 @@ func (s *SQLiteStore) Search(ctx context.Context, p SearchParams) ([]SearchResul
 		WHERE %s AND chunks_fts MATCH ?
 		GROUP BY m.id
 		ORDER BY
-			(CASE m.priority WHEN 'critical' THEN 4 WHEN 'high' THEN 3 WHEN 'normal' THEN 2 ELSE 1 END) * 0.2
-			+ (julianday(m.created_at) / julianday('now')) * 0.3
-			+ (MIN(fts.rank) * -0.5)
+			(CASE m.priority WHEN 'critical' THEN 1.0 WHEN 'high' THEN 0.75 WHEN 'normal' THEN 0.5 ELSE 0.25 END) * ?
+			+ exp(-? * (julianday('now') - julianday(m.created_at))) * ?
+			+ (-MIN(fts.rank)) * ?
 			DESC
 		LIMIT ?`, strings.Join(where, " AND "))
 
-	args = append(args, ftsQuery, limit)
+	w := DefaultRankWeights()
+	if p.Weights != nil {
+		w = *p.Weights
+	}
+	args = append(args, ftsQuery, w.Priority, w.DecayPerDay, w.Recency, w.Match, limit)
 
 	// Try FTS5 first; on error fall back to LIKE entirely
 	rows, err := s.db.QueryContext(ctx, sql, args...)
 	if err != nil {
-		return s.searchLike(ctx, p, where, limit)
+		if p.RawQuery {
+			return nil, fmt.Errorf("fts query %q: %w", p.Query, err)
+		}
+		results, err := s.searchLike(ctx, p, where, limit)
+		if err != nil {
+			return nil, err
+		}
+		for i := range results {
+			results[i].Degraded = true
+		}
+		return results, nil
 	}
 	defer rows.Close()
 
 	var results []SearchResult
+	var ranks []float64
 	seen := map[string]bool{}
+	bestRank := 0.0
 	for rows.Next() {
-		m, err := scanMemory(rows)
+		var rank float64
+		m, err := s.scanMemoryWithExtra(rows, &rank)
 		if err != nil {
 			return nil, err
 		}
@@ -93,16 +174,48 @@ func (s *SQLiteStore) Search(ctx context.Context, p SearchParams) ([]SearchResul
 			continue
 		}
 		seen[m.ID] = true
-		results = append(results, SearchResult{Memory: m})
+		// bm25 ranks are negative; more negative is a better match
+		if rank < bestRank {
+			bestRank = rank
+		}
+		results = append(results, SearchResult{Memory: m, Score: rank})
+		ranks = append(ranks, rank)
+	}
+
+	// Normalize bm25 against the best match so scores fall in (0, 1]
+	explainNow := time.Now().UTC()
+	for i := range results {
+		if bestRank < 0 {
+			results[i].Score = roundScore(results[i].Score / bestRank)
+		} else {
+			results[i].Score = 1
+		}
+		if p.Explain {
+			results[i].Explain = explainFTS(results[i], ranks[i], w, explainNow)
+		}
+	}
+
+	// Attach the best-ranked chunk and a highlighted snippet to each FTS hit
+	for i := range results {
+		chunk, snippet, err := s.ftsMatchChunk(ctx, results[i].ID, ftsQuery)
+		if err == nil {
+			results[i].MatchChunk = chunk
+			results[i].Snippet = snippet
+		}
 	}
 
 	// Supplement with LIKE matches (catches key matches and content that FTS5 tokenizer misses)
-	if len(results) < limit {
+	if len(results) < limit && !p.RawQuery {
 		likeResults, err := s.searchLike(ctx, p, where, limit-len(results))
 		if err == nil {
 			for _, r := range likeResults {
 				if !seen[r.ID] {
 					seen[r.ID] = true
+					// Substring-only matches rank below any token match
+					r.Score = roundScore(r.Score * 0.5)
+					if r.Explain != nil {
+						r.Explain.Score = r.Score
+					}
 					results = append(results, r)
 				}
 			}
@@ -110,8 +223,11 @@ func (s *SQLiteStore) Search(ctx context.Context, p SearchParams) ([]SearchResul
 	}
 
 	// If embedder is available, do vector search and merge/re-rank
-	if s.embedder != nil {
-		vecResults, err := s.searchVector(ctx, p, seen, limit)
+	if mode == SearchHybrid && s.embedder != nil {
+		vecResults, err := s.searchVector(ctx, p, limit)
+		if errors.Is(err, ErrDimsMismatch) {
+			return nil, err
+		}
 		if err == nil && len(vecResults) > 0 {
 			for _, r := range vecResults {
 				if !seen[r.ID] {
@@ -119,6 +235,22 @@ func (s *SQLiteStore) Search(ctx context.Context, p SearchParams) ([]SearchResul
 					results = append(results, r)
 				}
 			}
+			if p.Explain {
+				// Keyword hits keep Similarity 0 for ordering, but show the
+				// similarity the vector pass computed for them.
+				sims := map[string]float64{}
+				for _, r := range vecResults {
+					sims[r.ID] = r.Similarity
+				}
+				for i := range results {
+					if e := results[i].Explain; e != nil {
+						e.RankedBy = RankedBySimilarity
+						if e.Similarity == 0 {
+							e.Similarity = sims[results[i].ID]
+						}
+					}
+				}
+			}
 			// Re-rank by similarity when we have vector scores
 			sort.Slice(results, func(i, j int) bool {
 				// Prefer higher similarity; fall back to recency
@@ -137,13 +269,53 @@ func (s *SQLiteStore) Search(ctx context.Context, p SearchParams) ([]SearchResul
 	return results, nil
 }
 
+// roundScore rounds a relevance score to three decimal places.
+func roundScore(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
+
+// buildFTSQuery converts a user query into an FTS5 MATCH expression. Each term
+// is quoted as a literal and joined with AND; raw queries pass through as-is.
+// Terms with no letters or digits are dropped since they produce no tokens
+// and would make the AND unsatisfiable.
+func buildFTSQuery(query string, raw bool) string {
+	if raw {
+		return query
+	}
+	var terms []string
+	for _, t := range strings.Fields(query) {
+		if strings.IndexFunc(t, isTokenRune) < 0 {
+			continue
+		}
+		terms = append(terms, `"`+strings.ReplaceAll(t, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " AND ")
+}
+
+func isTokenRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
 // searchVector performs semantic search using embeddings.
-func (s *SQLiteStore) searchVector(ctx context.Context, p SearchParams, exclude map[string]bool, limit int) ([]SearchResult, error) {
+func (s *SQLiteStore) searchVector(ctx context.Context, p SearchParams, limit int) ([]SearchResult, error) {
 	// Embed the query
-	queryVec, err := s.embedder.Embed(ctx, p.Query)
+	queryVec, err := embedding.EmbedQuery(ctx, s.embedder, p.Query)
 	if err != nil {
 		return nil, err
 	}
+	return s.searchByVector(ctx, p, queryVec, limit)
+}
+
+// searchByVector ranks the latest version of each memory by the best cosine
+// similarity between queryVec and any of its chunk embeddings.
+func (s *SQLiteStore) searchByVector(ctx context.Context, p SearchParams, queryVec embedding.Vector, limit int) ([]SearchResult, error) {
+	index, err := s.EmbedIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkDims(index, len(queryVec)); err != nil {
+		return nil, err
+	}
 
 	// Fetch all chunks with embeddings (filtered by ns if provided)
 	now := time.Now().UTC().Format(time.RFC3339)
@@ -161,8 +333,8 @@ func (s *SQLiteStore) searchVector(ctx context.Context, p SearchParams, exclude
 
 	query := fmt.Sprintf(`
 		SELECT m.id, m.ns, m.key, m.content, m.kind, m.tags, m.version, m.supersedes,
-		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at,
-		       c.embedding
+		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at, m.content_encoding, m.actor,
+		       c.id, c.seq, c.text, c.start_line, c.end_line, c.embedding
 		FROM memories m
 		INNER JOIN (
 			SELECT ns, key, MAX(version) AS max_ver
@@ -181,19 +353,25 @@ func (s *SQLiteStore) searchVector(ctx context.Context, p SearchParams, exclude
 	// Score each chunk by cosine similarity, keep best per memory
 	type scored struct {
 		memory     model.Memory
+		chunk      model.Chunk
 		similarity float64
 	}
 	best := map[string]*scored{}
 
 	for rows.Next() {
 		var embJSON string
-		m, err := scanMemoryWithExtra(rows, &embJSON)
+		var c model.Chunk
+		var startLine, endLine sql.NullInt64
+		m, err := s.scanMemoryWithExtra(rows, &c.ID, &c.Seq, &c.Text, &startLine, &endLine, &embJSON)
 		if err != nil {
 			continue
 		}
-		if exclude[m.ID] {
-			// Already in results, but we might want to add similarity score
+		if c.Text, err = s.decrypt(c.Text); err != nil {
+			continue
 		}
+		c.MemoryID = m.ID
+		c.StartLine = int(startLine.Int64)
+		c.EndLine = int(endLine.Int64)
 
 		var chunkVec embedding.Vector
 		if err := json.Unmarshal([]byte(embJSON), &chunkVec); err != nil {
@@ -202,21 +380,32 @@ func (s *SQLiteStore) searchVector(ctx context.Context, p SearchParams, exclude
 
 		sim := embedding.CosineSimilarity(queryVec, chunkVec)
 		if existing, ok := best[m.ID]; !ok || sim > existing.similarity {
-			best[m.ID] = &scored{memory: m, similarity: sim}
+			best[m.ID] = &scored{memory: m, chunk: c, similarity: sim}
 		}
 	}
 
 	// Convert to results, filter by minimum similarity
 	var results []SearchResult
+	terms := strings.Fields(p.Query)
 	for _, s := range best {
 		if s.similarity < 0.3 { // minimum threshold
 			continue
 		}
+		chunk := s.chunk
 		results = append(results, SearchResult{
 			Memory:     s.memory,
+			MatchChunk: &chunk,
+			Snippet:    makeSnippet(chunk.Text, terms),
 			Similarity: math.Round(s.similarity*1000) / 1000,
+			Score:      roundScore(s.similarity),
 		})
 	}
+	if p.Explain {
+		now := time.Now().UTC()
+		for i := range results {
+			results[i].Explain = baseExplain(results[i], RankedBySimilarity, now)
+		}
+	}
 
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].Similarity > results[j].Similarity
@@ -230,15 +419,15 @@ func (s *SQLiteStore) searchVector(ctx context.Context, p SearchParams, exclude
 }
 
 // scanMemoryWithExtra scans a memory row plus additional columns.
-func scanMemoryWithExtra(row scanner, extras ...interface{}) (model.Memory, error) {
+func (s *SQLiteStore) scanMemoryWithExtra(row scanner, extras ...interface{}) (model.Memory, error) {
 	var m model.Memory
-	var tagsJSON, supersedes, deletedAt, lastAccessed, meta, expiresAt sql.NullString
+	var tagsJSON, supersedes, deletedAt, lastAccessed, meta, expiresAt, encoding, actor sql.NullString
 	var createdAt string
 
 	dest := []interface{}{
 		&m.ID, &m.NS, &m.Key, &m.Content, &m.Kind, &tagsJSON,
 		&m.Version, &supersedes, &createdAt, &deletedAt,
-		&m.Priority, &m.AccessCount, &lastAccessed, &meta, &expiresAt,
+		&m.Priority, &m.AccessCount, &lastAccessed, &meta, &expiresAt, &encoding, &actor,
 	}
 	dest = append(dest, extras...)
 
@@ -269,8 +458,10 @@ func scanMemoryWithExtra(row scanner, extras ...interface{}) (model.Memory, erro
 		t, _ := time.Parse(time.RFC3339, expiresAt.String)
 		m.ExpiresAt = &t
 	}
+	m.Actor = actor.String
 
-	return m, nil
+	m.Content, err = s.decodeContent(m.Content, encoding)
+	return m, err
 }
 
 // searchLike is the fallback when FTS5 fails.
@@ -292,7 +483,7 @@ func (s *SQLiteStore) searchLike(ctx context.Context, p SearchParams, baseWhere
 
 	sql := fmt.Sprintf(`
 		SELECT DISTINCT m.id, m.ns, m.key, m.content, m.kind, m.tags, m.version, m.supersedes,
-		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at
+		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at, m.content_encoding, m.actor
 		FROM memories m
 		INNER JOIN (
 			SELECT ns, key, MAX(version) AS max_ver
@@ -315,7 +506,7 @@ func (s *SQLiteStore) searchLike(ctx context.Context, p SearchParams, baseWhere
 	var results []SearchResult
 	seen := map[string]bool{}
 	for rows.Next() {
-		m, err := scanMemory(rows)
+		m, err := s.scanMemory(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -325,5 +516,134 @@ func (s *SQLiteStore) searchLike(ctx context.Context, p SearchParams, baseWhere
 		seen[m.ID] = true
 		results = append(results, SearchResult{Memory: m})
 	}
+	rows.Close()
+
+	for i := range results {
+		// LIKE has no match strength; score by position in recency order
+		results[i].Score = roundScore(1 / float64(i+1))
+		if p.Explain {
+			results[i].Explain = baseExplain(results[i], RankedByRecency, time.Now().UTC())
+		}
+		chunk, err := s.likeMatchChunk(ctx, results[i].ID, likeQuery)
+		if err == nil {
+			results[i].MatchChunk = chunk
+			results[i].Snippet = makeSnippet(chunk.Text, []string{p.Query})
+		}
+	}
 	return results, nil
 }
+
+// ftsMatchChunk returns the best-ranked chunk of a memory for an FTS5 query,
+// along with an FTS5 snippet highlighting the matched terms.
+func (s *SQLiteStore) ftsMatchChunk(ctx context.Context, memoryID, ftsQuery string) (*model.Chunk, string, error) {
+	var c model.Chunk
+	var startLine, endLine sql.NullInt64
+	var snippet string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT c.id, c.memory_id, c.seq, c.text, c.start_line, c.end_line,
+		       snippet(chunks_fts, 0, '**', '**', '...', 16)
+		FROM chunks_fts
+		INNER JOIN chunks c ON c.rowid = chunks_fts.rowid
+		WHERE chunks_fts MATCH ? AND c.memory_id = ?
+		ORDER BY chunks_fts.rank
+		LIMIT 1`, ftsQuery, memoryID).Scan(
+		&c.ID, &c.MemoryID, &c.Seq, &c.Text, &startLine, &endLine, &snippet)
+	if err != nil {
+		return nil, "", err
+	}
+	if c.Text, err = s.decrypt(c.Text); err != nil {
+		return nil, "", err
+	}
+	c.StartLine = int(startLine.Int64)
+	c.EndLine = int(endLine.Int64)
+	return &c, snippet, nil
+}
+
+// likeMatchChunk returns the first chunk of a memory containing the LIKE pattern.
+func (s *SQLiteStore) likeMatchChunk(ctx context.Context, memoryID, likeQuery string) (*model.Chunk, error) {
+	var c model.Chunk
+	var startLine, endLine sql.NullInt64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, memory_id, seq, text, start_line, end_line
+		FROM chunks WHERE memory_id = ? AND text LIKE ?
+		ORDER BY seq LIMIT 1`, memoryID, likeQuery).Scan(
+		&c.ID, &c.MemoryID, &c.Seq, &c.Text, &startLine, &endLine)
+	if err != nil {
+		return nil, err
+	}
+	if c.Text, err = s.decrypt(c.Text); err != nil {
+		return nil, err
+	}
+	c.StartLine = int(startLine.Int64)
+	c.EndLine = int(endLine.Int64)
+	return &c, nil
+}
+
+// snippetRadius is the number of runes kept on each side of the first match.
+const snippetRadius = 80
+
+// makeSnippet extracts a window of text around the first matching term and
+// wraps every case-insensitive term occurrence in **...**.
+func makeSnippet(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	first := -1
+	for _, t := range terms {
+		if t == "" {
+			continue
+		}
+		if i := strings.Index(lower, strings.ToLower(t)); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+
+	runes := []rune(text)
+	start, end := 0, len(runes)
+	if first >= 0 {
+		pos := len([]rune(text[:first]))
+		if pos > snippetRadius {
+			start = pos - snippetRadius
+		}
+		if pos+snippetRadius < end {
+			end = pos + snippetRadius
+		}
+	} else if end > 2*snippetRadius {
+		end = 2 * snippetRadius
+	}
+
+	window := string(runes[start:end])
+	for _, t := range terms {
+		window = highlight(window, t)
+	}
+	if start > 0 {
+		window = "..." + window
+	}
+	if end < len(runes) {
+		window += "..."
+	}
+	return window
+}
+
+// highlight wraps case-insensitive occurrences of term in **...**.
+func highlight(text, term string) string {
+	if term == "" {
+		return text
+	}
+	lower := strings.ToLower(text)
+	lt := strings.ToLower(term)
+	if len(lower) != len(text) {
+		return text // case folding changed byte offsets; leave as-is
+	}
+	var b strings.Builder
+	i := 0
+	for {
+		j := strings.Index(lower[i:], lt)
+		if j < 0 {
+			b.WriteString(text[i:])
+			break
+		}
+		b.WriteString(text[i : i+j])
+		b.WriteString("**" + text[i+j:i+j+len(lt)] + "**")
+		i += j + len(lt)
+	}
+	return b.String()
+}
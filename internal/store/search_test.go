@@ -1,10 +1,18 @@
 package store
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/model"
 )
 
 func TestSearch_Basic(t *testing.T) {
@@ -93,7 +101,7 @@ func TestStats(t *testing.T) {
 	s.Put(ctx, PutParams{NS: "ns1", Key: "b", Content: "world"})
 	s.Put(ctx, PutParams{NS: "ns2", Key: "c", Content: "test"})
 
-	stats, err := s.Stats(ctx, dbPath)
+	stats, err := s.Stats(ctx, StatsParams{DBPath: dbPath})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -108,6 +116,69 @@ func TestStats(t *testing.T) {
 	}
 }
 
+func TestStatsBreakdown(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "x", Kind: "episodic", Priority: "critical"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "b", Content: "y", Priority: "critical"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "c", Content: "z", TTL: "1h"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "d", Content: "w"})
+	s.Rm(ctx, RmParams{NS: "ns", Key: "d"})
+	s.db.Exec(`UPDATE memories SET expires_at = '2020-01-01T00:00:00Z' WHERE key = 'c'`)
+
+	stats, err := s.Stats(ctx, StatsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Kinds != nil || stats.Priorities != nil {
+		t.Error("expected no breakdowns by default")
+	}
+	if stats.ExpiredMemories != 1 {
+		t.Errorf("expected 1 expired, got %d", stats.ExpiredMemories)
+	}
+	if stats.DeletedMemories != 1 {
+		t.Errorf("expected 1 deleted, got %d", stats.DeletedMemories)
+	}
+
+	stats, err = s.Stats(ctx, StatsParams{PerKind: true, PerPriority: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Kinds["episodic"] != 1 || stats.Kinds["semantic"] != 2 {
+		t.Errorf("unexpected kinds: %v", stats.Kinds)
+	}
+	if stats.Priorities["critical"] != 2 || stats.Priorities["normal"] != 1 {
+		t.Errorf("unexpected priorities: %v", stats.Priorities)
+	}
+}
+
+func TestStatsEmbeddingCoverage(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "plain", Content: "no vectors here"})
+	s.embedder = &fakeEmbedder{model: "fake", dims: 3}
+	s.Put(ctx, PutParams{NS: "ns", Key: "embedded", Content: "has vectors"})
+
+	stats, err := s.Stats(ctx, StatsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.ChunksWithEmbedding != 1 {
+		t.Errorf("expected 1 chunk with embedding, got %d", stats.ChunksWithEmbedding)
+	}
+	if stats.MemoriesWithEmbedding != 1 {
+		t.Errorf("expected 1 memory with embedding, got %d", stats.MemoriesWithEmbedding)
+	}
+	if stats.AvgChunks != 1 {
+		t.Errorf("expected 1 chunk per memory, got %f", stats.AvgChunks)
+	}
+	if stats.EmbedIndex == nil || stats.EmbedIndex.Dims != 3 {
+		t.Errorf("expected embed index with 3 dims, got %+v", stats.EmbedIndex)
+	}
+}
+
 func TestExportImport(t *testing.T) {
 	dir := t.TempDir()
 	s1, _ := NewSQLiteStore(filepath.Join(dir, "src.db"))
@@ -128,12 +199,12 @@ func TestExportImport(t *testing.T) {
 	s2, _ := NewSQLiteStore(filepath.Join(dir, "dst.db"))
 	defer s2.Close()
 
-	n, err := s2.Import(ctx, exported)
+	res, err := s2.Import(ctx, ImportParams{Memories: exported})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if n != 2 {
-		t.Fatalf("expected 2 imported, got %d", n)
+	if res.Imported != 2 {
+		t.Fatalf("expected 2 imported, got %d", res.Imported)
 	}
 
 	// Verify
@@ -143,6 +214,256 @@ func TestExportImport(t *testing.T) {
 	}
 }
 
+func TestImportSkipsDuplicates(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha"})
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha v2"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "beta"})
+	exported, _ := s.ExportAll(ctx, "test")
+
+	res, err := s.Import(ctx, ImportParams{Memories: exported})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Imported != 0 || res.Skipped != 3 {
+		t.Errorf("expected 0 imported / 3 skipped, got %+v", res)
+	}
+
+	exported = append(exported, model.Memory{NS: "test", Key: "b", Content: "beta v2", Version: 2})
+	res, _ = s.Import(ctx, ImportParams{Memories: exported})
+	if res.Imported != 1 || res.Skipped != 3 {
+		t.Errorf("expected 1 imported / 3 skipped, got %+v", res)
+	}
+
+	res, _ = s.Import(ctx, ImportParams{Memories: exported, Force: true})
+	if res.Imported != 4 || res.Skipped != 0 {
+		t.Errorf("expected force to import all 4, got %+v", res)
+	}
+}
+
+func TestImportMergeStrategies(t *testing.T) {
+	ctx := context.Background()
+	older := time.Now().UTC().Add(-time.Hour)
+	newer := time.Now().UTC().Add(time.Hour)
+	incoming := []model.Memory{
+		{NS: "test", Key: "a", Content: "old a", CreatedAt: older},
+		{NS: "test", Key: "b", Content: "new b", CreatedAt: newer},
+		{NS: "test", Key: "c", Content: "c v1", CreatedAt: older},
+		{NS: "test", Key: "c", Content: "c v2", CreatedAt: older, Version: 2},
+	}
+
+	tests := []struct {
+		strategy MergeStrategy
+		imported int
+		latest   map[string]string
+	}{
+		{MergeVersion, 4, map[string]string{"a": "old a", "b": "new b", "c": "c v2"}},
+		{MergeSkip, 2, map[string]string{"a": "local a", "b": "local b", "c": "c v2"}},
+		{MergeNewest, 3, map[string]string{"a": "local a", "b": "new b", "c": "c v2"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			s := newTestStore(t)
+			s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "local a"})
+			s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "local b"})
+
+			res, err := s.Import(ctx, ImportParams{Memories: incoming, OnConflict: tt.strategy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Imported != tt.imported {
+				t.Errorf("expected %d imported, got %+v", tt.imported, res)
+			}
+			for key, want := range tt.latest {
+				got, err := s.Get(ctx, GetParams{NS: "test", Key: key})
+				if err != nil {
+					t.Fatalf("get %s: %v", key, err)
+				}
+				if got[0].Content != want {
+					t.Errorf("%s: expected %q, got %q", key, want, got[0].Content)
+				}
+			}
+		})
+	}
+
+	s := newTestStore(t)
+	if _, err := s.Import(ctx, ImportParams{OnConflict: "bogus"}); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+// exportAll collects the memories ExportEach visits.
+func exportAll(t *testing.T, s *SQLiteStore, p ExportParams) []model.Memory {
+	t.Helper()
+	var memories []model.Memory
+	if err := s.ExportEach(context.Background(), p, func(m model.Memory) error {
+		memories = append(memories, m)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return memories
+}
+
+func TestExportEach(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha"})
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha v2"})
+	s.Put(ctx, PutParams{NS: "other", Key: "b", Content: "beta"})
+
+	memories := exportAll(t, s, ExportParams{NS: "test"})
+	if len(memories) != 2 {
+		t.Fatalf("expected 2 exported, got %d", len(memories))
+	}
+	if m := memories[1]; m.Key != "a" || m.Version != 2 || m.Content != "alpha v2" {
+		t.Errorf("unexpected second memory: %+v", m)
+	}
+
+	stop := errors.New("stop")
+	n := 0
+	err := s.ExportEach(ctx, ExportParams{}, func(model.Memory) error {
+		n++
+		return stop
+	})
+	if err != stop || n != 1 {
+		t.Errorf("expected the callback error to stop the export, got %v after %d", err, n)
+	}
+}
+
+func TestExportEachCallbackUsesStore(t *testing.T) {
+	s := newTestStore(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer func(n int) { exportPageSize = n }(exportPageSize)
+	exportPageSize = 2
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		s.Put(ctx, PutParams{NS: "test", Key: key, Content: "content " + key})
+	}
+	s.Put(ctx, PutParams{NS: "test", Key: "c", Content: "content c v2"})
+
+	var got []string
+	err := s.ExportEach(ctx, ExportParams{NS: "test"}, func(m model.Memory) error {
+		if _, err := s.Get(ctx, GetParams{NS: m.NS, Key: m.Key}); err != nil {
+			return err
+		}
+		got = append(got, fmt.Sprintf("%s/v%d", m.Key, m.Version))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportEach: %v", err)
+	}
+	if want := "a/v1,b/v1,c/v1,c/v2,d/v1,e/v1"; strings.Join(got, ",") != want {
+		t.Errorf("expected %s across pages, got %v", want, got)
+	}
+}
+
+func TestExportStream(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha"})
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha v2"})
+	s.Put(ctx, PutParams{NS: "other", Key: "b", Content: "beta"})
+
+	var buf bytes.Buffer
+	n, err := s.ExportStream(ctx, ExportParams{NS: "test"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 exported, got %d", n)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	var m model.Memory
+	if err := json.Unmarshal([]byte(lines[1]), &m); err != nil {
+		t.Fatalf("parse line: %v", err)
+	}
+	if m.Key != "a" || m.Version != 2 || m.Content != "alpha v2" {
+		t.Errorf("unexpected second line: %+v", m)
+	}
+}
+
+func TestExportIncludeDeleted(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "beta"})
+	s.Rm(ctx, RmParams{NS: "test", Key: "b"})
+
+	if n := len(exportAll(t, s, ExportParams{NS: "test"})); n != 1 {
+		t.Errorf("expected 1 live memory, got %d", n)
+	}
+
+	memories := exportAll(t, s, ExportParams{NS: "test", IncludeDeleted: true})
+	if len(memories) != 2 {
+		t.Fatalf("expected 2 with deleted, got %d", len(memories))
+	}
+
+	dst := newTestStore(t)
+	if _, err := dst.Import(ctx, ImportParams{Memories: memories}); err != nil {
+		t.Fatal(err)
+	}
+	live, _ := dst.List(ctx, ListParams{NS: "test"})
+	if len(live) != 1 || live[0].Key != "a" {
+		t.Errorf("expected only a live after import, got %v", keys(live))
+	}
+	deleted, _ := dst.List(ctx, ListParams{NS: "test", OnlyDeleted: true})
+	if len(deleted) != 1 || deleted[0].Key != "b" {
+		t.Errorf("expected b imported as deleted, got %v", keys(deleted))
+	}
+	events, _ := dst.Events(ctx, EventsParams{NS: "test", Op: EventRm})
+	if len(events) != 1 || events[0].Key != "b" {
+		t.Errorf("expected an rm event for the deleted import, got %+v", events)
+	}
+}
+
+func TestExportSinceID(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	first, _ := s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "one"})
+	time.Sleep(2 * time.Millisecond) // ULIDs only order across milliseconds
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "two"})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "three"})
+
+	all, _ := s.ExportAll(ctx, "test")
+	var since []model.Memory
+	err := s.ExportEach(ctx, ExportParams{NS: "test", SinceID: strings.ToLower(first.ID)}, func(m model.Memory) error {
+		since = append(since, m)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || len(since) != 2 {
+		t.Fatalf("expected 2 of 3 versions after the watermark, got %d of %d", len(since), len(all))
+	}
+	for _, m := range since {
+		if m.ID == first.ID {
+			t.Error("the watermark itself should not be exported again")
+		}
+	}
+
+	err = s.ExportEach(ctx, ExportParams{SinceID: "not-a-ulid"}, func(model.Memory) error { return nil })
+	if err == nil {
+		t.Error("expected an error for an invalid since id")
+	}
+
+	if n := len(exportAll(t, s, ExportParams{Since: time.Now().Add(time.Hour)})); n != 0 {
+		t.Errorf("expected nothing created in the future, got %d", n)
+	}
+}
+
 func TestTTL_Expired(t *testing.T) {
 	dir := t.TempDir()
 	dbPath := filepath.Join(dir, "test.db")
@@ -194,5 +515,265 @@ func TestTTL_ParseTTL(t *testing.T) {
 	}
 }
 
+func TestSearch_Modes(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.Search(ctx, SearchParams{Query: "x", Mode: SearchVector}); err == nil {
+		t.Fatal("expected error for vector mode without embedder")
+	}
+	if _, err := s.Search(ctx, SearchParams{Query: "x", Mode: "fuzzy"}); err == nil {
+		t.Fatal("expected error for invalid mode")
+	}
+
+	s.embedder = &fakeEmbedder{model: "fake", dims: 3}
+	s.Put(ctx, PutParams{NS: "test", Key: "golang", Content: "Go has goroutines"})
+	s.Put(ctx, PutParams{NS: "test", Key: "python", Content: "Python has generators"})
+
+	// Keyword mode: only the literal match, no vector scores
+	results, err := s.Search(ctx, SearchParams{Query: "goroutines", Mode: SearchKeyword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 keyword result, got %d", len(results))
+	}
+	if results[0].Similarity != 0 {
+		t.Errorf("expected no similarity in keyword mode, got %f", results[0].Similarity)
+	}
+
+	// Vector mode: the fake embedder matches everything
+	results, err = s.Search(ctx, SearchParams{Query: "goroutines", Mode: SearchVector})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 vector results, got %d", len(results))
+	}
+}
+
+func TestSearch_NewerOutranksOlder(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "old", Content: "deploy checklist for staging"})
+	s.Put(ctx, PutParams{NS: "test", Key: "new", Content: "deploy checklist for staging"})
+	s.db.Exec(`UPDATE memories SET created_at = '2020-01-01T00:00:00Z' WHERE key = 'old'`)
+
+	results, err := s.Search(ctx, SearchParams{Query: "deploy", Mode: SearchKeyword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Key != "new" {
+		t.Errorf("expected newer memory first, got %s", results[0].Key)
+	}
+}
+
+func TestSearch_CustomWeights(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "old-critical", Content: "deploy checklist", Priority: "critical"})
+	s.Put(ctx, PutParams{NS: "test", Key: "new-low", Content: "deploy checklist", Priority: "low"})
+	s.db.Exec(`UPDATE memories SET created_at = '2020-01-01T00:00:00Z' WHERE key = 'old-critical'`)
+
+	// Default weights: recency dominates
+	results, _ := s.Search(ctx, SearchParams{Query: "deploy", Mode: SearchKeyword})
+	if len(results) != 2 || results[0].Key != "new-low" {
+		t.Fatalf("expected new-low first with default weights, got %+v", results)
+	}
+
+	// Priority-only weights: critical wins regardless of age
+	results, _ = s.Search(ctx, SearchParams{
+		Query:   "deploy",
+		Mode:    SearchKeyword,
+		Weights: &RankWeights{Priority: 1},
+	})
+	if len(results) != 2 || results[0].Key != "old-critical" {
+		t.Fatalf("expected old-critical first with priority weights, got %+v", results)
+	}
+}
+
+func TestSearch_Explain(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "old-critical", Content: "deploy checklist", Priority: "critical"})
+	s.Put(ctx, PutParams{NS: "test", Key: "new-low", Content: "deploy checklist", Priority: "low"})
+	s.Put(ctx, PutParams{NS: "test", Key: "deploy-key", Content: "only the key matches"})
+	s.db.Exec(`UPDATE memories SET created_at = '2020-01-01T00:00:00Z' WHERE key = 'old-critical'`)
+
+	results, _ := s.Search(ctx, SearchParams{Query: "deploy", Mode: SearchKeyword})
+	if len(results) == 0 || results[0].Explain != nil {
+		t.Fatalf("expected no explanation unless asked, got %+v", results)
+	}
+
+	results, _ = s.Search(ctx, SearchParams{Query: "deploy", Mode: SearchKeyword, Explain: true})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for _, r := range results[:2] {
+		e := r.Explain
+		if e == nil || e.RankedBy != RankedByFTS || e.FTSRank > 0 {
+			t.Fatalf("expected an fts explanation for %s, got %+v", r.Key, e)
+		}
+		if e.Score != r.Score {
+			t.Errorf("%s: explain score %v != result score %v", r.Key, e.Score, r.Score)
+		}
+		sum := e.PriorityBoost + e.RecencyBoost + e.MatchBoost
+		if diff := sum - e.Combined; diff > 0.002 || diff < -0.002 {
+			t.Errorf("%s: boosts sum to %v, combined is %v", r.Key, sum, e.Combined)
+		}
+	}
+	if results[0].Explain.Combined < results[1].Explain.Combined {
+		t.Error("fts results should be ordered by combined score")
+	}
+	old := results[1].Explain
+	if results[1].Key != "old-critical" || old.Priority != 1 || old.AgeDays < 365 || old.RecencyFactor > 0.01 {
+		t.Errorf("unexpected explanation for old-critical: %+v", old)
+	}
+	if e := results[2].Explain; e == nil || e.RankedBy != RankedByRecency || e.Score != results[2].Score {
+		t.Errorf("expected a recency explanation for the key-only match, got %+v", e)
+	}
+}
+
+func TestSearch_MatchChunkAndSnippet(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "golang", Content: "Go is a compiled language with goroutines"})
+	s.Put(ctx, PutParams{NS: "test", Key: "keyonly", Content: "nothing relevant"})
+
+	results, err := s.Search(ctx, SearchParams{Query: "goroutines"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].MatchChunk == nil {
+		t.Fatal("expected match chunk to be populated")
+	}
+	if results[0].MatchChunk.MemoryID != results[0].ID {
+		t.Errorf("match chunk belongs to %s, expected %s", results[0].MatchChunk.MemoryID, results[0].ID)
+	}
+	if !strings.Contains(results[0].Snippet, "**goroutines**") {
+		t.Errorf("expected highlighted snippet, got %q", results[0].Snippet)
+	}
+}
+
+func TestMakeSnippet(t *testing.T) {
+	got := makeSnippet("Deploy with Docker, then deploy again", []string{"deploy"})
+	want := "**Deploy** with Docker, then **deploy** again"
+	if got != want {
+		t.Errorf("makeSnippet = %q, want %q", got, want)
+	}
+
+	long := strings.Repeat("x", 200) + " needle " + strings.Repeat("y", 200)
+	got = makeSnippet(long, []string{"needle"})
+	if !strings.HasPrefix(got, "...") || !strings.HasSuffix(got, "...") {
+		t.Errorf("expected trimmed snippet with ellipses, got %q", got)
+	}
+	if !strings.Contains(got, "**needle**") {
+		t.Errorf("expected highlighted term, got %q", got)
+	}
+}
+
+func TestSearch_RawQuery(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "pool", Content: "tune the connection pool size"})
+	s.Put(ctx, PutParams{NS: "test", Key: "split", Content: "the pool for each connection"})
+	s.Put(ctx, PutParams{NS: "test", Key: "timeout", Content: "error: connection timeout"})
+	s.Put(ctx, PutParams{NS: "test", Key: "refused", Content: "error: connection refused"})
+
+	results, err := s.Search(ctx, SearchParams{Query: `"connection pool"`, RawQuery: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Key != "pool" {
+		t.Fatalf("expected only phrase match, got %+v", results)
+	}
+
+	results, err = s.Search(ctx, SearchParams{Query: "error NOT timeout", RawQuery: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Key != "refused" {
+		t.Fatalf("expected exclusion to leave refused, got %+v", results)
+	}
+
+	if _, err := s.Search(ctx, SearchParams{Query: `"unbalanced`, RawQuery: true}); err == nil {
+		t.Fatal("expected syntax error for raw query")
+	}
+}
+
+func TestBuildFTSQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		raw   bool
+		want  string
+	}{
+		{"deploy steps", false, `"deploy" AND "steps"`},
+		{`say "hi`, false, `"say" AND """hi"`},
+		{"ns:key", false, `"ns:key"`},
+		{`"exact phrase" OR other`, true, `"exact phrase" OR other`},
+	}
+	for _, tt := range tests {
+		if got := buildFTSQuery(tt.query, tt.raw); got != tt.want {
+			t.Errorf("buildFTSQuery(%q, %v) = %q, want %q", tt.query, tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestSearch_PunctuationTerms(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "greeting", Content: "hello: world"})
+
+	for _, q := range []string{"hello :", `"hello`, "hello -", "ns:key hello"} {
+		results, err := s.Search(ctx, SearchParams{Query: q})
+		if err != nil {
+			t.Fatalf("search %q: %v", q, err)
+		}
+		for _, r := range results {
+			if r.Degraded {
+				t.Errorf("search %q unexpectedly fell back to LIKE", q)
+			}
+		}
+	}
+
+	results, _ := s.Search(ctx, SearchParams{Query: "hello :"})
+	if len(results) != 1 {
+		t.Fatalf("expected punctuation term to be ignored, got %d results", len(results))
+	}
+}
+
+func TestSearch_DegradedFallback(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "greeting", Content: "hello world"})
+
+	// Break the FTS index so MATCH fails
+	s.db.Exec(`DROP TABLE chunks_fts`)
+
+	results, err := s.Search(ctx, SearchParams{Query: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected LIKE fallback to find 1 result, got %d", len(results))
+	}
+	if !results[0].Degraded {
+		t.Error("expected result to be marked degraded")
+	}
+}
+
 // Ensure unused import doesn't break
 var _ = os.TempDir
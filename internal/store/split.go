@@ -0,0 +1,149 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rcliao/agent-memory/internal/chunker"
+)
+
+// Split strategies.
+const (
+	SplitByHeading = "heading" // one memory per markdown section
+	SplitByChunk   = "chunk"   // one memory per search chunk
+)
+
+// SplitParams holds parameters for breaking a memory into several.
+type SplitParams struct {
+	NS         string
+	Key        string
+	By         string // SplitByHeading (default) or SplitByChunk
+	Level      int    // heading level to split at; 0 picks one (see chunker.Sections)
+	RmOriginal bool   // soft-delete every version of the original
+}
+
+// SplitResult reports the memories created by Split.
+type SplitResult struct {
+	Original MemoryRef `json:"original"`
+	Keys     []string  `json:"keys"`
+	Removed  bool      `json:"removed,omitempty"`
+}
+
+// Split stores each section (or chunk) of the latest version of NS/Key as a
+// new memory keyed "<key>#<slug>", with the original's kind, tags, priority,
+// and meta, and links each one to the original with refines. Everything
+// happens in one transaction.
+func (s *SQLiteStore) Split(ctx context.Context, p SplitParams) (*SplitResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	by := p.By
+	if by == "" {
+		by = SplitByHeading
+	}
+	if by != SplitByHeading && by != SplitByChunk {
+		return nil, fmt.Errorf("invalid split strategy %q (valid: heading, chunk)", p.By)
+	}
+
+	// Build and embed the new memories before taking the write lock, then
+	// check inside the transaction that the original did not change meanwhile.
+	orig, err := s.latestMemory(ctx, s.db, p.NS, p.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	type part struct{ slug, text string }
+	var parts []part
+	if by == SplitByHeading {
+		for _, sec := range chunker.Sections(orig.Content, p.Level) {
+			slug := chunker.Slug(sec.Heading)
+			if sec.Level == 0 {
+				slug = "intro"
+			}
+			parts = append(parts, part{slug, sec.Text})
+		}
+	} else {
+		_, chunkr, err := chunkerFor(PutParams{Content: orig.Content})
+		if err != nil {
+			return nil, err
+		}
+		opts := s.chunkOptions(PutParams{})
+		opts.Overlap = 0
+		for i, c := range chunkr.Chunk(orig.Content, opts) {
+			parts = append(parts, part{fmt.Sprintf("part-%d", i+1), c.Text})
+		}
+	}
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("nothing to split: %s/%s has a single %s", p.NS, p.Key, by)
+	}
+
+	result := &SplitResult{Original: MemoryRef{NS: orig.NS, Key: orig.Key}, Keys: []string{}}
+	puts := make([]PutParams, len(parts))
+	prepared := make([][]embeddedChunk, len(parts))
+	used := map[string]bool{}
+	for i, pt := range parts {
+		slug := pt.slug
+		if slug == "" {
+			slug = fmt.Sprintf("section-%d", i+1)
+		}
+		for base, n := slug, 2; used[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[slug] = true
+		puts[i] = PutParams{
+			NS:       orig.NS,
+			Key:      orig.Key + "#" + slug,
+			Content:  pt.text,
+			Kind:     orig.Kind,
+			Tags:     orig.Tags,
+			Priority: orig.Priority,
+			Meta:     orig.Meta,
+		}
+		if prepared[i], err = s.prepareChunks(ctx, puts[i]); err != nil {
+			return nil, fmt.Errorf("store %s: %w", puts[i].Key, err)
+		}
+	}
+
+	index, err := s.EmbedIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if current, err := s.latestMemory(ctx, tx, p.NS, p.Key); err != nil {
+		return nil, err
+	} else if current.ID != orig.ID {
+		return nil, ErrChanged
+	}
+
+	for i, put := range puts {
+		if _, err := s.putTx(ctx, tx, put, prepared[i], &index); err != nil {
+			return nil, fmt.Errorf("store %s: %w", put.Key, err)
+		}
+		lp := LinkParams{FromNS: orig.NS, FromKey: put.Key, ToNS: orig.NS, ToKey: orig.Key, Rel: "refines"}
+		if _, err := linkTx(ctx, tx, lp); err != nil {
+			return nil, err
+		}
+		if err := s.recordLinkEvent(ctx, tx, lp); err != nil {
+			return nil, err
+		}
+		result.Keys = append(result.Keys, put.Key)
+	}
+
+	if p.RmOriginal {
+		if err := s.rmTx(ctx, tx, RmParams{NS: orig.NS, Key: orig.Key, AllVersions: true}); err != nil {
+			return nil, err
+		}
+		result.Removed = true
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
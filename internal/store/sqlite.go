@@ -1,16 +1,21 @@
 package store
 
 import (
+	"bytes"
 	"context"
+	"crypto/cipher"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -23,27 +28,127 @@ import (
 
 // SQLiteStore implements Store using SQLite.
 type SQLiteStore struct {
-	db       *sql.DB
-	entropy  *rand.Rand
-	embedder embedding.Embedder
+	db         *sql.DB
+	idMu       sync.Mutex // guards entropy, which is not safe for concurrent use
+	entropy    *rand.Rand
+	embedder   embedding.Embedder
+	forceDims  bool
+	tokenizer  string
+	readOnly   bool
+	chunkOpts  chunker.Options // default chunking options; see WithChunkerOptions
+	aead       cipher.AEAD     // content cipher from $AGENT_MEMORY_KEY, or nil
+	compressAt int             // compress content larger than this many bytes; 0 = never
+	maxContent int             // reject content larger than this many bytes; 0 = no limit
+	actor      string          // recorded with each event; see SetActor
+	allowedNS  []string        // namespace patterns writes are limited to; see SetAllowedNamespaces
+	metrics    metrics         // operation counters; see WriteMetrics
+}
+
+// ErrReadOnly is returned by write operations on a read-only store.
+var ErrReadOnly = errors.New("store is read-only")
+
+// DefaultBusyTimeout is how long a connection waits on a locked database
+// before failing with SQLITE_BUSY. Override with $AGENT_MEMORY_BUSY_TIMEOUT (ms).
+const DefaultBusyTimeout = 5000 * time.Millisecond
+
+// busyTimeout returns the configured busy timeout in milliseconds.
+func busyTimeout() (int, error) {
+	env := os.Getenv("AGENT_MEMORY_BUSY_TIMEOUT")
+	if env == "" {
+		return int(DefaultBusyTimeout / time.Millisecond), nil
+	}
+	ms, err := strconv.Atoi(env)
+	if err != nil || ms < 0 {
+		return 0, fmt.Errorf("invalid AGENT_MEMORY_BUSY_TIMEOUT %q (milliseconds)", env)
+	}
+	return ms, nil
+}
+
+// chunkOverlap returns the default chunk overlap from $AGENT_MEMORY_CHUNK_OVERLAP.
+func chunkOverlap() (int, error) {
+	env := os.Getenv("AGENT_MEMORY_CHUNK_OVERLAP")
+	if env == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(env)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid AGENT_MEMORY_CHUNK_OVERLAP %q (chars)", env)
+	}
+	return n, nil
+}
+
+// DefaultMaxContent is the largest memory content Put accepts, in bytes.
+// Override with $AGENT_MEMORY_MAX_CONTENT; 0 removes the limit.
+const DefaultMaxContent = 4 << 20
+
+// ErrContentTooLarge is returned by Put when content exceeds the size limit.
+var ErrContentTooLarge = errors.New("content too large")
+
+// MaxContentSize returns the configured content size limit in bytes, or 0
+// for no limit.
+func MaxContentSize() (int, error) {
+	env := os.Getenv("AGENT_MEMORY_MAX_CONTENT")
+	if env == "" {
+		return DefaultMaxContent, nil
+	}
+	n, err := strconv.Atoi(env)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid AGENT_MEMORY_MAX_CONTENT %q (bytes)", env)
+	}
+	return n, nil
 }
 
 // NewSQLiteStore opens or creates a SQLite database at the given path.
-func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
-	dir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return nil, fmt.Errorf("create db dir: %w", err)
+func NewSQLiteStore(dbPath string, opts ...Option) (*SQLiteStore, error) {
+	o := newOptions(opts)
+	if o.readOnly {
+		return openReadOnly(dbPath, o)
+	}
+	if dbPath != ":memory:" {
+		if err := checkDBPath(dbPath); err != nil {
+			return nil, err
+		}
 	}
 
-	db, err := sql.Open("sqlite", dbPath+"?_pragma=journal_mode(wal)&_pragma=foreign_keys(on)")
+	timeout, err := o.timeout()
+	if err != nil {
+		return nil, err
+	}
+	chunkOpts := chunker.DefaultOptions()
+	if o.chunkOpts != nil {
+		chunkOpts = *o.chunkOpts
+	} else if chunkOpts.Overlap, err = chunkOverlap(); err != nil {
+		return nil, err
+	}
+	compressAt, err := compressThreshold()
+	if err != nil {
+		return nil, err
+	}
+	maxContent, err := MaxContentSize()
+	if err != nil {
+		return nil, err
+	}
+	// _txlock=immediate takes the write lock at BEGIN so a transaction never
+	// has to upgrade from a stale read snapshot (which fails without waiting).
+	db, err := sql.Open("sqlite", fmt.Sprintf(
+		"%s?_pragma=journal_mode(wal)&_pragma=foreign_keys(on)&_pragma=busy_timeout(%d)&_txlock=immediate",
+		dbPath, timeout))
 	if err != nil {
 		return nil, fmt.Errorf("open db: %w", err)
 	}
+	// SQLite allows a single writer; one connection serializes writes in
+	// this process instead of failing lock upgrades with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
 
 	s := &SQLiteStore{
-		db:       db,
-		entropy:  rand.New(rand.NewSource(time.Now().UnixNano())),
-		embedder: embedding.NewFromEnv(),
+		db:         db,
+		entropy:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		embedder:   o.embedderOrEnv(),
+		tokenizer:  os.Getenv("AGENT_MEMORY_FTS_TOKENIZER"),
+		chunkOpts:  chunkOpts,
+		compressAt: compressAt,
+		maxContent: maxContent,
 	}
 
 	if err := s.migrate(); err != nil {
@@ -51,104 +156,256 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 		return nil, fmt.Errorf("migrate: %w", err)
 	}
 
+	if err := s.loadCipher(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("content key: %w", err)
+	}
+
+	if autoSweep() {
+		if _, err := s.SweepExpired(context.Background(), false); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("sweep expired: %w", err)
+		}
+	}
+
 	return s, nil
 }
 
+// checkDBPath turns common path misconfigurations into actionable errors
+// before SQLite reports them cryptically, creating the parent directory if
+// it does not exist yet.
+func checkDBPath(dbPath string) error {
+	if info, err := os.Stat(dbPath); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("database path %s is a directory; pass a file path such as %s",
+				dbPath, filepath.Join(dbPath, "memory.db"))
+		}
+		f, err := os.OpenFile(dbPath, os.O_RDWR, 0)
+		if err != nil {
+			return fmt.Errorf("database file %s is not writable (use --read-only to open it for reading): %w", dbPath, err)
+		}
+		f.Close()
+		return nil
+	}
+
+	dir := filepath.Dir(dbPath)
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		return fmt.Errorf("cannot create database %s: parent %s is a file, not a directory", dbPath, dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create db dir: %w", err)
+	}
+	probe, err := os.CreateTemp(dir, ".agent-memory-*")
+	if err != nil {
+		return fmt.Errorf("cannot create database in %s: directory is not writable: %w", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return nil
+}
+
+// NewReadOnlyStore opens an existing SQLite database without write access.
+// Migrations and access tracking are skipped so that many concurrent readers
+// never contend for the write lock.
+func NewReadOnlyStore(dbPath string, opts ...Option) (*SQLiteStore, error) {
+	return openReadOnly(dbPath, newOptions(opts))
+}
+
+func openReadOnly(dbPath string, o *options) (*SQLiteStore, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+
+	timeout, err := o.timeout()
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro&_pragma=busy_timeout(%d)", dbPath, timeout))
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+
+	s := &SQLiteStore{
+		db:       db,
+		entropy:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		embedder: o.embedderOrEnv(),
+		readOnly: true,
+	}
+	if err := s.loadCipher(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("content key: %w", err)
+	}
+	return s, nil
+}
+
+// NewMemoryStore creates an ephemeral store backed by an in-memory SQLite
+// database. Nothing touches disk and all data is lost on Close. Each call
+// returns an independent database.
+func NewMemoryStore(opts ...Option) (*SQLiteStore, error) {
+	return NewSQLiteStore(":memory:", opts...)
+}
+
 func (s *SQLiteStore) newID() string {
+	s.idMu.Lock()
+	defer s.idMu.Unlock()
 	return ulid.MustNew(ulid.Timestamp(time.Now()), s.entropy).String()
 }
 
+// migrate brings the schema to the latest version, then sets up the FTS
+// index, whose tokenizer is configuration rather than schema.
 func (s *SQLiteStore) migrate() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS memories (
-		id          TEXT PRIMARY KEY,
-		ns          TEXT NOT NULL,
-		key         TEXT NOT NULL,
-		content     TEXT NOT NULL,
-		kind        TEXT NOT NULL DEFAULT 'semantic',
-		tags        TEXT,
-		version     INTEGER NOT NULL DEFAULT 1,
-		supersedes  TEXT,
-		created_at  TEXT NOT NULL,
-		deleted_at  TEXT,
-		priority    TEXT NOT NULL DEFAULT 'normal',
-		access_count INTEGER NOT NULL DEFAULT 0,
-		last_accessed_at TEXT,
-		meta        TEXT,
-		expires_at  TEXT
-	);
-	CREATE INDEX IF NOT EXISTS idx_memories_ns_key ON memories(ns, key);
-	CREATE INDEX IF NOT EXISTS idx_memories_ns_kind ON memories(ns, kind);
-	CREATE INDEX IF NOT EXISTS idx_memories_created ON memories(created_at DESC);
-	CREATE INDEX IF NOT EXISTS idx_memories_deleted ON memories(deleted_at);
-	CREATE INDEX IF NOT EXISTS idx_memories_priority ON memories(ns, priority);
-
-	CREATE TABLE IF NOT EXISTS chunks (
-		id          TEXT PRIMARY KEY,
-		memory_id   TEXT NOT NULL REFERENCES memories(id),
-		seq         INTEGER NOT NULL,
-		text        TEXT NOT NULL,
-		start_line  INTEGER,
-		end_line    INTEGER,
-		embedding   TEXT
-	);
-	CREATE INDEX IF NOT EXISTS idx_chunks_memory ON chunks(memory_id);
-	CREATE INDEX IF NOT EXISTS idx_memories_expires ON memories(expires_at);
-
-	CREATE TABLE IF NOT EXISTS memory_links (
-		from_id    TEXT NOT NULL REFERENCES memories(id),
-		to_id      TEXT NOT NULL REFERENCES memories(id),
-		rel        TEXT NOT NULL,
-		created_at TEXT NOT NULL,
-		PRIMARY KEY (from_id, to_id, rel)
-	);
-	CREATE INDEX IF NOT EXISTS idx_links_to ON memory_links(to_id);
-
-	CREATE VIRTUAL TABLE IF NOT EXISTS chunks_fts USING fts5(
-		text,
-		content=chunks,
-		content_rowid=rowid
-	);
-	`
-	_, err := s.db.Exec(schema)
-	if err != nil {
+	if err := s.MigrateTo(context.Background(), LatestSchemaVersion()); err != nil {
 		return err
 	}
 
-	// Schema upgrades for older databases
-	s.db.Exec(`ALTER TABLE memories ADD COLUMN expires_at TEXT`)
-	s.db.Exec(`ALTER TABLE chunks ADD COLUMN embedding TEXT`)
+	if err := s.migrateFTS(); err != nil {
+		return fmt.Errorf("fts: %w", err)
+	}
 
-	// FTS5 triggers for automatic sync
-	s.db.Exec(`CREATE TRIGGER IF NOT EXISTS chunks_ai AFTER INSERT ON chunks BEGIN
-		INSERT INTO chunks_fts(rowid, text) VALUES (new.rowid, new.text);
-	END`)
-	s.db.Exec(`CREATE TRIGGER IF NOT EXISTS chunks_ad AFTER DELETE ON chunks BEGIN
-		INSERT INTO chunks_fts(chunks_fts, rowid, text) VALUES('delete', old.rowid, old.text);
-	END`)
-	s.db.Exec(`CREATE TRIGGER IF NOT EXISTS chunks_au AFTER UPDATE ON chunks BEGIN
-		INSERT INTO chunks_fts(chunks_fts, rowid, text) VALUES('delete', old.rowid, old.text);
-		INSERT INTO chunks_fts(rowid, text) VALUES (new.rowid, new.text);
-	END`)
+	// FTS5 triggers for automatic sync. A missing trigger silently leaves
+	// search stale, so every failure is reported.
+	var errs []error
+	for _, stmt := range []string{
+		`CREATE TRIGGER IF NOT EXISTS chunks_ai AFTER INSERT ON chunks BEGIN
+			INSERT INTO chunks_fts(rowid, text) VALUES (new.rowid, new.text);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS chunks_ad AFTER DELETE ON chunks BEGIN
+			INSERT INTO chunks_fts(chunks_fts, rowid, text) VALUES('delete', old.rowid, old.text);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS chunks_au AFTER UPDATE ON chunks BEGIN
+			INSERT INTO chunks_fts(chunks_fts, rowid, text) VALUES('delete', old.rowid, old.text);
+			INSERT INTO chunks_fts(rowid, text) VALUES (new.rowid, new.text);
+		END`,
+	} {
+		if _, err := s.db.Exec(stmt); err != nil {
+			errs = append(errs, fmt.Errorf("fts trigger: %w", err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
 
 	// Backfill FTS for any existing chunks not yet indexed
-	s.db.Exec(`INSERT OR IGNORE INTO chunks_fts(rowid, text) SELECT rowid, text FROM chunks`)
+	if _, err := s.db.Exec(`INSERT OR IGNORE INTO chunks_fts(rowid, text) SELECT rowid, text FROM chunks`); err != nil {
+		return fmt.Errorf("fts backfill: %w", err)
+	}
 
 	return nil
 }
 
 func (s *SQLiteStore) Put(ctx context.Context, p PutParams) (*model.Memory, error) {
+	s.metrics.count("put")
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	chunks, err := s.prepareChunks(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dimensions of the existing vector index, if any
+	index, err := s.EmbedIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	mem, err := s.putTx(ctx, tx, p, chunks, &index)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return mem, nil
+}
+
+// embeddedChunk is one chunk of a put with its vector, nil if the chunk
+// was not embedded.
+type embeddedChunk struct {
+	chunker.ChunkResult
+	vec embedding.Vector
+}
+
+// prepareChunks chunks and embeds p's content. Callers run it before
+// opening the write transaction, so slow or rate-limited embedding calls
+// never hold the SQLite write lock.
+func (s *SQLiteStore) prepareChunks(ctx context.Context, p PutParams) ([]embeddedChunk, error) {
+	if err := s.checkPut(p); err != nil {
+		return nil, err
+	}
+	_, chunkr, err := chunkerFor(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []embeddedChunk
+	for _, c := range chunkr.Chunk(p.Content, s.chunkOptions(p)) {
+		ec := embeddedChunk{ChunkResult: c}
+		if s.embedder != nil {
+			// Embedding errors are skipped — FTS5 still works
+			if vec, err := embedding.EmbedDocument(ctx, s.embedder, c.Text); err == nil && len(vec) > 0 {
+				ec.vec = vec
+			}
+		}
+		chunks = append(chunks, ec)
+	}
+	return chunks, nil
+}
+
+// checkPut rejects a put to a disallowed namespace or with oversized content.
+func (s *SQLiteStore) checkPut(p PutParams) error {
+	if err := s.checkNamespace(p.NS); err != nil {
+		return err
+	}
+	if s.maxContent > 0 && len(p.Content) > s.maxContent {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte limit (set AGENT_MEMORY_MAX_CONTENT to raise it)",
+			ErrContentTooLarge, len(p.Content), s.maxContent)
+	}
+	return nil
+}
+
+// putTx stores one memory inside tx with chunks from prepareChunks(p).
+// index holds the vector index recorded so far and is updated if this put
+// records it.
+func (s *SQLiteStore) putTx(ctx context.Context, tx *sql.Tx, p PutParams, chunks []embeddedChunk, index **EmbedIndexInfo) (*model.Memory, error) {
+	if err := s.checkPut(p); err != nil {
+		return nil, err
+	}
 	now := time.Now().UTC()
 	id := s.newID()
 
 	kind := p.Kind
 	if kind == "" {
 		kind = "semantic"
+		if p.Append {
+			kind = "episodic"
+		}
+	}
+	if p.Append {
+		if kind != "episodic" {
+			return nil, fmt.Errorf("append is only supported for episodic memories, got kind %q", kind)
+		}
+		p.Key = p.Key + "/" + id
 	}
 	priority := p.Priority
 	if priority == "" {
 		priority = "normal"
 	}
+	actor := p.Actor
+	if actor == "" {
+		actor = s.actor
+	}
 
 	var tagsJSON *string
 	if len(p.Tags) > 0 {
@@ -158,10 +415,25 @@ func (s *SQLiteStore) Put(ctx context.Context, p PutParams) (*model.Memory, erro
 	}
 
 	var metaPtr *string
-	if p.Meta != "" {
+	if strings.TrimSpace(p.Meta) != "" {
+		if !p.MetaRaw {
+			meta, err := normalizeMeta(p.Meta)
+			if err != nil {
+				return nil, err
+			}
+			p.Meta = meta
+		}
 		metaPtr = &p.Meta
+	} else {
+		p.Meta = ""
+	}
+	if err := s.validateMeta(ctx, tx, p.NS, p.Meta); err != nil {
+		return nil, err
 	}
 
+	if p.TTL != "" && p.ExpiresAt != "" {
+		return nil, fmt.Errorf("ttl and expires_at are mutually exclusive")
+	}
 	var expiresAt *string
 	if p.TTL != "" {
 		d, err := parseTTL(p.TTL)
@@ -171,17 +443,22 @@ func (s *SQLiteStore) Put(ctx context.Context, p PutParams) (*model.Memory, erro
 		exp := now.Add(d).Format(time.RFC3339)
 		expiresAt = &exp
 	}
-
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
+	if p.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, p.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expires_at: %w", err)
+		}
+		if !t.After(now) && !p.AllowPast {
+			return nil, fmt.Errorf("expires_at %s is in the past", p.ExpiresAt)
+		}
+		exp := t.UTC().Format(time.RFC3339)
+		expiresAt = &exp
 	}
-	defer tx.Rollback()
 
 	// Check for existing latest version
 	var prevID string
 	var prevVersion int
-	err = tx.QueryRowContext(ctx,
+	err := tx.QueryRowContext(ctx,
 		`SELECT id, version FROM memories
 		 WHERE ns = ? AND key = ? AND deleted_at IS NULL
 		 ORDER BY version DESC LIMIT 1`, p.NS, p.Key).Scan(&prevID, &prevVersion)
@@ -193,42 +470,51 @@ func (s *SQLiteStore) Put(ctx context.Context, p PutParams) (*model.Memory, erro
 		supersedes = &prevID
 	}
 
+	stored, encoding, err := s.encodeContent(p.Content)
+	if err != nil {
+		return nil, err
+	}
 	_, err = tx.ExecContext(ctx,
-		`INSERT INTO memories (id, ns, key, content, kind, tags, version, supersedes, created_at, priority, access_count, meta, expires_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)`,
-		id, p.NS, p.Key, p.Content, kind, tagsJSON, version, supersedes,
-		now.Format(time.RFC3339), priority, metaPtr, expiresAt)
+		`INSERT INTO memories (id, ns, key, content, kind, tags, version, supersedes, created_at, priority, access_count, meta, expires_at, content_encoding, actor)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?)`,
+		id, p.NS, p.Key, stored, kind, tagsJSON, version, supersedes,
+		now.Format(time.RFC3339), priority, metaPtr, expiresAt, encoding, nullIfEmpty(actor))
 	if err != nil {
 		return nil, fmt.Errorf("insert memory: %w", err)
 	}
 
-	// Chunk the content
-	chunks := chunker.Chunk(p.Content, chunker.DefaultOptions())
 	for i, c := range chunks {
 		chunkID := s.newID()
 
-		// Generate embedding if provider is configured
 		var embeddingJSON *string
-		if s.embedder != nil {
-			vec, err := s.embedder.Embed(ctx, c.Text)
-			if err == nil && len(vec) > 0 {
-				b, _ := json.Marshal(vec)
-				str := string(b)
-				embeddingJSON = &str
+		if c.vec != nil {
+			if *index == nil {
+				*index = &EmbedIndexInfo{Model: s.embedder.Model(), Dims: len(c.vec)}
+				if err := setEmbedIndex(ctx, tx, *index); err != nil {
+					return nil, fmt.Errorf("record embed index: %w", err)
+				}
+			} else if err := s.checkDims(*index, len(c.vec)); err != nil {
+				return nil, err
 			}
-			// Silently skip embedding errors — FTS5 still works
+			b, _ := json.Marshal(c.vec)
+			str := string(b)
+			embeddingJSON = &str
 		}
 
+		text, err := s.encrypt(c.Text)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt chunk: %w", err)
+		}
 		_, err = tx.ExecContext(ctx,
 			`INSERT INTO chunks (id, memory_id, seq, text, start_line, end_line, embedding)
 			 VALUES (?, ?, ?, ?, ?, ?, ?)`,
-			chunkID, id, i, c.Text, c.StartLine, c.EndLine, embeddingJSON)
+			chunkID, id, i, text, c.StartLine, c.EndLine, embeddingJSON)
 		if err != nil {
 			return nil, fmt.Errorf("insert chunk: %w", err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := s.recordEvent(ctx, tx, EventPut, p.NS, p.Key, version, ""); err != nil {
 		return nil, err
 	}
 
@@ -243,6 +529,7 @@ func (s *SQLiteStore) Put(ctx context.Context, p PutParams) (*model.Memory, erro
 		CreatedAt:  now,
 		Priority:   priority,
 		Meta:       p.Meta,
+		Actor:      actor,
 		ChunkCount: len(chunks),
 	}
 	if expiresAt != nil {
@@ -257,32 +544,38 @@ func (s *SQLiteStore) Put(ctx context.Context, p PutParams) (*model.Memory, erro
 }
 
 func (s *SQLiteStore) Get(ctx context.Context, p GetParams) ([]model.Memory, error) {
+	s.metrics.count("get")
 	var query string
 	var args []interface{}
 
 	now := time.Now().UTC().Format(time.RFC3339)
+	expiry := `AND (expires_at IS NULL OR expires_at > ?)`
+	expiryArgs := []interface{}{now}
+	if p.IncludeExpired {
+		expiry, expiryArgs = "", nil
+	}
 
 	if p.History {
 		// History shows all versions including expired (for audit)
 		query = `SELECT id, ns, key, content, kind, tags, version, supersedes,
-				        created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at
+				        created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor
 				 FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL
 				 ORDER BY version DESC`
 		args = []interface{}{p.NS, p.Key}
 	} else if p.Version > 0 {
 		query = `SELECT id, ns, key, content, kind, tags, version, supersedes,
-				        created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at
+				        created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor
 				 FROM memories WHERE ns = ? AND key = ? AND version = ? AND deleted_at IS NULL
-				   AND (expires_at IS NULL OR expires_at > ?)
+				   ` + expiry + `
 				 LIMIT 1`
-		args = []interface{}{p.NS, p.Key, p.Version, now}
+		args = append([]interface{}{p.NS, p.Key, p.Version}, expiryArgs...)
 	} else {
 		query = `SELECT id, ns, key, content, kind, tags, version, supersedes,
-				        created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at
+				        created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor
 				 FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL
-				   AND (expires_at IS NULL OR expires_at > ?)
+				   ` + expiry + `
 				 ORDER BY version DESC LIMIT 1`
-		args = []interface{}{p.NS, p.Key, now}
+		args = append([]interface{}{p.NS, p.Key}, expiryArgs...)
 	}
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
@@ -293,19 +586,20 @@ func (s *SQLiteStore) Get(ctx context.Context, p GetParams) ([]model.Memory, err
 
 	var memories []model.Memory
 	for rows.Next() {
-		m, err := scanMemory(rows)
+		m, err := s.scanMemory(rows)
 		if err != nil {
 			return nil, err
 		}
 		memories = append(memories, m)
 	}
+	rows.Close()
 
 	if len(memories) == 0 {
-		return nil, fmt.Errorf("memory not found: %s/%s", p.NS, p.Key)
+		return s.getMiss(ctx, p)
 	}
 
-	// Update access tracking for the latest
-	if !p.History {
+	// Update access tracking for the latest; audit reads are not accesses
+	if !p.History && !p.IncludeExpired && !s.readOnly {
 		now := time.Now().UTC().Format(time.RFC3339)
 		s.db.ExecContext(ctx,
 			`UPDATE memories SET access_count = access_count + 1, last_accessed_at = ? WHERE id = ?`,
@@ -315,6 +609,39 @@ func (s *SQLiteStore) Get(ctx context.Context, p GetParams) ([]model.Memory, err
 	return memories, nil
 }
 
+// getMiss handles a Get that found nothing: with p.Fuzzy it retries with the
+// closest key, otherwise it fails, suggesting close keys if there are any.
+func (s *SQLiteStore) getMiss(ctx context.Context, p GetParams) ([]model.Memory, error) {
+	similar, err := s.similarKeys(ctx, p.NS, p.Key)
+	if err != nil {
+		return nil, err
+	}
+	if p.Fuzzy && len(similar) > 0 {
+		p.Key, p.Fuzzy = similar[0], false
+		return s.Get(ctx, p)
+	}
+	if len(similar) > 0 {
+		return nil, fmt.Errorf("memory not found: %s/%s (did you mean: %s?)", p.NS, p.Key, strings.Join(similar, ", "))
+	}
+	return nil, fmt.Errorf("memory not found: %s/%s", p.NS, p.Key)
+}
+
+// GetByID returns the memory row with the given id, whatever its version,
+// deleted, or expired state. Access tracking is not updated.
+func (s *SQLiteStore) GetByID(ctx context.Context, id string) (*model.Memory, error) {
+	m, err := s.scanMemory(s.db.QueryRowContext(ctx,
+		`SELECT id, ns, key, content, kind, tags, version, supersedes,
+		        created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor
+		 FROM memories WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("memory not found: id %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
 func (s *SQLiteStore) List(ctx context.Context, p ListParams) ([]model.Memory, error) {
 	limit := p.Limit
 	if limit <= 0 {
@@ -323,8 +650,18 @@ func (s *SQLiteStore) List(ctx context.Context, p ListParams) ([]model.Memory, e
 
 	// Build a query that returns only the latest version of each ns+key
 	now := time.Now().UTC().Format(time.RFC3339)
-	where := []string{"m.deleted_at IS NULL", "(m.expires_at IS NULL OR m.expires_at > ?)"}
-	args := []interface{}{now}
+	var where []string
+	var args []interface{}
+	switch {
+	case p.OnlyDeleted:
+		where = []string{"m.deleted_at IS NOT NULL"}
+	case p.IncludeDeleted:
+		where = []string{"(m.expires_at IS NULL OR m.expires_at > ?)"}
+		args = []interface{}{now}
+	default:
+		where = []string{"m.deleted_at IS NULL", "(m.expires_at IS NULL OR m.expires_at > ?)"}
+		args = []interface{}{now}
+	}
 
 	if p.NS != "" {
 		where = append(where, "m.ns = ?")
@@ -334,6 +671,10 @@ func (s *SQLiteStore) List(ctx context.Context, p ListParams) ([]model.Memory, e
 		where = append(where, "m.kind = ?")
 		args = append(args, p.Kind)
 	}
+	if p.Actor != "" {
+		where = append(where, "m.actor = ?")
+		args = append(args, p.Actor)
+	}
 
 	// Tag filtering
 	for _, tag := range p.Tags {
@@ -341,18 +682,48 @@ func (s *SQLiteStore) List(ctx context.Context, p ListParams) ([]model.Memory, e
 		args = append(args, "%\""+tag+"\"%")
 	}
 
-	query := fmt.Sprintf(`
+	metaKeys := make([]string, 0, len(p.MetaFilter))
+	for k := range p.MetaFilter {
+		metaKeys = append(metaKeys, k)
+	}
+	sort.Strings(metaKeys)
+	for _, k := range metaKeys {
+		path, err := metaPath(k)
+		if err != nil {
+			return nil, err
+		}
+		// Rows with malformed meta never match rather than failing the query.
+		where = append(where, "CAST(CASE WHEN json_valid(m.meta) THEN json_extract(m.meta, ?) END AS TEXT) = ?")
+		args = append(args, path, p.MetaFilter[k])
+	}
+
+	var query string
+	if p.OnlyDeleted {
+		query = fmt.Sprintf(`
 		SELECT m.id, m.ns, m.key, m.content, m.kind, m.tags, m.version, m.supersedes,
-		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at
+		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at, m.content_encoding, m.actor
+		FROM memories m
+		WHERE %s
+		ORDER BY m.deleted_at DESC, m.version DESC
+		LIMIT ?`, strings.Join(where, " AND "))
+	} else {
+		latestWhere := "WHERE deleted_at IS NULL"
+		if p.IncludeDeleted {
+			latestWhere = ""
+		}
+		query = fmt.Sprintf(`
+		SELECT m.id, m.ns, m.key, m.content, m.kind, m.tags, m.version, m.supersedes,
+		       m.created_at, m.deleted_at, m.priority, m.access_count, m.last_accessed_at, m.meta, m.expires_at, m.content_encoding, m.actor
 		FROM memories m
 		INNER JOIN (
 			SELECT ns, key, MAX(version) AS max_ver
-			FROM memories WHERE deleted_at IS NULL
+			FROM memories %s
 			GROUP BY ns, key
 		) latest ON m.ns = latest.ns AND m.key = latest.key AND m.version = latest.max_ver
 		WHERE %s
 		ORDER BY m.created_at DESC
-		LIMIT ?`, strings.Join(where, " AND "))
+		LIMIT ?`, latestWhere, strings.Join(where, " AND "))
+	}
 	args = append(args, limit)
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
@@ -363,7 +734,7 @@ func (s *SQLiteStore) List(ctx context.Context, p ListParams) ([]model.Memory, e
 
 	var memories []model.Memory
 	for rows.Next() {
-		m, err := scanMemory(rows)
+		m, err := s.scanMemory(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -374,49 +745,202 @@ func (s *SQLiteStore) List(ctx context.Context, p ListParams) ([]model.Memory, e
 }
 
 func (s *SQLiteStore) Rm(ctx context.Context, p RmParams) error {
-	if p.Hard {
-		if p.AllVersions {
-			// Delete chunks first
-			_, err := s.db.ExecContext(ctx,
-				`DELETE FROM chunks WHERE memory_id IN (SELECT id FROM memories WHERE ns = ? AND key = ?)`,
-				p.NS, p.Key)
-			if err != nil {
-				return err
-			}
-			_, err = s.db.ExecContext(ctx, `DELETE FROM memories WHERE ns = ? AND key = ?`, p.NS, p.Key)
-			return err
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.rmTx(ctx, tx, p); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MatchKeys returns the keys in p.NS matching the GLOB pattern p.Glob that
+// RmGlob with the same params would delete, sorted.
+func (s *SQLiteStore) MatchKeys(ctx context.Context, p RmParams) ([]string, error) {
+	return matchKeys(ctx, s.db, p)
+}
+
+// RmGlob deletes every key in p.NS matching the GLOB pattern p.Glob (e.g.
+// "tmp-*"), applying AllVersions, Hard, and Cascade to each, in a single
+// transaction: if any key fails (e.g. a hard delete blocked by links),
+// nothing is deleted. It returns the deleted keys, sorted.
+func (s *SQLiteStore) RmGlob(ctx context.Context, p RmParams) ([]string, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	keys, err := matchKeys(ctx, tx, p)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		kp := p
+		kp.Key = key
+		if err := s.rmTx(ctx, tx, kp); err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", p.NS, key, err)
 		}
-		// Hard delete latest only
-		var id string
-		err := s.db.QueryRowContext(ctx,
-			`SELECT id FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL ORDER BY version DESC LIMIT 1`,
-			p.NS, p.Key).Scan(&id)
-		if err != nil {
-			return fmt.Errorf("memory not found: %s/%s", p.NS, p.Key)
+	}
+	return keys, tx.Commit()
+}
+
+// querier is satisfied by *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func matchKeys(ctx context.Context, q querier, p RmParams) ([]string, error) {
+	if p.NS == "" || p.Glob == "" {
+		return nil, fmt.Errorf("namespace and glob are required")
+	}
+	query := `SELECT DISTINCT key FROM memories WHERE ns = ? AND key GLOB ?`
+	if !(p.Hard && p.AllVersions) {
+		// Otherwise only keys with a live version have anything to delete
+		query += ` AND deleted_at IS NULL`
+	}
+	rows, err := q.QueryContext(ctx, query+` ORDER BY key`, p.NS, p.Glob)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
 		}
-		s.db.ExecContext(ctx, `DELETE FROM chunks WHERE memory_id = ?`, id)
-		_, err = s.db.ExecContext(ctx, `DELETE FROM memories WHERE id = ?`, id)
-		return err
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// rmTx soft- or hard-deletes one memory inside tx.
+func (s *SQLiteStore) rmTx(ctx context.Context, tx *sql.Tx, p RmParams) error {
+	if p.Hard {
+		return s.hardDelete(ctx, tx, p)
 	}
 
 	now := time.Now().UTC().Format(time.RFC3339)
 	if p.AllVersions {
-		_, err := s.db.ExecContext(ctx,
+		res, err := tx.ExecContext(ctx,
 			`UPDATE memories SET deleted_at = ? WHERE ns = ? AND key = ? AND deleted_at IS NULL`,
 			now, p.NS, p.Key)
-		return err
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			return s.recordEvent(ctx, tx, EventRm, p.NS, p.Key, 0, "all versions")
+		}
+		return nil
 	}
 
 	// Soft-delete latest version only
 	var id string
-	err := s.db.QueryRowContext(ctx,
-		`SELECT id FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL ORDER BY version DESC LIMIT 1`,
-		p.NS, p.Key).Scan(&id)
+	var version int
+	err := tx.QueryRowContext(ctx,
+		`SELECT id, version FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL ORDER BY version DESC LIMIT 1`,
+		p.NS, p.Key).Scan(&id, &version)
 	if err != nil {
 		return fmt.Errorf("memory not found: %s/%s", p.NS, p.Key)
 	}
-	_, err = s.db.ExecContext(ctx, `UPDATE memories SET deleted_at = ? WHERE id = ?`, now, id)
-	return err
+	if _, err := tx.ExecContext(ctx, `UPDATE memories SET deleted_at = ? WHERE id = ?`, now, id); err != nil {
+		return err
+	}
+	return s.recordEvent(ctx, tx, EventRm, p.NS, p.Key, version, "")
+}
+
+// ErrHasLinks is returned by a hard delete of a memory that links still
+// reference, unless Cascade is set.
+var ErrHasLinks = errors.New("memory has links")
+
+// hardDelete permanently removes the latest (or every) version of a memory
+// with its chunks, inside tx. Links to or from those rows block the delete unless
+// p.Cascade is set, in which case they are removed too.
+func (s *SQLiteStore) hardDelete(ctx context.Context, tx *sql.Tx, p RmParams) error {
+	var ids []interface{}
+	version := 0
+	if p.AllVersions {
+		rows, err := tx.QueryContext(ctx, `SELECT id FROM memories WHERE ns = ? AND key = ?`, p.NS, p.Key)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if len(ids) == 0 {
+			return nil
+		}
+	} else {
+		var id string
+		err := tx.QueryRowContext(ctx,
+			`SELECT id, version FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NULL ORDER BY version DESC LIMIT 1`,
+			p.NS, p.Key).Scan(&id, &version)
+		if err != nil {
+			return fmt.Errorf("memory not found: %s/%s", p.NS, p.Key)
+		}
+		ids = append(ids, id)
+	}
+	in := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	both := append(append([]interface{}{}, ids...), ids...)
+
+	if !p.Cascade {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT f.ns, f.key, l.rel, t.ns, t.key
+			FROM memory_links l
+			INNER JOIN memories f ON f.id = l.from_id
+			INNER JOIN memories t ON t.id = l.to_id
+			WHERE l.from_id IN (`+in+`) OR l.to_id IN (`+in+`)`, both...)
+		if err != nil {
+			return err
+		}
+		var links []string
+		for rows.Next() {
+			var fns, fkey, rel, tns, tkey string
+			if err := rows.Scan(&fns, &fkey, &rel, &tns, &tkey); err != nil {
+				rows.Close()
+				return err
+			}
+			links = append(links, fmt.Sprintf("%s/%s --%s--> %s/%s", fns, fkey, rel, tns, tkey))
+		}
+		rows.Close()
+		if len(links) > 0 {
+			return fmt.Errorf("%w (%s); use --cascade to delete them too", ErrHasLinks, strings.Join(links, ", "))
+		}
+	} else if _, err := tx.ExecContext(ctx,
+		`DELETE FROM memory_links WHERE from_id IN (`+in+`) OR to_id IN (`+in+`)`, both...); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM chunks WHERE memory_id IN (`+in+`)`, ids...); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM memories WHERE id IN (`+in+`)`, ids...); err != nil {
+		return err
+	}
+	detail := "hard"
+	if p.AllVersions {
+		detail = "hard, all versions"
+	}
+	return s.recordEvent(ctx, tx, EventRm, p.NS, p.Key, version, detail)
 }
 
 func (s *SQLiteStore) Close() error {
@@ -427,15 +951,15 @@ type scanner interface {
 	Scan(dest ...interface{}) error
 }
 
-func scanMemory(row scanner) (model.Memory, error) {
+func (s *SQLiteStore) scanMemory(row scanner) (model.Memory, error) {
 	var m model.Memory
-	var tagsJSON, supersedes, deletedAt, lastAccessed, meta, expiresAt sql.NullString
+	var tagsJSON, supersedes, deletedAt, lastAccessed, meta, expiresAt, encoding, actor sql.NullString
 	var createdAt string
 
 	err := row.Scan(
 		&m.ID, &m.NS, &m.Key, &m.Content, &m.Kind, &tagsJSON,
 		&m.Version, &supersedes, &createdAt, &deletedAt,
-		&m.Priority, &m.AccessCount, &lastAccessed, &meta, &expiresAt,
+		&m.Priority, &m.AccessCount, &lastAccessed, &meta, &expiresAt, &encoding, &actor,
 	)
 	if err != nil {
 		return m, err
@@ -463,13 +987,47 @@ func scanMemory(row scanner) (model.Memory, error) {
 		t, _ := time.Parse(time.RFC3339, expiresAt.String)
 		m.ExpiresAt = &t
 	}
+	m.Actor = actor.String
+
+	m.Content, err = s.decodeContent(m.Content, encoding)
+	return m, err
+}
+
+// normalizeMeta validates meta as JSON and returns it compacted, keeping
+// the original key order.
+func normalizeMeta(meta string) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(meta)); err != nil {
+		return "", fmt.Errorf("invalid meta JSON (use --meta-raw to store it verbatim): %w", err)
+	}
+	return buf.String(), nil
+}
 
-	return m, nil
+// metaPath converts a dotted meta key like "author.name" into a JSON path
+// for json_extract, quoting each segment.
+func metaPath(key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("invalid meta filter: empty key")
+	}
+	var b strings.Builder
+	b.WriteString("$")
+	for _, seg := range strings.Split(key, ".") {
+		if seg == "" || strings.ContainsAny(seg, `"\`) {
+			return "", fmt.Errorf("invalid meta filter key %q", key)
+		}
+		b.WriteString(`."` + seg + `"`)
+	}
+	return b.String(), nil
 }
 
 // parseTTL parses a TTL string like "7d", "24h", "30m" into a time.Duration.
 var ttlRegex = regexp.MustCompile(`^(\d+)([dhms])$`)
 
+// ParseTTL parses a duration in TTL syntax ("7d", "24h", "30m", "60s").
+func ParseTTL(s string) (time.Duration, error) {
+	return parseTTL(s)
+}
+
 func parseTTL(s string) (time.Duration, error) {
 	m := ttlRegex.FindStringSubmatch(s)
 	if m == nil {
@@ -488,3 +1046,39 @@ func parseTTL(s string) (time.Duration, error) {
 	}
 	return 0, fmt.Errorf("unknown unit %q", m[2])
 }
+
+// chunkerFor resolves the chunking format for a Put, detecting it from the
+// content when p.Format is empty.
+func chunkerFor(p PutParams) (string, chunker.Chunker, error) {
+	format := p.Format
+	if format == "" {
+		format = chunker.Detect(p.Content)
+	}
+	c, err := chunker.ForFormat(format)
+	return format, c, err
+}
+
+// chunkOptions builds chunker options for a Put from its params and the
+// store defaults.
+func (s *SQLiteStore) chunkOptions(p PutParams) chunker.Options {
+	opts := s.chunkOpts
+	if p.ChunkTargetSize > 0 {
+		// Keep the default min:target:max proportions (1:4:6)
+		opts.TargetSize = p.ChunkTargetSize
+		opts.MinSize = p.ChunkTargetSize / 4
+		opts.MaxSize = p.ChunkTargetSize * 3 / 2
+	}
+	if p.ChunkMinSize > 0 {
+		opts.MinSize = p.ChunkMinSize
+	}
+	if p.ChunkMaxSize > 0 {
+		opts.MaxSize = p.ChunkMaxSize
+	}
+	if opts.MaxSize < opts.TargetSize {
+		opts.MaxSize = opts.TargetSize
+	}
+	if p.ChunkOverlap > 0 {
+		opts.Overlap = p.ChunkOverlap
+	}
+	return opts
+}
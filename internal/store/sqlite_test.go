@@ -2,8 +2,12 @@ package store
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -138,6 +142,36 @@ func TestSoftDelete(t *testing.T) {
 	}
 }
 
+func TestListDeleted(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "keep", Content: "alive"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "gone", Content: "removed"})
+	s.Rm(ctx, RmParams{NS: "ns", Key: "gone"})
+
+	deleted, err := s.List(ctx, ListParams{NS: "ns", OnlyDeleted: true})
+	if err != nil {
+		t.Fatalf("list deleted: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].Key != "gone" {
+		t.Fatalf("expected only 'gone', got %+v", deleted)
+	}
+	if deleted[0].DeletedAt == nil {
+		t.Error("expected deleted_at to be set")
+	}
+
+	all, _ := s.List(ctx, ListParams{NS: "ns", IncludeDeleted: true})
+	if len(all) != 2 {
+		t.Errorf("expected 2 with include-deleted, got %d", len(all))
+	}
+
+	active, _ := s.List(ctx, ListParams{NS: "ns"})
+	if len(active) != 1 || active[0].Key != "keep" {
+		t.Errorf("expected only 'keep' by default, got %+v", active)
+	}
+}
+
 func TestHardDelete(t *testing.T) {
 	ctx := context.Background()
 	s := newTestStore(t)
@@ -202,6 +236,112 @@ func TestDBPathCreation(t *testing.T) {
 	}
 }
 
+func TestReadOnlyStore(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	rw, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "shared data"})
+
+	ro, err := NewReadOnlyStore(dbPath)
+	if err != nil {
+		t.Fatalf("open read-only: %v", err)
+	}
+	defer ro.Close()
+	defer rw.Close()
+
+	got, err := ro.Get(ctx, GetParams{NS: "ns", Key: "k"})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got[0].Content != "shared data" {
+		t.Errorf("expected 'shared data', got %q", got[0].Content)
+	}
+	if results, err := ro.Search(ctx, SearchParams{Query: "shared"}); err != nil || len(results) != 1 {
+		t.Errorf("expected 1 search result, got %d (err %v)", len(results), err)
+	}
+	if _, err := ro.Stats(ctx, StatsParams{DBPath: dbPath}); err != nil {
+		t.Errorf("stats: %v", err)
+	}
+
+	// No access tracking in read-only mode
+	again, _ := rw.Get(ctx, GetParams{NS: "ns", Key: "k"})
+	if again[0].AccessCount != 0 {
+		t.Errorf("expected access_count 0 after read-only get, got %d", again[0].AccessCount)
+	}
+
+	if _, err := ro.Put(ctx, PutParams{NS: "ns", Key: "x", Content: "nope"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from put, got %v", err)
+	}
+	if err := ro.Rm(ctx, RmParams{NS: "ns", Key: "k"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly from rm, got %v", err)
+	}
+}
+
+func TestReadOnlyStoreMissingFile(t *testing.T) {
+	if _, err := NewReadOnlyStore(filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Fatal("expected error opening missing db read-only")
+	}
+}
+
+func TestConcurrentPutGet(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Two stores on one file simulate separate processes
+	s1, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s1.Close()
+	s2, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s2.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for w := 0; w < 8; w++ {
+		s := s1
+		if w%2 == 1 {
+			s = s2
+		}
+		wg.Add(1)
+		go func(w int, s *SQLiteStore) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				key := fmt.Sprintf("k%d", i%3)
+				if _, err := s.Put(ctx, PutParams{NS: "ns", Key: key, Content: fmt.Sprintf("w%d-%d", w, i)}); err != nil {
+					errs <- fmt.Errorf("put: %w", err)
+					return
+				}
+				if _, err := s.Get(ctx, GetParams{NS: "ns", Key: key}); err != nil {
+					errs <- fmt.Errorf("get: %w", err)
+					return
+				}
+			}
+		}(w, s)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	hist, _ := s1.Get(ctx, GetParams{NS: "ns", Key: "k0", History: true})
+	seen := map[int]bool{}
+	for _, m := range hist {
+		if seen[m.Version] {
+			t.Errorf("duplicate version %d", m.Version)
+		}
+		seen[m.Version] = true
+	}
+}
+
 func TestPutWithPriorityAndKind(t *testing.T) {
 	ctx := context.Background()
 	s := newTestStore(t)
@@ -222,3 +362,350 @@ func TestPutWithPriorityAndKind(t *testing.T) {
 		t.Error("kind/priority not persisted correctly")
 	}
 }
+
+func TestPutChunkOverlap(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	var sections []string
+	for i := 0; i < 6; i++ {
+		sections = append(sections, "## Part\n"+strings.Repeat("lorem ipsum dolor sit amet ", 12))
+	}
+	content := strings.Join(sections, "\n\n\n")
+
+	countChunkChars := func(key string) int {
+		var n int
+		if err := s.db.QueryRow(`SELECT SUM(LENGTH(c.text)) FROM chunks c
+			JOIN memories m ON m.id = c.memory_id WHERE m.key = ?`, key).Scan(&n); err != nil {
+			t.Fatalf("count chunks: %v", err)
+		}
+		return n
+	}
+
+	s.Put(ctx, PutParams{NS: "test", Key: "plain", Content: content})
+	s.Put(ctx, PutParams{NS: "test", Key: "overlap", Content: content, ChunkOverlap: 50})
+
+	if countChunkChars("overlap") <= countChunkChars("plain") {
+		t.Error("expected overlapping chunks to repeat text")
+	}
+}
+
+func TestChunkOverlapEnv(t *testing.T) {
+	t.Setenv("AGENT_MEMORY_CHUNK_OVERLAP", "-1")
+	if _, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db")); err == nil {
+		t.Error("expected error for negative overlap")
+	}
+}
+
+func TestPutChunkSize(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	var paras []string
+	for i := 0; i < 8; i++ {
+		paras = append(paras, strings.Repeat("runbook step details and notes ", 6))
+	}
+	content := strings.Join(paras, "\n\n\n")
+
+	countChunks := func(key string) int {
+		var n int
+		if err := s.db.QueryRow(`SELECT COUNT(*) FROM chunks c
+			JOIN memories m ON m.id = c.memory_id WHERE m.key = ?`, key).Scan(&n); err != nil {
+			t.Fatalf("count chunks: %v", err)
+		}
+		return n
+	}
+
+	s.Put(ctx, PutParams{NS: "test", Key: "default", Content: content})
+	s.Put(ctx, PutParams{NS: "test", Key: "tiny", Content: content, ChunkTargetSize: 100})
+
+	if d, tiny := countChunks("default"), countChunks("tiny"); tiny <= d {
+		t.Errorf("expected tiny target to produce more chunks: default=%d tiny=%d", d, tiny)
+	}
+}
+
+func TestPutFormat(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.Put(ctx, PutParams{NS: "test", Key: "bad", Content: "x", Format: "xml"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+
+	content := strings.Repeat("abcdefghij", 100)
+	if _, err := s.Put(ctx, PutParams{NS: "test", Key: "plain", Content: content, Format: "plain"}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	var n int
+	s.db.QueryRow(`SELECT COUNT(*) FROM chunks c JOIN memories m ON m.id = c.memory_id WHERE m.key = 'plain'`).Scan(&n)
+	if n < 2 {
+		t.Errorf("expected plain chunker to window unstructured text, got %d chunks", n)
+	}
+}
+
+func TestPutAppend(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	a, err := s.Put(ctx, PutParams{NS: "log", Key: "deploy", Content: "deployed v1", Append: true})
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	b, err := s.Put(ctx, PutParams{NS: "log", Key: "deploy", Content: "deployed v2", Append: true})
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if a.Key == b.Key {
+		t.Fatalf("expected distinct keys, both %q", a.Key)
+	}
+	if a.Key != "deploy/"+a.ID || a.Kind != "episodic" || a.Version != 1 || b.Version != 1 {
+		t.Errorf("unexpected appended memory: key=%q kind=%q version=%d", a.Key, a.Kind, a.Version)
+	}
+	if !strings.HasPrefix(b.Key, "deploy/") {
+		t.Errorf("expected key under deploy/, got %q", b.Key)
+	}
+
+	if _, err := s.Put(ctx, PutParams{NS: "log", Key: "deploy", Content: "x", Kind: "semantic", Append: true}); err == nil {
+		t.Error("expected append with non-episodic kind to fail")
+	}
+}
+
+func TestNewSQLiteStoreBadPaths(t *testing.T) {
+	dir := t.TempDir()
+
+	// Path is an existing directory
+	_, err := NewSQLiteStore(dir)
+	if err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Errorf("expected directory error, got %v", err)
+	}
+
+	// Parent of the path is a regular file
+	file := filepath.Join(dir, "notadir")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewSQLiteStore(filepath.Join(file, "memory.db"))
+	if err == nil || !strings.Contains(err.Error(), "is a file, not a directory") {
+		t.Errorf("expected parent-is-file error, got %v", err)
+	}
+}
+
+func TestListKeys(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "one"})
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "two"})
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "three"})
+	s.Put(ctx, PutParams{NS: "test", Key: "gone", Content: "four"})
+	s.Put(ctx, PutParams{NS: "other", Key: "c", Content: "five"})
+	s.Rm(ctx, RmParams{NS: "test", Key: "gone"})
+
+	got, err := s.ListKeys(ctx, "test")
+	if err != nil {
+		t.Fatalf("list keys: %v", err)
+	}
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected [a b], got %v", got)
+	}
+}
+
+func TestPutMaxContent(t *testing.T) {
+	t.Setenv("AGENT_MEMORY_MAX_CONTENT", "100")
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	if _, err := s.Put(ctx, PutParams{NS: "test", Key: "ok", Content: strings.Repeat("a", 100)}); err != nil {
+		t.Fatalf("expected content at the limit to be accepted: %v", err)
+	}
+	_, err := s.Put(ctx, PutParams{NS: "test", Key: "big", Content: strings.Repeat("a", 101)})
+	if !errors.Is(err, ErrContentTooLarge) {
+		t.Errorf("expected ErrContentTooLarge, got %v", err)
+	}
+
+	t.Setenv("AGENT_MEMORY_MAX_CONTENT", "nope")
+	if _, err := NewSQLiteStore(filepath.Join(t.TempDir(), "bad.db")); err == nil {
+		t.Error("expected invalid AGENT_MEMORY_MAX_CONTENT to fail")
+	}
+}
+
+func TestListMetaFilter(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "one", Meta: `{"source":"slack","author":{"name":"rick"}}`})
+	s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "two", Meta: `{"source":"email","pages":3}`})
+	s.Put(ctx, PutParams{NS: "test", Key: "c", Content: "three"})
+	s.db.Exec(`UPDATE memories SET meta = 'not json' WHERE key = 'c'`)
+
+	got, err := s.List(ctx, ListParams{NS: "test", MetaFilter: map[string]string{"source": "slack"}})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 1 || got[0].Key != "a" {
+		t.Errorf("expected only a, got %v", keys(got))
+	}
+
+	got, _ = s.List(ctx, ListParams{NS: "test", MetaFilter: map[string]string{"author.name": "rick", "source": "slack"}})
+	if len(got) != 1 || got[0].Key != "a" {
+		t.Errorf("expected nested match on a, got %v", keys(got))
+	}
+
+	got, _ = s.List(ctx, ListParams{NS: "test", MetaFilter: map[string]string{"pages": "3"}})
+	if len(got) != 1 || got[0].Key != "b" {
+		t.Errorf("expected numeric match on b, got %v", keys(got))
+	}
+
+	if _, err := s.List(ctx, ListParams{MetaFilter: map[string]string{`bad"key`: "x"}}); err == nil {
+		t.Error("expected error for quoted meta key")
+	}
+}
+
+func TestPutMeta(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	m, err := s.Put(ctx, PutParams{NS: "test", Key: "obj", Content: "x", Meta: "{ \"b\": 1,\n  \"a\": [1, 2] }"})
+	if err != nil {
+		t.Fatalf("valid object: %v", err)
+	}
+	if m.Meta != `{"b":1,"a":[1,2]}` {
+		t.Errorf("expected compacted meta in original key order, got %q", m.Meta)
+	}
+	got, _ := s.Get(ctx, GetParams{NS: "test", Key: "obj"})
+	if got[0].Meta != m.Meta {
+		t.Errorf("expected stored meta %q, got %q", m.Meta, got[0].Meta)
+	}
+
+	if _, err := s.Put(ctx, PutParams{NS: "test", Key: "bad", Content: "x", Meta: "not json"}); err == nil {
+		t.Error("expected invalid meta JSON to be rejected")
+	}
+
+	m, err = s.Put(ctx, PutParams{NS: "test", Key: "raw", Content: "x", Meta: "not json", MetaRaw: true})
+	if err != nil {
+		t.Fatalf("raw meta: %v", err)
+	}
+	if m.Meta != "not json" {
+		t.Errorf("expected raw meta stored verbatim, got %q", m.Meta)
+	}
+
+	m, err = s.Put(ctx, PutParams{NS: "test", Key: "empty", Content: "x", Meta: "  "})
+	if err != nil {
+		t.Fatalf("empty meta: %v", err)
+	}
+	var stored *string
+	s.db.QueryRow(`SELECT meta FROM memories WHERE key = 'empty'`).Scan(&stored)
+	if m.Meta != "" || stored != nil {
+		t.Errorf("expected blank meta stored as NULL, got %q / %v", m.Meta, stored)
+	}
+}
+
+func TestPutActor(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "test", Key: "anon", Content: "x"})
+	s.SetActor("planner")
+	m, err := s.Put(ctx, PutParams{NS: "test", Key: "a", Content: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Actor != "planner" {
+		t.Fatalf("expected actor from SetActor, got %q", m.Actor)
+	}
+	m, _ = s.Put(ctx, PutParams{NS: "test", Key: "b", Content: "x", Actor: "importer"})
+	if m.Actor != "importer" {
+		t.Errorf("expected explicit actor to win, got %q", m.Actor)
+	}
+
+	got, _ := s.Get(ctx, GetParams{NS: "test", Key: "a"})
+	if got[0].Actor != "planner" {
+		t.Errorf("expected stored actor, got %q", got[0].Actor)
+	}
+	got, _ = s.Get(ctx, GetParams{NS: "test", Key: "anon"})
+	if got[0].Actor != "" {
+		t.Errorf("expected no actor, got %q", got[0].Actor)
+	}
+
+	list, _ := s.List(ctx, ListParams{NS: "test", Actor: "planner"})
+	if len(list) != 1 || list[0].Key != "a" {
+		t.Errorf("expected only key a for actor planner, got %+v", list)
+	}
+}
+
+func TestGetByID(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	v1, _ := s.Put(ctx, PutParams{NS: "test", Key: "k", Content: "one"})
+	s.Put(ctx, PutParams{NS: "test", Key: "k", Content: "two"})
+	if err := s.Rm(ctx, RmParams{NS: "test", Key: "k", AllVersions: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := s.GetByID(ctx, v1.ID)
+	if err != nil {
+		t.Fatalf("get superseded, deleted version: %v", err)
+	}
+	if m.Version != 1 || m.Content != "one" || m.DeletedAt == nil {
+		t.Errorf("expected deleted v1, got %+v", m)
+	}
+	if m.AccessCount != 0 {
+		t.Errorf("expected no access tracking, got %d", m.AccessCount)
+	}
+
+	if _, err := s.GetByID(ctx, "01NOTAREALID"); err == nil || !strings.Contains(err.Error(), "01NOTAREALID") {
+		t.Errorf("expected not-found error naming the id, got %v", err)
+	}
+}
+
+func TestRmGlob(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	for _, k := range []string{"tmp-1", "tmp-2", "tmpx", "keep"} {
+		s.Put(ctx, PutParams{NS: "test", Key: k, Content: "x"})
+	}
+	s.Put(ctx, PutParams{NS: "other", Key: "tmp-3", Content: "x"})
+
+	p := RmParams{NS: "test", Glob: "tmp-*"}
+	keys, err := s.MatchKeys(ctx, p)
+	if err != nil || strings.Join(keys, ",") != "tmp-1,tmp-2" {
+		t.Fatalf("expected dry run to match tmp-1,tmp-2, got %v, %v", keys, err)
+	}
+
+	deleted, err := s.RmGlob(ctx, p)
+	if err != nil || strings.Join(deleted, ",") != "tmp-1,tmp-2" {
+		t.Fatalf("expected tmp-1,tmp-2 deleted, got %v, %v", deleted, err)
+	}
+	left, _ := s.ListKeys(ctx, "test")
+	if strings.Join(left, ",") != "keep,tmpx" {
+		t.Errorf("expected keep,tmpx left, got %v", left)
+	}
+	if other, _ := s.ListKeys(ctx, "other"); len(other) != 1 {
+		t.Errorf("expected other namespace untouched, got %v", other)
+	}
+
+	// A hard delete blocked by links deletes nothing.
+	s.Put(ctx, PutParams{NS: "test", Key: "tmp-4", Content: "x"})
+	s.Link(ctx, LinkParams{FromNS: "test", FromKey: "tmp-4", ToNS: "test", ToKey: "keep", Rel: "relates_to"})
+	_, err = s.RmGlob(ctx, RmParams{NS: "test", Glob: "tmp*", Hard: true})
+	if !errors.Is(err, ErrHasLinks) {
+		t.Fatalf("expected ErrHasLinks, got %v", err)
+	}
+	if left, _ := s.ListKeys(ctx, "test"); len(left) != 3 {
+		t.Errorf("expected nothing deleted after a failure, got %v", left)
+	}
+
+	// Hard delete of all versions also purges soft-deleted matches.
+	deleted, err = s.RmGlob(ctx, RmParams{NS: "test", Glob: "tmp*", Hard: true, AllVersions: true, Cascade: true})
+	if err != nil || strings.Join(deleted, ",") != "tmp-1,tmp-2,tmp-4,tmpx" {
+		t.Errorf("expected every tmp key purged, got %v, %v", deleted, err)
+	}
+	var rows int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memories WHERE ns = 'test'`).Scan(&rows)
+	if rows != 1 {
+		t.Errorf("expected only keep to remain, got %d rows", rows)
+	}
+}
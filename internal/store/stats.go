@@ -2,17 +2,36 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"math"
 	"os"
+	"time"
 )
 
+// StatsParams holds parameters for computing database statistics.
+type StatsParams struct {
+	DBPath      string
+	PerKind     bool // include a breakdown by kind
+	PerPriority bool // include a breakdown by priority
+}
+
 // Stats holds database statistics.
 type Stats struct {
-	DBPath         string           `json:"db_path"`
-	DBSizeBytes    int64            `json:"db_size_bytes"`
-	TotalMemories  int              `json:"total_memories"`
-	ActiveMemories int              `json:"active_memories"`
-	TotalChunks    int              `json:"total_chunks"`
-	Namespaces     []NamespaceStats `json:"namespaces"`
+	DBPath                string           `json:"db_path"`
+	DBSizeBytes           int64            `json:"db_size_bytes"`
+	TotalMemories         int              `json:"total_memories"`
+	ActiveMemories        int              `json:"active_memories"`
+	ExpiredMemories       int              `json:"expired_memories"`
+	DeletedMemories       int              `json:"deleted_memories"`
+	TotalChunks           int              `json:"total_chunks"`
+	AvgChunks             float64          `json:"avg_chunks_per_memory"`
+	ChunksWithEmbedding   int              `json:"chunks_with_embedding"`
+	MemoriesWithEmbedding int              `json:"memories_with_embedding"`
+	EmbedIndex            *EmbedIndexInfo  `json:"embed_index,omitempty"`
+	Namespaces            []NamespaceStats `json:"namespaces"`
+	Kinds                 map[string]int   `json:"kinds,omitempty"`
+	Priorities            map[string]int   `json:"priorities,omitempty"`
 }
 
 // NamespaceStats holds per-namespace counts.
@@ -23,17 +42,40 @@ type NamespaceStats struct {
 }
 
 // Stats returns database statistics.
-func (s *SQLiteStore) Stats(ctx context.Context, dbPath string) (*Stats, error) {
-	st := &Stats{DBPath: dbPath}
+func (s *SQLiteStore) Stats(ctx context.Context, p StatsParams) (*Stats, error) {
+	st := &Stats{DBPath: p.DBPath}
+	if st.DBPath == "" {
+		st.DBPath, _ = s.filePath(ctx)
+	}
 
-	// DB file size
-	if info, err := os.Stat(dbPath); err == nil {
-		st.DBSizeBytes = info.Size()
+	// DB file size (in-memory databases have no file and report 0)
+	if st.DBPath != "" {
+		if info, err := os.Stat(st.DBPath); err == nil {
+			st.DBSizeBytes = info.Size()
+		}
 	}
 
+	now := time.Now().UTC().Format(time.RFC3339)
 	s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM memories`).Scan(&st.TotalMemories)
 	s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM memories WHERE deleted_at IS NULL`).Scan(&st.ActiveMemories)
+	s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM memories WHERE deleted_at IS NULL AND expires_at IS NOT NULL AND expires_at <= ?`,
+		now).Scan(&st.ExpiredMemories)
+	s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM memories WHERE deleted_at IS NOT NULL`).Scan(&st.DeletedMemories)
 	s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM chunks`).Scan(&st.TotalChunks)
+	if st.TotalMemories > 0 {
+		st.AvgChunks = math.Round(float64(st.TotalChunks)/float64(st.TotalMemories)*100) / 100
+	}
+
+	// Embedding coverage
+	s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM chunks WHERE embedding IS NOT NULL`).Scan(&st.ChunksWithEmbedding)
+	s.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT c.memory_id) FROM chunks c
+		INNER JOIN memories m ON m.id = c.memory_id
+		WHERE c.embedding IS NOT NULL AND m.deleted_at IS NULL`).Scan(&st.MemoriesWithEmbedding)
+	if info, err := s.EmbedIndex(ctx); err == nil {
+		st.EmbedIndex = info
+	}
 
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT ns, COUNT(*) as cnt, COUNT(DISTINCT key) as keys
@@ -50,9 +92,46 @@ func (s *SQLiteStore) Stats(ctx context.Context, dbPath string) (*Stats, error)
 		st.Namespaces = append(st.Namespaces, ns)
 	}
 
+	if p.PerKind {
+		if st.Kinds, err = s.countBy(ctx, "kind", true); err != nil {
+			return st, err
+		}
+	}
+	if p.PerPriority {
+		if st.Priorities, err = s.countBy(ctx, "priority", true); err != nil {
+			return st, err
+		}
+	}
+
 	return st, nil
 }
 
+// countBy counts memories grouped by the given column, optionally only
+// those not soft-deleted. column must be a trusted identifier, never user input.
+func (s *SQLiteStore) countBy(ctx context.Context, column string, activeOnly bool) (map[string]int, error) {
+	where := ""
+	if activeOnly {
+		where = " WHERE deleted_at IS NULL"
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+column+`, COUNT(*) FROM memories`+where+` GROUP BY `+column)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var k string
+		var n int
+		if err := rows.Scan(&k, &n); err != nil {
+			return nil, err
+		}
+		counts[k] = n
+	}
+	return counts, rows.Err()
+}
+
 // ListNamespaces returns all namespaces with counts.
 func (s *SQLiteStore) ListNamespaces(ctx context.Context) ([]NamespaceStats, error) {
 	rows, err := s.db.QueryContext(ctx, `
@@ -72,3 +151,117 @@ func (s *SQLiteStore) ListNamespaces(ctx context.Context) ([]NamespaceStats, err
 	}
 	return result, nil
 }
+
+// ListKeys returns the distinct keys in a namespace whose latest version is
+// live (not deleted or expired), sorted.
+func (s *SQLiteStore) ListKeys(ctx context.Context, ns string) ([]string, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT m.key
+		FROM memories m
+		INNER JOIN (
+			SELECT ns, key, MAX(version) AS max_ver
+			FROM memories WHERE ns = ? AND deleted_at IS NULL
+			GROUP BY ns, key
+		) latest ON m.ns = latest.ns AND m.key = latest.key AND m.version = latest.max_ver
+		WHERE m.expires_at IS NULL OR m.expires_at > ?
+		ORDER BY m.key`, ns, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// namespaceTopTags is how many tags NamespaceDetail reports.
+const namespaceTopTags = 10
+
+// NamespaceDetail holds statistics for a single namespace.
+type NamespaceDetail struct {
+	NS           string     `json:"ns"`
+	Keys         int        `json:"keys"`          // keys with a live version
+	Memories     int        `json:"memories"`      // live versions
+	Versions     int        `json:"versions"`      // all versions, including deleted
+	Deleted      int        `json:"deleted"`       // soft-deleted versions
+	Chunks       int        `json:"chunks"`        // chunks of all versions
+	ContentBytes int64      `json:"content_bytes"` // stored size of live versions
+	Oldest       *time.Time `json:"oldest,omitempty"`
+	Newest       *time.Time `json:"newest,omitempty"`
+	TopTags      []TagCount `json:"top_tags,omitempty"`
+}
+
+// TagCount is how many memories carry a tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// NamespaceDetail returns statistics for ns. Tags are counted over the
+// latest live version of each key.
+func (s *SQLiteStore) NamespaceDetail(ctx context.Context, ns string) (*NamespaceDetail, error) {
+	d := &NamespaceDetail{NS: ns}
+	var oldest, newest sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+		       COUNT(CASE WHEN deleted_at IS NOT NULL THEN 1 END),
+		       COUNT(CASE WHEN deleted_at IS NULL THEN 1 END),
+		       COUNT(DISTINCT CASE WHEN deleted_at IS NULL THEN key END),
+		       COALESCE(SUM(CASE WHEN deleted_at IS NULL THEN LENGTH(CAST(content AS BLOB)) END), 0),
+		       MIN(created_at), MAX(created_at)
+		FROM memories WHERE ns = ?`, ns).
+		Scan(&d.Versions, &d.Deleted, &d.Memories, &d.Keys, &d.ContentBytes, &oldest, &newest)
+	if err != nil {
+		return nil, err
+	}
+	if d.Versions == 0 {
+		return nil, fmt.Errorf("namespace not found: %s", ns)
+	}
+	if t, err := time.Parse(time.RFC3339, oldest.String); err == nil {
+		d.Oldest = &t
+	}
+	if t, err := time.Parse(time.RFC3339, newest.String); err == nil {
+		d.Newest = &t
+	}
+
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM chunks c
+		INNER JOIN memories m ON m.id = c.memory_id
+		WHERE m.ns = ?`, ns).Scan(&d.Chunks); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT j.value, COUNT(*) AS cnt
+		FROM memories m
+		INNER JOIN (
+			SELECT ns, key, MAX(version) AS max_ver
+			FROM memories WHERE ns = ? AND deleted_at IS NULL
+			GROUP BY ns, key
+		) latest ON m.ns = latest.ns AND m.key = latest.key AND m.version = latest.max_ver,
+		json_each(m.tags) j
+		WHERE m.tags IS NOT NULL
+		GROUP BY j.value
+		ORDER BY cnt DESC, j.value
+		LIMIT ?`, ns, namespaceTopTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tc TagCount
+		if err := rows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return nil, err
+		}
+		d.TopTags = append(d.TopTags, tc)
+	}
+	return d, rows.Err()
+}
@@ -15,8 +15,28 @@ type PutParams struct {
 	Kind     string
 	Tags     []string
 	Priority string
-	Meta     string
+	Meta     string // JSON; validated and stored compacted
+	MetaRaw  bool   // store Meta verbatim, skipping validation
+	Actor    string // who wrote it; empty uses the store's actor (SetActor)
 	TTL      string // e.g. "7d", "24h", "30m"
+	// Append stores an episodic event under a unique key, Key + "/" + the new
+	// memory's ULID, instead of versioning Key. Kind defaults to episodic.
+	Append bool
+	// ExpiresAt is an absolute RFC3339 expiry, an alternative to TTL.
+	// Past timestamps are rejected unless AllowPast is set.
+	ExpiresAt string
+	AllowPast bool
+	// Format selects the chunking strategy: markdown, plain, or lines.
+	// Empty detects it from the content.
+	Format string
+	// Chunk sizing in chars; zero values fall back to the chunker defaults.
+	// When only ChunkTargetSize is set, min and max scale with it.
+	ChunkTargetSize int
+	ChunkMinSize    int
+	ChunkMaxSize    int
+	// ChunkOverlap is the number of chars shared between consecutive chunks.
+	// Zero uses the store default ($AGENT_MEMORY_CHUNK_OVERLAP, else none).
+	ChunkOverlap int
 }
 
 // GetParams holds parameters for retrieving a memory.
@@ -25,6 +45,12 @@ type GetParams struct {
 	Key     string
 	History bool
 	Version int // 0 means latest
+	// IncludeExpired also returns expired versions, without updating
+	// access tracking. History always includes them.
+	IncludeExpired bool
+	// Fuzzy falls back to the closest existing key in NS when Key has no
+	// match. Callers can compare the returned Key to detect this.
+	Fuzzy bool
 }
 
 // ListParams holds parameters for listing memories.
@@ -32,8 +58,16 @@ type ListParams struct {
 	NS       string
 	Kind     string
 	Tags     []string
+	Actor    string
 	Limit    int
 	KeysOnly bool
+
+	// MetaFilter matches meta JSON fields: each key (a dotted path such as
+	// "source" or "author.name") must equal its value.
+	MetaFilter map[string]string
+
+	IncludeDeleted bool // consider soft-deleted rows when picking each key's latest version
+	OnlyDeleted    bool // list every soft-deleted row, most recently deleted first
 }
 
 // RmParams holds parameters for deleting a memory.
@@ -42,6 +76,10 @@ type RmParams struct {
 	Key         string
 	AllVersions bool
 	Hard        bool
+	Cascade     bool // with Hard, also delete links to and from the memory
+	// Glob is a SQLite GLOB pattern (e.g. "tmp-*") matched against keys in
+	// NS by MatchKeys and RmGlob, instead of Key.
+	Glob string
 }
 
 // Store defines the memory storage interface.
@@ -59,6 +97,20 @@ type Store interface {
 	// Rm soft-deletes (or hard-deletes) a memory.
 	Rm(ctx context.Context, p RmParams) error
 
+	// Search finds memories matching a query.
+	Search(ctx context.Context, p SearchParams) ([]SearchResult, error)
+
+	// Context assembles relevant memories within a token budget.
+	Context(ctx context.Context, p ContextParams) (*ContextResult, error)
+
+	// Link creates or removes a relation between two memories.
+	Link(ctx context.Context, p LinkParams) (*Link, error)
+
+	// GetLinks returns all links for a memory.
+	GetLinks(ctx context.Context, memoryID string) ([]Link, error)
+
 	// Close closes the store.
 	Close() error
 }
+
+var _ Store = (*SQLiteStore)(nil)
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SweepExpired removes memories whose expiry has passed and returns how many
+// were swept. By default rows are soft-deleted so undelete can restore them;
+// hard removes the rows along with their chunks and links.
+func (s *SQLiteStore) SweepExpired(ctx context.Context, hard bool) (int, error) {
+	if s.readOnly {
+		return 0, ErrReadOnly
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	expired := `SELECT id FROM memories WHERE expires_at IS NOT NULL AND expires_at <= ? AND deleted_at IS NULL`
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT ns, key, version FROM memories WHERE id IN (`+expired+`) ORDER BY ns, key, version`, now)
+	if err != nil {
+		return 0, err
+	}
+	var swept []versionKey
+	for rows.Next() {
+		var v versionKey
+		if err := rows.Scan(&v.ns, &v.key, &v.version); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		swept = append(swept, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(swept) == 0 {
+		return 0, nil
+	}
+
+	detail := "expired"
+	if hard {
+		detail = "expired, hard"
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM memory_links WHERE from_id IN (`+expired+`) OR to_id IN (`+expired+`)`, now, now); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM chunks WHERE memory_id IN (`+expired+`)`, now); err != nil {
+			return 0, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM memories WHERE id IN (`+expired+`)`, now); err != nil {
+			return 0, err
+		}
+	} else if _, err := tx.ExecContext(ctx,
+		`UPDATE memories SET deleted_at = ? WHERE id IN (`+expired+`)`, now, now); err != nil {
+		return 0, err
+	}
+
+	for _, v := range swept {
+		if err := s.recordEvent(ctx, tx, EventRm, v.ns, v.key, v.version, detail); err != nil {
+			return 0, err
+		}
+	}
+	return len(swept), tx.Commit()
+}
+
+// autoSweep reports whether $AGENT_MEMORY_AUTO_SWEEP asks for expired
+// memories to be soft-deleted when the store is opened.
+func autoSweep() bool {
+	on, _ := strconv.ParseBool(os.Getenv("AGENT_MEMORY_AUTO_SWEEP"))
+	return on
+}
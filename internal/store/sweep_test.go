@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// expireAll backdates every memory in ns so it is already expired.
+func expireAll(t *testing.T, s *SQLiteStore, ns string) {
+	t.Helper()
+	past := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	if _, err := s.db.Exec(`UPDATE memories SET expires_at = ? WHERE ns = ?`, past, ns); err != nil {
+		t.Fatalf("backdate: %v", err)
+	}
+}
+
+func TestSweepExpiredSoft(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "old", Key: "a", Content: "stale"})
+	s.Put(ctx, PutParams{NS: "old", Key: "b", Content: "stale"})
+	s.Put(ctx, PutParams{NS: "live", Key: "c", Content: "fresh", TTL: "7d"})
+	expireAll(t, s, "old")
+
+	n, err := s.SweepExpired(ctx, false)
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 swept, got %d", n)
+	}
+
+	deleted, _ := s.List(ctx, ListParams{OnlyDeleted: true})
+	if len(deleted) != 2 {
+		t.Errorf("expected 2 soft-deleted rows, got %d", len(deleted))
+	}
+	if n, _ := s.SweepExpired(ctx, false); n != 0 {
+		t.Errorf("second sweep should find nothing, got %d", n)
+	}
+}
+
+func TestSweepExpiredHard(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "old", Key: "a", Content: "stale"})
+	s.Put(ctx, PutParams{NS: "live", Key: "b", Content: "fresh"})
+	s.Link(ctx, LinkParams{FromNS: "live", FromKey: "b", ToNS: "old", ToKey: "a", Rel: "relates_to"})
+	expireAll(t, s, "old")
+
+	n, err := s.SweepExpired(ctx, true)
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 swept, got %d", n)
+	}
+
+	var rows, chunks, links int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memories`).Scan(&rows)
+	s.db.QueryRow(`SELECT COUNT(*) FROM chunks`).Scan(&chunks)
+	s.db.QueryRow(`SELECT COUNT(*) FROM memory_links`).Scan(&links)
+	if rows != 1 || chunks != 1 || links != 0 {
+		t.Errorf("expected 1 memory, 1 chunk, 0 links; got %d, %d, %d", rows, chunks, links)
+	}
+}
+
+func TestAutoSweepOnOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	s.Put(ctx, PutParams{NS: "old", Key: "a", Content: "stale"})
+	expireAll(t, s, "old")
+	s.Close()
+
+	t.Setenv("AGENT_MEMORY_AUTO_SWEEP", "1")
+	s, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var deleted int
+	s.db.QueryRow(`SELECT COUNT(*) FROM memories WHERE deleted_at IS NOT NULL`).Scan(&deleted)
+	if deleted != 1 {
+		t.Errorf("expected expired memory to be swept on open, got %d deleted", deleted)
+	}
+}
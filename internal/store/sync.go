@@ -0,0 +1,319 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+// SyncResult reports what Sync copied between two stores.
+type SyncResult struct {
+	Pulled    int            `json:"pulled"`  // versions copied from the other store
+	Pushed    int            `json:"pushed"`  // versions copied to the other store
+	Links     int            `json:"links"`   // links copied in either direction
+	Skipped   int            `json:"skipped"` // versions not copied because of a conflict
+	Conflicts []SyncConflict `json:"conflicts,omitempty"`
+
+	// Versions not copied because their namespace is outside the receiving
+	// store's allow-list, and those namespaces.
+	Disallowed   int      `json:"disallowed,omitempty"`
+	DisallowedNS []string `json:"disallowed_ns,omitempty"`
+}
+
+// SyncConflict is an ns/key/version that exists in both stores with
+// different ids, i.e. was written independently on each side. That version
+// and every later version of the key are left alone.
+type SyncConflict struct {
+	NS      string `json:"ns"`
+	Key     string `json:"key"`
+	Version int    `json:"version"`
+}
+
+// versionKey identifies one version of a memory across stores.
+type versionKey struct {
+	ns, key string
+	version int
+}
+
+// syncRow is a raw memories row, copied verbatim between stores.
+type syncRow struct {
+	id, ns, key, content, kind, createdAt, priority string
+	version, accessCount                            int
+	tags, supersedes, deletedAt                     sql.NullString
+	lastAccessedAt, meta, expiresAt, encoding       sql.NullString
+	actor                                           sql.NullString
+}
+
+// Sync reconciles s with other in both directions. Every memory version
+// (matched by ns/key/version) missing on one side is copied to the other
+// with its id, timestamps, chunks, and embeddings intact, followed by any
+// links whose endpoints now exist on both sides. Soft-deleted versions are
+// copied too, so deletions propagate as history. Versions in a namespace
+// outside the receiving store's allow-list are skipped and reported.
+func (s *SQLiteStore) Sync(ctx context.Context, other *SQLiteStore) (*SyncResult, error) {
+	if s.readOnly || other.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	result := &SyncResult{}
+	conflicts := map[versionKey]bool{}
+	disallowed := map[string]int{} // ns -> versions not copied
+
+	pulled, skipped, err := copyMissing(ctx, other, s, conflicts, disallowed)
+	if err != nil {
+		return nil, fmt.Errorf("pull: %w", err)
+	}
+	result.Pulled, result.Skipped = pulled, skipped
+
+	pushed, skipped, err := copyMissing(ctx, s, other, conflicts, disallowed)
+	if err != nil {
+		return nil, fmt.Errorf("push: %w", err)
+	}
+	result.Pushed, result.Skipped = pushed, result.Skipped+skipped
+
+	for _, pair := range [][2]*SQLiteStore{{other, s}, {s, other}} {
+		n, err := copyLinks(ctx, pair[0], pair[1])
+		if err != nil {
+			return nil, fmt.Errorf("links: %w", err)
+		}
+		result.Links += n
+	}
+
+	for ns, n := range disallowed {
+		result.Disallowed += n
+		result.DisallowedNS = append(result.DisallowedNS, ns)
+	}
+	sort.Strings(result.DisallowedNS)
+
+	for v := range conflicts {
+		result.Conflicts = append(result.Conflicts, SyncConflict{NS: v.ns, Key: v.key, Version: v.version})
+	}
+	sort.Slice(result.Conflicts, func(i, j int) bool {
+		a, b := result.Conflicts[i], result.Conflicts[j]
+		if a.NS != b.NS {
+			return a.NS < b.NS
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Version < b.Version
+	})
+	return result, nil
+}
+
+// copyMissing copies versions present in src but not dst, recording
+// conflicting versions in conflicts and counting versions in namespaces dst
+// does not allow in disallowed. It returns the number copied and skipped
+// because of a conflict.
+func copyMissing(ctx context.Context, src, dst *SQLiteStore, conflicts map[versionKey]bool, disallowed map[string]int) (int, int, error) {
+	have, err := versionIDs(ctx, dst)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rows, err := src.db.QueryContext(ctx,
+		`SELECT id, ns, key, content, kind, tags, version, supersedes, created_at, deleted_at,
+		        priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor
+		 FROM memories ORDER BY ns, key, version`)
+	if err != nil {
+		return 0, 0, err
+	}
+	var missing []syncRow
+	skipped := 0
+	blocked := map[string]bool{} // ns+key with a conflict at an earlier version
+	for rows.Next() {
+		var r syncRow
+		if err := rows.Scan(&r.id, &r.ns, &r.key, &r.content, &r.kind, &r.tags, &r.version,
+			&r.supersedes, &r.createdAt, &r.deletedAt, &r.priority, &r.accessCount,
+			&r.lastAccessedAt, &r.meta, &r.expiresAt, &r.encoding, &r.actor); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		nk := r.ns + "\x00" + r.key
+		v := versionKey{r.ns, r.key, r.version}
+		id, ok := have[v]
+		switch {
+		case ok && id == r.id:
+			continue
+		case ok || blocked[nk]:
+			blocked[nk] = true
+			conflicts[v] = true
+			skipped++
+			continue
+		}
+		if err := dst.checkNamespace(r.ns); err != nil {
+			disallowed[r.ns]++
+			continue
+		}
+		missing = append(missing, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+	if len(missing) == 0 {
+		return 0, skipped, nil
+	}
+
+	// Embeddings are only meaningful if both stores index the same model.
+	srcIndex, err := src.EmbedIndex(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	dstIndex, err := dst.EmbedIndex(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	keepVectors := srcIndex != nil && (dstIndex == nil || *dstIndex == *srcIndex)
+
+	tx, err := dst.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	if keepVectors && dstIndex == nil {
+		if err := setEmbedIndex(ctx, tx, srcIndex); err != nil {
+			return 0, 0, fmt.Errorf("record embed index: %w", err)
+		}
+	}
+
+	for _, r := range missing {
+		content, err := reseal(src, dst, r.content)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s/%s v%d: %w", r.ns, r.key, r.version, err)
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO memories (id, ns, key, content, kind, tags, version, supersedes, created_at, deleted_at,
+			                       priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			r.id, r.ns, r.key, content, r.kind, r.tags, r.version, r.supersedes, r.createdAt, r.deletedAt,
+			r.priority, r.accessCount, r.lastAccessedAt, r.meta, r.expiresAt, r.encoding, r.actor)
+		if err != nil {
+			return 0, 0, fmt.Errorf("insert %s/%s v%d: %w", r.ns, r.key, r.version, err)
+		}
+		if err := copyChunks(ctx, src, dst, tx, r.id, keepVectors); err != nil {
+			return 0, 0, fmt.Errorf("chunks for %s/%s v%d: %w", r.ns, r.key, r.version, err)
+		}
+		if err := dst.recordEvent(ctx, tx, EventPut, r.ns, r.key, r.version, "sync"); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return len(missing), skipped, nil
+}
+
+// versionIDs maps every ns/key/version in s to its memory id.
+func versionIDs(ctx context.Context, s *SQLiteStore) (map[versionKey]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, ns, key, version FROM memories`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := map[versionKey]string{}
+	for rows.Next() {
+		var id string
+		var v versionKey
+		if err := rows.Scan(&id, &v.ns, &v.key, &v.version); err != nil {
+			return nil, err
+		}
+		ids[v] = id
+	}
+	return ids, rows.Err()
+}
+
+// copyChunks copies the chunks of memoryID from src into dst's tx, dropping
+// embeddings unless keepVectors is set.
+func copyChunks(ctx context.Context, src, dst *SQLiteStore, tx *sql.Tx, memoryID string, keepVectors bool) error {
+	rows, err := src.db.QueryContext(ctx,
+		`SELECT id, seq, text, start_line, end_line, embedding FROM chunks WHERE memory_id = ? ORDER BY seq`, memoryID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, text string
+		var seq int
+		var start, end sql.NullInt64
+		var embedding sql.NullString
+		if err := rows.Scan(&id, &seq, &text, &start, &end, &embedding); err != nil {
+			return err
+		}
+		if !keepVectors {
+			embedding = sql.NullString{}
+		}
+		if text, err = reseal(src, dst, text); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO chunks (id, memory_id, seq, text, start_line, end_line, embedding)
+			 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			id, memoryID, seq, text, start, end, embedding); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// reseal re-encrypts text from src's content key to dst's. Values pass
+// through unchanged when neither store has a key.
+func reseal(src, dst *SQLiteStore, text string) (string, error) {
+	if src.aead == nil && dst.aead == nil {
+		return text, nil
+	}
+	plain, err := src.decrypt(text)
+	if err != nil {
+		return "", err
+	}
+	return dst.encrypt(plain)
+}
+
+// copyLinks copies links from src to dst whose endpoints both exist in dst.
+func copyLinks(ctx context.Context, src, dst *SQLiteStore) (int, error) {
+	rows, err := src.db.QueryContext(ctx, `SELECT from_id, to_id, rel, created_at FROM memory_links`)
+	if err != nil {
+		return 0, err
+	}
+	type link struct{ from, to, rel, created string }
+	var links []link
+	for rows.Next() {
+		var l link
+		if err := rows.Scan(&l.from, &l.to, &l.rel, &l.created); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		links = append(links, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	tx, err := dst.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	copied := 0
+	for _, l := range links {
+		res, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO memory_links (from_id, to_id, rel, created_at)
+			 SELECT ?, ?, ?, ?
+			 WHERE EXISTS (SELECT 1 FROM memories WHERE id = ?)
+			   AND EXISTS (SELECT 1 FROM memories WHERE id = ?)`,
+			l.from, l.to, l.rel, l.created, l.from, l.to)
+		if err != nil {
+			return 0, err
+		}
+		n, _ := res.RowsAffected()
+		copied += int(n)
+	}
+	return copied, tx.Commit()
+}
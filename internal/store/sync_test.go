@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSync(t *testing.T) {
+	dir := t.TempDir()
+	laptop, _ := NewSQLiteStore(filepath.Join(dir, "laptop.db"))
+	defer laptop.Close()
+	server, _ := NewSQLiteStore(filepath.Join(dir, "server.db"))
+	defer server.Close()
+	ctx := context.Background()
+
+	a, _ := laptop.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha"})
+	laptop.Put(ctx, PutParams{NS: "test", Key: "a", Content: "alpha v2"})
+	server.Put(ctx, PutParams{NS: "test", Key: "b", Content: "beta"})
+	laptop.Put(ctx, PutParams{NS: "test", Key: "c", Content: "gamma"})
+	if _, err := laptop.Link(ctx, LinkParams{FromNS: "test", FromKey: "a", ToNS: "test", ToKey: "c", Rel: "relates_to"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := laptop.Sync(ctx, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pulled != 1 || res.Pushed != 3 || res.Links != 1 || res.Skipped != 0 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	got, err := server.Get(ctx, GetParams{NS: "test", Key: "a", Version: 1})
+	if err != nil {
+		t.Fatalf("get pushed: %v", err)
+	}
+	if got[0].ID != a.ID || !got[0].CreatedAt.Equal(a.CreatedAt.Truncate(time.Second)) {
+		t.Errorf("expected id and timestamp preserved, got %s %v", got[0].ID, got[0].CreatedAt)
+	}
+	results, _ := server.Search(ctx, SearchParams{Query: "alpha", Mode: "keyword"})
+	if len(results) == 0 {
+		t.Error("expected pushed chunks to be searchable")
+	}
+
+	// A second sync is a no-op.
+	res, _ = laptop.Sync(ctx, server)
+	if res.Pulled != 0 || res.Pushed != 0 || res.Links != 0 || res.Skipped != 0 {
+		t.Errorf("expected no-op resync, got %+v", res)
+	}
+}
+
+func TestSyncConflict(t *testing.T) {
+	dir := t.TempDir()
+	laptop, _ := NewSQLiteStore(filepath.Join(dir, "laptop.db"))
+	defer laptop.Close()
+	server, _ := NewSQLiteStore(filepath.Join(dir, "server.db"))
+	defer server.Close()
+	ctx := context.Background()
+
+	laptop.Put(ctx, PutParams{NS: "test", Key: "a", Content: "laptop v1"})
+	laptop.Put(ctx, PutParams{NS: "test", Key: "a", Content: "laptop v2"})
+	server.Put(ctx, PutParams{NS: "test", Key: "a", Content: "server v1"})
+
+	res, err := laptop.Sync(ctx, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pulled != 0 || res.Pushed != 0 || res.Skipped != 3 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if len(res.Conflicts) != 2 || res.Conflicts[0].Version != 1 || res.Conflicts[1].Version != 2 {
+		t.Errorf("expected conflicts at v1 and v2, got %+v", res.Conflicts)
+	}
+}
+
+func TestSyncAllowedNamespaces(t *testing.T) {
+	dir := t.TempDir()
+	local, _ := NewSQLiteStore(filepath.Join(dir, "local.db"))
+	defer local.Close()
+	other, _ := NewSQLiteStore(filepath.Join(dir, "other.db"))
+	defer other.Close()
+	ctx := context.Background()
+
+	other.Put(ctx, PutParams{NS: "project:app", Key: "a", Content: "allowed"})
+	other.Put(ctx, PutParams{NS: "personal", Key: "b", Content: "secret"})
+	other.Put(ctx, PutParams{NS: "personal", Key: "b", Content: "secret v2"})
+	if err := local.SetAllowedNamespaces([]string{"project:*"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := local.Sync(ctx, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pulled != 1 || res.Disallowed != 2 || len(res.DisallowedNS) != 1 || res.DisallowedNS[0] != "personal" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if _, err := local.Get(ctx, GetParams{NS: "personal", Key: "b"}); err == nil {
+		t.Error("expected the disallowed namespace not to be pulled")
+	}
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenCounter estimates how many model tokens a piece of text costs.
+type TokenCounter interface {
+	Count(text string) int
+}
+
+// CharCounter approximates tokens as 4 bytes per token. It is the default
+// and matches the historical budgeting behaviour.
+type CharCounter struct{}
+
+// Count implements TokenCounter.
+func (CharCounter) Count(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// WordCounter approximates tokens as 1.3 per whitespace-separated word, with
+// each CJK character counted as its own token. It tracks real tokenizers far
+// more closely than CharCounter for code and non-Latin text.
+type WordCounter struct{}
+
+// Count implements TokenCounter.
+func (WordCounter) Count(text string) int {
+	words, cjk := 0, 0
+	inWord := false
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsSpace(r):
+			inWord = false
+		default:
+			if !inWord {
+				words++
+				inWord = true
+			}
+		}
+	}
+	return int(math.Ceil(float64(words)*1.3)) + cjk
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// TokenCounterByName returns the counter for a CLI-facing name.
+func TokenCounterByName(name string) (TokenCounter, error) {
+	switch strings.ToLower(name) {
+	case "", "chars":
+		return CharCounter{}, nil
+	case "words":
+		return WordCounter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown tokenizer %q (want chars or words)", name)
+	}
+}
+
+// truncateToTokens returns the longest rune-aligned prefix of text that,
+// followed by suffix, costs at most max tokens. When the prefix contains a
+// sentence or line break in its second half, it is cut there instead.
+func truncateToTokens(c TokenCounter, text, suffix string, max int) string {
+	runes := []rune(text)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if c.Count(string(runes[:mid])+suffix) <= max {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return sentencePrefix(string(runes[:lo])) + suffix
+}
+
+// sentencePrefix trims s back to its last sentence or line boundary, as long
+// as that keeps at least half of s. Otherwise s is returned unchanged.
+func sentencePrefix(s string) string {
+	cut := -1
+	for i, r := range s {
+		switch r {
+		case '\n':
+			cut = i
+		case '.', '!', '?', '。', '！', '？':
+			end := i + utf8.RuneLen(r)
+			if end == len(s) || s[end] == ' ' || s[end] == '\n' || r >= utf8.RuneSelf {
+				cut = end
+			}
+		}
+	}
+	if cut < len(s)/2 {
+		return s
+	}
+	return strings.TrimRight(s[:cut], " \n")
+}
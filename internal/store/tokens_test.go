@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCharCounter(t *testing.T) {
+	c := CharCounter{}
+	if got := c.Count(""); got != 0 {
+		t.Errorf("empty: got %d", got)
+	}
+	if got := c.Count("abcdefgh"); got != 2 {
+		t.Errorf("8 chars: got %d, want 2", got)
+	}
+	if got := c.Count("abcde"); got != 2 {
+		t.Errorf("5 chars: got %d, want 2 (rounded up)", got)
+	}
+}
+
+func TestWordCounter(t *testing.T) {
+	c := WordCounter{}
+	if got := c.Count("the quick brown fox"); got != 6 {
+		t.Errorf("4 words: got %d, want 6", got)
+	}
+	// Each CJK character is its own token
+	if got := c.Count("日本語"); got != 3 {
+		t.Errorf("3 CJK chars: got %d, want 3", got)
+	}
+	if got := c.Count("Go 言語"); got != 4 {
+		t.Errorf("mixed: got %d, want 4", got)
+	}
+}
+
+func TestTokenCounterByName(t *testing.T) {
+	for _, name := range []string{"", "chars", "words", "WORDS"} {
+		if _, err := TokenCounterByName(name); err != nil {
+			t.Errorf("%q: %v", name, err)
+		}
+	}
+	if _, err := TokenCounterByName("bpe"); err == nil {
+		t.Error("expected error for unknown tokenizer")
+	}
+}
+
+func TestContextWithWordCounter(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	long := strings.Repeat("kubernetes cluster upgrade notes ", 200)
+	s.Put(ctx, PutParams{NS: "test", Key: "big", Content: long})
+	s.Put(ctx, PutParams{NS: "test", Key: "small", Content: "kubernetes runs in us-east"})
+
+	counter := WordCounter{}
+	result, err := s.Context(ctx, ContextParams{NS: "test", Query: "kubernetes", Budget: 100, Counter: counter})
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	if result.Used > result.Budget {
+		t.Errorf("used %d exceeds budget %d", result.Used, result.Budget)
+	}
+	total := 0
+	for _, m := range result.Memories {
+		total += counter.Count(m.Content)
+	}
+	if total != result.Used {
+		t.Errorf("used %d, but memories cost %d tokens", result.Used, total)
+	}
+}
+
+func TestSentencePrefix(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"First sentence. Second sentence. Third sent", "First sentence. Second sentence."},
+		{"line one\nline two\nline thr", "line one\nline two"},
+		{"Short. then a very long run of words without any boundary at all", "Short. then a very long run of words without any boundary at all"},
+		{"version 1.2 is out and stable", "version 1.2 is out and stable"},
+		{"日本語の文です。もう一つの文です。途中", "日本語の文です。もう一つの文です。"},
+	}
+	for _, tt := range tests {
+		if got := sentencePrefix(tt.in); got != tt.want {
+			t.Errorf("sentencePrefix(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestContextExcerptMultibyte(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	// 3-byte runes so a byte budget rarely lands on a rune boundary
+	content := strings.Repeat("データベースの移行手順を確認してください。", 40)
+	s.Put(ctx, PutParams{NS: "test", Key: "jp", Content: "migration " + content})
+
+	result, err := s.Context(ctx, ContextParams{NS: "test", Query: "migration", Budget: 101})
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+	if len(result.Memories) != 1 || !result.Memories[0].Excerpt {
+		t.Fatalf("expected one excerpt, got %+v", result.Memories)
+	}
+	excerpt := result.Memories[0].Content
+	if !utf8.ValidString(excerpt) {
+		t.Fatalf("excerpt is not valid UTF-8: %q", excerpt)
+	}
+	if !strings.HasSuffix(excerpt, "。...") {
+		t.Errorf("expected excerpt to end on a sentence boundary, got %q", excerpt)
+	}
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+// VerifyIssue describes one class of integrity problem found by Verify.
+type VerifyIssue struct {
+	Check  string `json:"check"`
+	Count  int    `json:"count"`
+	Detail string `json:"detail,omitempty"`
+	Fixed  bool   `json:"fixed,omitempty"`
+}
+
+// VerifyResult is the outcome of an integrity check.
+type VerifyResult struct {
+	OK     bool          `json:"ok"`
+	Issues []VerifyIssue `json:"issues"`
+}
+
+// Verify checks the store for integrity problems. When fix is set, orphaned
+// chunks and links are removed and the FTS index is rebuilt; other issues
+// are only reported.
+func (s *SQLiteStore) Verify(ctx context.Context, fix bool) (*VerifyResult, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+	res := &VerifyResult{Issues: []VerifyIssue{}}
+
+	// SQLite page-level integrity
+	rows, err := s.db.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return nil, fmt.Errorf("integrity check: %w", err)
+	}
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	rows.Close()
+	if len(problems) > 0 {
+		res.Issues = append(res.Issues, VerifyIssue{
+			Check:  "integrity_check",
+			Count:  len(problems),
+			Detail: strings.Join(problems, "; "),
+		})
+	}
+
+	// Chunks whose memory no longer exists
+	var n int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM chunks WHERE memory_id NOT IN (SELECT id FROM memories)`).Scan(&n); err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		issue := VerifyIssue{Check: "dangling_chunks", Count: n}
+		if fix {
+			if _, err := s.db.ExecContext(ctx,
+				`DELETE FROM chunks WHERE memory_id NOT IN (SELECT id FROM memories)`); err != nil {
+				return nil, fmt.Errorf("remove dangling chunks: %w", err)
+			}
+			issue.Fixed = true
+		}
+		res.Issues = append(res.Issues, issue)
+	}
+
+	// Links whose endpoints no longer exist
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM memory_links
+		WHERE from_id NOT IN (SELECT id FROM memories) OR to_id NOT IN (SELECT id FROM memories)`).Scan(&n); err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		issue := VerifyIssue{Check: "dangling_links", Count: n}
+		if fix {
+			if _, err := s.db.ExecContext(ctx, `
+				DELETE FROM memory_links
+				WHERE from_id NOT IN (SELECT id FROM memories) OR to_id NOT IN (SELECT id FROM memories)`); err != nil {
+				return nil, fmt.Errorf("remove dangling links: %w", err)
+			}
+			issue.Fixed = true
+		}
+		res.Issues = append(res.Issues, issue)
+	}
+
+	// Links to soft-deleted memories (recoverable, so reported only)
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM memory_links l
+		WHERE EXISTS (SELECT 1 FROM memories m WHERE m.id IN (l.from_id, l.to_id) AND m.deleted_at IS NOT NULL)`).Scan(&n); err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		res.Issues = append(res.Issues, VerifyIssue{Check: "links_to_deleted", Count: n})
+	}
+
+	// FTS index consistency with the chunks table
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO chunks_fts(chunks_fts, rank) VALUES('integrity-check', 1)`); err != nil {
+		issue := VerifyIssue{Check: "fts_out_of_sync", Count: 1, Detail: err.Error()}
+		if fix {
+			if _, err := s.db.ExecContext(ctx, `INSERT INTO chunks_fts(chunks_fts) VALUES('rebuild')`); err != nil {
+				return nil, fmt.Errorf("rebuild fts: %w", err)
+			}
+			issue.Fixed = true
+		}
+		res.Issues = append(res.Issues, issue)
+	}
+
+	// Invalid kind/priority values
+	if issue, err := s.invalidValues(ctx, "kind", model.ValidKinds); err != nil {
+		return nil, err
+	} else if issue != nil {
+		res.Issues = append(res.Issues, *issue)
+	}
+	if issue, err := s.invalidValues(ctx, "priority", model.ValidPriorities); err != nil {
+		return nil, err
+	} else if issue != nil {
+		res.Issues = append(res.Issues, *issue)
+	}
+
+	res.OK = true
+	for _, issue := range res.Issues {
+		if !issue.Fixed {
+			res.OK = false
+		}
+	}
+	return res, nil
+}
+
+// invalidValues reports memories whose column holds a value outside valid.
+// column must be a trusted identifier, never user input.
+func (s *SQLiteStore) invalidValues(ctx context.Context, column string, valid map[string]bool) (*VerifyIssue, error) {
+	counts, err := s.countBy(ctx, column, false)
+	if err != nil {
+		return nil, err
+	}
+	var bad []string
+	total := 0
+	for v, n := range counts {
+		if !valid[v] {
+			bad = append(bad, fmt.Sprintf("%q", v))
+			total += n
+		}
+	}
+	if total == 0 {
+		return nil, nil
+	}
+	sort.Strings(bad)
+	return &VerifyIssue{
+		Check:  "invalid_" + column,
+		Count:  total,
+		Detail: strings.Join(bad, ", "),
+	}, nil
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVerifyClean(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "alpha"})
+
+	res, err := s.Verify(ctx, false)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !res.OK || len(res.Issues) != 0 {
+		t.Fatalf("expected clean store, got %+v", res.Issues)
+	}
+}
+
+func TestVerifyDetectsAndFixes(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "a", Content: "alpha"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "b", Content: "beta", Kind: "bogus"})
+
+	// Create orphans with foreign keys disabled on a single connection
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`)
+	conn.ExecContext(ctx, `INSERT INTO chunks (id, memory_id, seq, text) VALUES ('c1', 'missing', 0, 'orphan')`)
+	conn.ExecContext(ctx, `INSERT INTO memory_links (from_id, to_id, rel, created_at) VALUES ('missing', 'gone', 'relates_to', '2026-01-01T00:00:00Z')`)
+	conn.ExecContext(ctx, `PRAGMA foreign_keys = ON`)
+	conn.Close()
+
+	// Desync FTS by editing chunk text without the update trigger
+	s.db.Exec(`DROP TRIGGER chunks_au`)
+	s.db.Exec(`UPDATE chunks SET text = 'changed' WHERE memory_id IN (SELECT id FROM memories WHERE key = 'a')`)
+
+	res, err := s.Verify(ctx, false)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if res.OK {
+		t.Fatal("expected problems to be reported")
+	}
+	found := map[string]bool{}
+	for _, issue := range res.Issues {
+		found[issue.Check] = true
+	}
+	for _, check := range []string{"dangling_chunks", "dangling_links", "fts_out_of_sync", "invalid_kind"} {
+		if !found[check] {
+			t.Errorf("expected %s issue, got %+v", check, res.Issues)
+		}
+	}
+
+	if _, err := s.Verify(ctx, true); err != nil {
+		t.Fatalf("verify --fix: %v", err)
+	}
+
+	// Only the unfixable invalid kind should remain
+	res, _ = s.Verify(ctx, false)
+	if len(res.Issues) != 1 || res.Issues[0].Check != "invalid_kind" {
+		t.Fatalf("expected only invalid_kind after fix, got %+v", res.Issues)
+	}
+}
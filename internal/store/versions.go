@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+// RollbackParams holds parameters for restoring a previous version.
+type RollbackParams struct {
+	NS  string
+	Key string
+	To  int // version whose content becomes the new latest
+}
+
+// Rollback stores the content, kind, tags, priority, and meta of a previous
+// version as a new latest version. Intermediate versions are kept for audit.
+func (s *SQLiteStore) Rollback(ctx context.Context, p RollbackParams) (*model.Memory, error) {
+	if p.To <= 0 {
+		return nil, fmt.Errorf("rollback: target version is required")
+	}
+
+	history, err := s.Get(ctx, GetParams{NS: p.NS, Key: p.Key, History: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var target *model.Memory
+	for i := range history {
+		if history[i].Version == p.To {
+			target = &history[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("version %d not found: %s/%s", p.To, p.NS, p.Key)
+	}
+	if target.Version == history[0].Version {
+		return nil, fmt.Errorf("version %d is already the latest: %s/%s", p.To, p.NS, p.Key)
+	}
+
+	return s.Put(ctx, PutParams{
+		NS:       p.NS,
+		Key:      p.Key,
+		Content:  target.Content,
+		Kind:     target.Kind,
+		Tags:     target.Tags,
+		Priority: target.Priority,
+		Meta:     target.Meta,
+		// Stored meta is already canonical; only meta saved with MetaRaw
+		// fails to parse, and it must round-trip as is.
+		MetaRaw: target.Meta != "" && !json.Valid([]byte(target.Meta)),
+	})
+}
+
+// UndeleteParams holds parameters for restoring soft-deleted memories.
+type UndeleteParams struct {
+	NS          string
+	Key         string
+	Version     int  // 0 means the most recently deleted version
+	AllVersions bool // restore every soft-deleted version
+}
+
+// Undelete clears deleted_at on soft-deleted versions of a memory and returns
+// the restored rows, newest first.
+func (s *SQLiteStore) Undelete(ctx context.Context, p UndeleteParams) ([]model.Memory, error) {
+	if s.readOnly {
+		return nil, ErrReadOnly
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id, version FROM memories WHERE ns = ? AND key = ? AND deleted_at IS NOT NULL`
+	args := []interface{}{p.NS, p.Key}
+	switch {
+	case p.AllVersions:
+	case p.Version > 0:
+		query += ` AND version = ?`
+		args = append(args, p.Version)
+	default:
+		query += ` ORDER BY version DESC LIMIT 1`
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	var versions []int
+	for rows.Next() {
+		var id string
+		var v int
+		if err := rows.Scan(&id, &v); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+		versions = append(versions, v)
+	}
+	rows.Close()
+	if len(ids) == 0 {
+		if p.Version > 0 {
+			return nil, fmt.Errorf("no deleted version %d: %s/%s", p.Version, p.NS, p.Key)
+		}
+		return nil, fmt.Errorf("no deleted versions: %s/%s", p.NS, p.Key)
+	}
+
+	for i, id := range ids {
+		// A new put after deletion may have reused the version number
+		var clash int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM memories WHERE ns = ? AND key = ? AND version = ? AND deleted_at IS NULL`,
+			p.NS, p.Key, versions[i]).Scan(&clash); err != nil {
+			return nil, fmt.Errorf("check version %d of %s/%s: %w", versions[i], p.NS, p.Key, err)
+		}
+		if clash > 0 {
+			return nil, fmt.Errorf("version %d of %s/%s is already in use by an active memory", versions[i], p.NS, p.Key)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE memories SET deleted_at = NULL WHERE id = ?`, id); err != nil {
+			return nil, fmt.Errorf("undelete: %w", err)
+		}
+		if err := s.recordEvent(ctx, tx, EventUndelete, p.NS, p.Key, versions[i], ""); err != nil {
+			return nil, err
+		}
+	}
+
+	var restored []model.Memory
+	for _, id := range ids {
+		m, err := s.scanMemory(tx.QueryRowContext(ctx,
+			`SELECT id, ns, key, content, kind, tags, version, supersedes,
+			        created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor
+			 FROM memories WHERE id = ?`, id))
+		if err != nil {
+			return nil, err
+		}
+		restored = append(restored, m)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	sort.Slice(restored, func(i, j int) bool { return restored[i].Version > restored[j].Version })
+	return restored, nil
+}
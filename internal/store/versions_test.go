@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRollback(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "v1"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "good", Tags: []string{"keep"}, Meta: `{"a":1}`})
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "bad"})
+	v3, _ := s.Get(ctx, GetParams{NS: "ns", Key: "k"})
+
+	mem, err := s.Rollback(ctx, RollbackParams{NS: "ns", Key: "k", To: 2})
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if mem.Version != 4 {
+		t.Errorf("expected new version 4, got %d", mem.Version)
+	}
+	if mem.Supersedes != v3[0].ID {
+		t.Errorf("expected supersedes %s, got %s", v3[0].ID, mem.Supersedes)
+	}
+
+	got, _ := s.Get(ctx, GetParams{NS: "ns", Key: "k"})
+	if got[0].Content != "good" || len(got[0].Tags) != 1 || got[0].Meta != `{"a":1}` {
+		t.Errorf("expected v2 content/tags/meta restored, got %+v", got[0])
+	}
+
+	hist, _ := s.Get(ctx, GetParams{NS: "ns", Key: "k", History: true})
+	if len(hist) != 4 {
+		t.Errorf("expected bad version kept in history, got %d versions", len(hist))
+	}
+}
+
+func TestRollbackRawMeta(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "v1", Meta: "not json", MetaRaw: true})
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "v2"})
+
+	mem, err := s.Rollback(ctx, RollbackParams{NS: "ns", Key: "k", To: 1})
+	if err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if mem.Content != "v1" || mem.Meta != "not json" {
+		t.Errorf("expected v1 with its raw meta restored, got %+v", mem)
+	}
+}
+
+func TestRollbackErrors(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "v1"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "v2"})
+
+	if _, err := s.Rollback(ctx, RollbackParams{NS: "ns", Key: "k", To: 9}); err == nil {
+		t.Error("expected error for missing version")
+	}
+	if _, err := s.Rollback(ctx, RollbackParams{NS: "ns", Key: "k", To: 2}); err == nil {
+		t.Error("expected error when rolling back to latest")
+	}
+	if _, err := s.Rollback(ctx, RollbackParams{NS: "ns", Key: "missing", To: 1}); err == nil {
+		t.Error("expected error for missing key")
+	}
+}
+
+func TestUndelete(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "data"})
+	if _, err := s.Undelete(ctx, UndeleteParams{NS: "ns", Key: "k"}); err == nil {
+		t.Fatal("expected error when nothing is deleted")
+	}
+
+	s.Rm(ctx, RmParams{NS: "ns", Key: "k"})
+	restored, err := s.Undelete(ctx, UndeleteParams{NS: "ns", Key: "k"})
+	if err != nil {
+		t.Fatalf("undelete: %v", err)
+	}
+	if len(restored) != 1 || restored[0].DeletedAt != nil {
+		t.Fatalf("expected 1 restored memory with no deleted_at, got %+v", restored)
+	}
+
+	got, err := s.Get(ctx, GetParams{NS: "ns", Key: "k"})
+	if err != nil || got[0].Content != "data" {
+		t.Fatalf("expected memory to be readable after undelete: %v", err)
+	}
+}
+
+func TestUndeleteAllVersions(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "v1"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "v2"})
+	s.Rm(ctx, RmParams{NS: "ns", Key: "k", AllVersions: true})
+
+	restored, err := s.Undelete(ctx, UndeleteParams{NS: "ns", Key: "k", AllVersions: true})
+	if err != nil {
+		t.Fatalf("undelete: %v", err)
+	}
+	if len(restored) != 2 || restored[0].Version != 2 {
+		t.Fatalf("expected both versions restored newest first, got %+v", restored)
+	}
+
+	hist, _ := s.Get(ctx, GetParams{NS: "ns", Key: "k", History: true})
+	if len(hist) != 2 {
+		t.Errorf("expected 2 versions in history, got %d", len(hist))
+	}
+}
+
+func TestUndeleteVersionClash(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "old"})
+	s.Rm(ctx, RmParams{NS: "ns", Key: "k"})
+	s.Put(ctx, PutParams{NS: "ns", Key: "k", Content: "new"}) // reuses version 1
+
+	if _, err := s.Undelete(ctx, UndeleteParams{NS: "ns", Key: "k", Version: 1}); err == nil {
+		t.Fatal("expected error when version number is in use")
+	}
+}
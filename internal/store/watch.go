@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/rcliao/agent-memory/internal/model"
+)
+
+// WatchCursor tracks how far a watcher has read. created_at has one-second
+// resolution, so the cursor also remembers which ids it has already returned
+// for its current second; rows committed later in that same second are still
+// picked up.
+type WatchCursor struct {
+	CreatedAt string // RFC3339
+	seen      map[string]bool
+}
+
+// NewWatchCursor returns a cursor positioned at t: the first poll returns
+// memories created at or after t.
+func NewWatchCursor(t time.Time) *WatchCursor {
+	return &WatchCursor{CreatedAt: t.UTC().Format(time.RFC3339)}
+}
+
+// WatchNext returns memory versions created since the cursor, oldest first,
+// and advances the cursor past them. Soft-deleted versions are skipped. An
+// empty ns watches every namespace.
+func (s *SQLiteStore) WatchNext(ctx context.Context, ns string, c *WatchCursor) ([]model.Memory, error) {
+	query := `
+		SELECT id, ns, key, content, kind, tags, version, supersedes,
+		       created_at, deleted_at, priority, access_count, last_accessed_at, meta, expires_at, content_encoding, actor
+		FROM memories
+		WHERE created_at >= ? AND deleted_at IS NULL`
+	args := []interface{}{c.CreatedAt}
+	if ns != "" {
+		query += ` AND ns = ?`
+		args = append(args, ns)
+	}
+	query += ` ORDER BY created_at, rowid`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var memories []model.Memory
+	for rows.Next() {
+		m, err := s.scanMemory(rows)
+		if err != nil {
+			return nil, err
+		}
+		created := m.CreatedAt.UTC().Format(time.RFC3339)
+		if created == c.CreatedAt && c.seen[m.ID] {
+			continue
+		}
+		if created != c.CreatedAt {
+			c.CreatedAt = created
+			c.seen = nil
+		}
+		if c.seen == nil {
+			c.seen = map[string]bool{}
+		}
+		c.seen[m.ID] = true
+		memories = append(memories, m)
+	}
+	return memories, rows.Err()
+}
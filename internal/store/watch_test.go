@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchNext(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	cursor := NewWatchCursor(time.Now().Add(-time.Minute))
+	if got, err := s.WatchNext(ctx, "", cursor); err != nil || len(got) != 0 {
+		t.Fatalf("expected nothing yet, got %d, %v", len(got), err)
+	}
+
+	s.Put(ctx, PutParams{NS: "a", Key: "k1", Content: "one"})
+	s.Put(ctx, PutParams{NS: "b", Key: "k2", Content: "two"})
+
+	got, err := s.WatchNext(ctx, "", cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Key != "k1" || got[1].Key != "k2" {
+		t.Fatalf("expected k1, k2 in order, got %+v", got)
+	}
+
+	// Same second: only rows not already returned.
+	if got, _ := s.WatchNext(ctx, "", cursor); len(got) != 0 {
+		t.Errorf("expected no repeats, got %d", len(got))
+	}
+	s.Put(ctx, PutParams{NS: "a", Key: "k1", Content: "one v2"})
+	got, _ = s.WatchNext(ctx, "", cursor)
+	if len(got) != 1 || got[0].Version != 2 {
+		t.Errorf("expected the new version only, got %+v", got)
+	}
+
+	nsCursor := NewWatchCursor(time.Now().Add(-time.Minute))
+	got, _ = s.WatchNext(ctx, "b", nsCursor)
+	if len(got) != 1 || got[0].NS != "b" {
+		t.Errorf("expected only namespace b, got %+v", got)
+	}
+}
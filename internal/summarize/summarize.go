@@ -0,0 +1,135 @@
+// Package summarize provides a pluggable interface for compressing text into
+// a shorter brief, used to fit more memories into a context budget.
+package summarize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Summarizer shortens text to roughly maxTokens tokens.
+type Summarizer interface {
+	Summarize(ctx context.Context, text string, maxTokens int) (string, error)
+}
+
+// --- No-op ---
+
+// Noop returns text unchanged. Callers still truncate it to fit.
+type Noop struct{}
+
+func (Noop) Summarize(ctx context.Context, text string, maxTokens int) (string, error) {
+	return text, nil
+}
+
+// --- OpenAI-compatible Provider ---
+
+// OpenAISummarizer uses any OpenAI-compatible chat completions API.
+type OpenAISummarizer struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+const systemPrompt = "You compress notes for an AI agent's context window. " +
+	"Rewrite the note as a brief that keeps every fact, name, number, and decision " +
+	"and drops everything else. Reply with the brief only."
+
+// NewOpenAISummarizer creates a summarizer using an OpenAI-compatible API.
+func NewOpenAISummarizer(baseURL, apiKey, model string) *OpenAISummarizer {
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	return &OpenAISummarizer{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (s *OpenAISummarizer) Summarize(ctx context.Context, text string, maxTokens int) (string, error) {
+	body, _ := json.Marshal(chatRequest{
+		Model: s.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: fmt.Sprintf("Keep it under %d tokens.\n\n%s", maxTokens, text)},
+		},
+		MaxTokens: maxTokens,
+	})
+	req, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("openai request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		b, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("openai error %d: %s", resp.StatusCode, string(b))
+	}
+
+	var result chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if len(result.Choices) == 0 || strings.TrimSpace(result.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("no summary returned")
+	}
+	return strings.TrimSpace(result.Choices[0].Message.Content), nil
+}
+
+// --- Factory ---
+
+// NewFromEnv creates a summarizer from environment variables.
+// AGENT_MEMORY_SUMMARIZE_PROVIDER: "openai" | "" (no-op)
+// AGENT_MEMORY_SUMMARIZE_MODEL: model name
+// AGENT_MEMORY_SUMMARIZE_URL: base URL override
+// OPENAI_API_KEY: for openai provider
+func NewFromEnv() Summarizer {
+	switch os.Getenv("AGENT_MEMORY_SUMMARIZE_PROVIDER") {
+	case "openai":
+		return NewOpenAISummarizer(
+			os.Getenv("AGENT_MEMORY_SUMMARIZE_URL"),
+			os.Getenv("OPENAI_API_KEY"),
+			os.Getenv("AGENT_MEMORY_SUMMARIZE_MODEL"))
+	default:
+		return Noop{}
+	}
+}
@@ -0,0 +1,62 @@
+package summarize
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNoop(t *testing.T) {
+	got, err := Noop{}.Summarize(context.Background(), "unchanged", 5)
+	if err != nil || got != "unchanged" {
+		t.Errorf("Noop = %q, %v", got, err)
+	}
+}
+
+func TestOpenAISummarizer(t *testing.T) {
+	var req chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer k" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  short brief\n"}}]}`))
+	}))
+	defer srv.Close()
+
+	s := NewOpenAISummarizer(srv.URL, "k", "")
+	got, err := s.Summarize(context.Background(), "a long note", 40)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if got != "short brief" {
+		t.Errorf("got %q, want trimmed brief", got)
+	}
+	if req.Model != "gpt-4o-mini" || req.MaxTokens != 40 || len(req.Messages) != 2 ||
+		!strings.Contains(req.Messages[1].Content, "a long note") {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestOpenAISummarizerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	_, err := NewOpenAISummarizer(srv.URL, "", "m").Summarize(context.Background(), "x", 10)
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("expected a 429 error, got %v", err)
+	}
+}
+
+func TestNewFromEnv_Default(t *testing.T) {
+	t.Setenv("AGENT_MEMORY_SUMMARIZE_PROVIDER", "")
+	if _, ok := NewFromEnv().(Noop); !ok {
+		t.Error("expected the no-op summarizer when no provider is configured")
+	}
+}